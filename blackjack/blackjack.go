@@ -0,0 +1,232 @@
+// This program allows a user to play blackjack at the console against the dealer.
+// It uses the ncurses tcell created by Garrett D'Amore which can be gotten by
+// go get -u github.com/gdamore/tcell
+//
+// Cards are dealt from a multi-deck shoe which is reshuffled when the cut card comes out.
+// The casino rules such as the number of decks, whether the dealer hits soft 17 and the
+// blackjack payout are set with command line flags.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/gdamore/tcell"
+	"github.com/tmasterson/cardgames/display"
+)
+
+// MinBet is the smallest bet and the amount + and - change the bet by.
+// Keeping bets a multiple of 10 means surrender and insurance are always whole chips.
+const minBet = 10
+
+// Define the boxes for easier manipulation
+var dealerArea, playerArea display.Box
+
+// DrawScreen draws the screen putting all the boxes in place
+// s: The screen variable
+// t: The table so the rules can be shown in the title
+// style: The style for the screen
+//
+// Returns: Returns an error if one occurs otherwise nil
+func drawScreen(s tcell.Screen, t *table, style tcell.Style) error {
+	w, h := s.Size()
+	if w < 80 || h < 25 {
+		return errors.New("Screen size must be at least 80 by 25")
+	}
+	title := fmt.Sprintf("Blackjack %s", t.rules)
+	display.PutString(s, w/2-len(title)/2, 0, style, title)
+	var err error
+	dealerArea, err = display.MakeBox(s, "Dealer", 0, 2, 40, 5, style)
+	if err != nil {
+		return err
+	}
+	playerArea, err = display.MakeBox(s, "Player", 0, dealerArea.BotY+2, 40, dealerArea.BotY+3+t.rules.maxHands, style)
+	if err != nil {
+		return err
+	}
+	x := dealerArea.RightX + 3
+	y := dealerArea.TopY
+	help := []string{
+		"Keys:",
+		"Enter or space deals a new round.",
+		"+ and - change the bet.",
+		"H hit, S stand, D double down.",
+		"P split, R surrender.",
+		"I takes insurance, N declines it.",
+		"? shows the basic strategy play.",
+		"Q quits.",
+	}
+	for _, line := range help {
+		display.PutString(s, x, y, style, line)
+		y++
+	}
+	s.Show()
+	return nil
+}
+
+// ShowTable prints the dealer and player hands and the status line.
+// s: Screen variable
+// t: The table being played
+// style: The style for the cards
+func showTable(s tcell.Screen, t *table, style tcell.Style) {
+	w, h := s.Size()
+	blank := strings.Repeat(" ", dealerArea.RightX-dealerArea.LeftX-1)
+	display.PutString(s, dealerArea.LeftX+1, dealerArea.TopY+1, style, blank)
+	display.PutString(s, dealerArea.LeftX+1, dealerArea.TopY+2, style, blank)
+	if len(t.dealer.cards) > 0 {
+		display.PutString(s, dealerArea.LeftX+2, dealerArea.TopY+1, style, t.dealer.String())
+		if t.phase == betting {
+			total, _ := t.dealer.value()
+			display.PutString(s, dealerArea.LeftX+2, dealerArea.TopY+2, style, fmt.Sprintf("Total %d", total))
+		}
+	}
+	for i := 0; i < t.rules.maxHands; i++ {
+		row := playerArea.TopY + 1 + i
+		display.PutString(s, playerArea.LeftX+1, row, style, blank)
+		if i >= len(t.hands) {
+			continue
+		}
+		hd := t.hands[i]
+		mark := " "
+		if i == t.active && t.phase == playing {
+			mark = ">"
+		}
+		total, soft := hd.value()
+		desc := fmt.Sprintf("%d", total)
+		if soft && total < 21 {
+			desc = "soft " + desc
+		}
+		if hd.result != "" {
+			desc = hd.result
+		}
+		display.PutString(s, playerArea.LeftX+1, row, style, fmt.Sprintf("%s%-18s %3d %s", mark, hd.String(), hd.bet, desc))
+	}
+	display.PutString(s, 0, h-2, style, strings.Repeat(" ", w-1))
+	display.PutString(s, 0, h-2, style, t.msg)
+	display.PutString(s, 0, h-1, style, strings.Repeat(" ", w-1))
+	display.PutString(s, 0, h-1, style, fmt.Sprintf("Bankroll %d, Bet %d, Shoe %d", t.bankroll, t.bet, t.shoe.remaining()))
+	s.Show()
+}
+
+// ProcessKey handles the processing of key strokes
+//
+// ev:  The event that that contains the key.
+// t:  The table the key acts on.
+//
+// returns: false if the player wants to quit
+func processKey(ev *tcell.EventKey, t *table) bool {
+	var err error
+	r := ev.Rune()
+	if ev.Key() == tcell.KeyEnter {
+		r = ' '
+	} else if ev.Key() != tcell.KeyRune {
+		return true
+	}
+	switch unicode.ToUpper(r) {
+	case 'Q':
+		return false
+	case ' ':
+		err = t.deal()
+	case '+', '=':
+		t.changeBet(minBet)
+	case '-':
+		t.changeBet(-minBet)
+	case actHit:
+		err = t.hit()
+	case actStand:
+		err = t.stand()
+	case actDouble:
+		err = t.double()
+	case actSplit:
+		err = t.split()
+	case actSurrender:
+		err = t.surrender()
+	case 'I':
+		err = t.insure(true)
+	case 'N':
+		err = t.insure(false)
+	case '?':
+		if t.phase != playing {
+			err = errors.New("no hand to give a hint for")
+			break
+		}
+		a := hint(t.current(), t.dealer.cards[0], t.rules, t.canDouble(), t.canSplit(), t.canSurrender())
+		t.msg = "Basic strategy says " + actionNames[a] + "."
+	}
+	if err != nil {
+		t.msg = err.Error() + "."
+	}
+	return true
+}
+
+// PlayGame is the main function that handles all aspects of the game.
+//
+// s: Screen variable.
+// t: The table to play at.
+// style: The style for the screen.
+//
+// returns: The final bankroll.
+func playGame(s tcell.Screen, t *table, style tcell.Style) int {
+	for {
+		showTable(s, t, style)
+		if t.phase == betting && t.bankroll < minBet {
+			return t.bankroll
+		}
+		ev := s.PollEvent()
+		switch ev := ev.(type) {
+		case *tcell.EventKey:
+			if ev.Key() == tcell.KeyCtrlL {
+				s.Sync()
+			} else if !processKey(ev, t) {
+				return t.bankroll
+			}
+		}
+	}
+}
+
+func main() {
+	r := defaultRules()
+	flag.IntVar(&r.decks, "decks", r.decks, "Number of decks in the shoe 1 to 8")
+	flag.Float64Var(&r.penetration, "pen", r.penetration, "Fraction of the shoe dealt before reshuffling")
+	flag.BoolVar(&r.hitSoft17, "h17", r.hitSoft17, "Dealer hits soft 17")
+	flag.BoolVar(&r.doubleAfterSplit, "das", r.doubleAfterSplit, "Allow doubling after a split")
+	flag.BoolVar(&r.surrender, "surrender", r.surrender, "Allow late surrender")
+	flag.IntVar(&r.maxHands, "hands", r.maxHands, "Most hands allowed after splitting")
+	payout := flag.String("payout", "3:2", "Blackjack payout such as 3:2 or 6:5")
+	bankroll := flag.Int("bankroll", 1000, "Starting bankroll")
+	flag.Parse()
+	var err error
+	r.payNum, r.payDen, err = parsePayout(*payout)
+	if err == nil {
+		err = r.check()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	t := newTable(r, *bankroll, minBet)
+	tcell.SetEncodingFallback(tcell.EncodingFallbackASCII)
+	s, e := tcell.NewScreen()
+	if e != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", e)
+		os.Exit(1)
+	}
+	if e = s.Init(); e != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", e)
+		os.Exit(1)
+	}
+	s.Clear()
+	s.HideCursor()
+	if err := drawScreen(s, t, tcell.StyleDefault); err != nil {
+		s.Fini()
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	final := playGame(s, t, tcell.StyleDefault)
+	s.Fini()
+	fmt.Printf("You finished with %d, a %+d result.\n", final, final-*bankroll)
+}
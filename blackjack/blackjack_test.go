@@ -0,0 +1,61 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell"
+)
+
+func mkTestScreen(t *testing.T, charset string) tcell.SimulationScreen {
+	s := tcell.NewSimulationScreen(charset)
+	if s == nil {
+		t.Fatalf("Failed to get simulation screen")
+	}
+	if e := s.Init(); e != nil {
+		t.Fatalf("Failed to initialize screen: %v", e)
+	}
+	return s
+}
+
+func TestDrawScreen(t *testing.T) {
+	s := mkTestScreen(t, "")
+	defer s.Fini()
+	tb := newTable(defaultRules(), 100, 10)
+	s.SetSize(30, 10)
+	if err := drawScreen(s, tb, tcell.StyleDefault); err == nil {
+		t.Errorf("Expected an error here")
+	}
+	s.SetSize(80, 25)
+	if err := drawScreen(s, tb, tcell.StyleDefault); err != nil {
+		t.Errorf("There should be no errors")
+	}
+	showTable(s, tb, tcell.StyleDefault)
+}
+
+func TestProcessKey(t *testing.T) {
+	tb := newTable(defaultRules(), 100, 10)
+	if !processKey(tcell.NewEventKey(tcell.KeyRune, '+', tcell.ModNone), tb) || tb.bet != 20 {
+		t.Errorf("Expected a bet of 20 but was %d", tb.bet)
+	}
+	processKey(tcell.NewEventKey(tcell.KeyRune, '-', tcell.ModNone), tb)
+	processKey(tcell.NewEventKey(tcell.KeyRune, '-', tcell.ModNone), tb)
+	if tb.bet != minBet {
+		t.Errorf("Expected a bet of %d but was %d", minBet, tb.bet)
+	}
+	stackShoe(tb, "T", "7", "6", "T", "5")
+	processKey(tcell.NewEventKey(tcell.KeyEnter, ' ', tcell.ModNone), tb)
+	if tb.phase != playing || len(tb.hands) != 1 {
+		t.Fatalf("Expected a round to be dealt")
+	}
+	processKey(tcell.NewEventKey(tcell.KeyRune, '?', tcell.ModNone), tb)
+	if tb.msg != "Basic strategy says Hit." {
+		t.Errorf("Expected a hit hint but was %q", tb.msg)
+	}
+	processKey(tcell.NewEventKey(tcell.KeyRune, 'h', tcell.ModNone), tb)
+	if tb.phase != betting || tb.bankroll != 110 {
+		t.Errorf("Expected hitting to 21 to win 10 but bankroll was %d", tb.bankroll)
+	}
+	if processKey(tcell.NewEventKey(tcell.KeyRune, 'q', tcell.ModNone), tb) {
+		t.Errorf("Expected q to quit")
+	}
+}
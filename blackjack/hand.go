@@ -0,0 +1,81 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/tmasterson/cardgames/generic"
+)
+
+// Hand is a single blackjack hand and its wager.
+// bet:  The amount wagered on the hand including any double.
+// doubled:  The hand was doubled down and received its one card.
+// split:  The hand was created by splitting a pair.
+// done:  No more actions can be taken on the hand.
+// surrendered:  The player gave up half the bet.
+type hand struct {
+	cards       []generic.Card
+	bet         int
+	doubled     bool
+	split       bool
+	done        bool
+	surrendered bool
+	result      string
+}
+
+// CardValue returns the blackjack value of a card counting aces as 11.
+func cardValue(c generic.Card) int {
+	switch {
+	case c.Rank == "A":
+		return 11
+	case c.Rvalue >= 10:
+		return 10
+	}
+	return c.Rvalue
+}
+
+// Value returns the best total of the hand and whether it is soft,
+// that is an ace is still being counted as 11.
+func (h *hand) value() (int, bool) {
+	total, aces := 0, 0
+	for _, c := range h.cards {
+		if c.Rank == "A" {
+			aces++
+		}
+		total += cardValue(c)
+	}
+	for total > 21 && aces > 0 {
+		total -= 10
+		aces--
+	}
+	return total, aces > 0
+}
+
+// IsBlackjack tells if the hand is a natural.  Split hands can never be a blackjack.
+func (h *hand) isBlackjack() bool {
+	total, _ := h.value()
+	return len(h.cards) == 2 && total == 21 && !h.split
+}
+
+// IsBust tells if the hand is over 21.
+func (h *hand) isBust() bool {
+	total, _ := h.value()
+	return total > 21
+}
+
+// IsPair tells if the hand is two cards of the same value and may be split.
+func (h *hand) isPair() bool {
+	return len(h.cards) == 2 && cardValue(h.cards[0]) == cardValue(h.cards[1])
+}
+
+// String returns the cards in the hand with face down cards hidden.
+func (h *hand) String() string {
+	var parts []string
+	for _, c := range h.cards {
+		if c.Faceup {
+			parts = append(parts, c.Rank+c.Suit)
+		} else {
+			parts = append(parts, "##")
+		}
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Rules holds the casino rule options for a table.
+// decks:  Number of decks in the shoe.
+// penetration:  Fraction of the shoe dealt before reshuffling.
+// hitSoft17:  The dealer hits a soft 17 when true otherwise stands on all 17s.
+// payNum, payDen:  Blackjack payout as a ratio, 3:2 or 6:5 for example.
+// doubleAfterSplit:  Doubling is allowed on split hands.
+// surrender:  Late surrender is offered.
+// maxHands:  The most hands a player may have after splitting.
+type rules struct {
+	decks            int
+	penetration      float64
+	hitSoft17        bool
+	payNum, payDen   int
+	doubleAfterSplit bool
+	surrender        bool
+	maxHands         int
+}
+
+// DefaultRules returns the rules of a typical six deck shoe game.
+func defaultRules() rules {
+	return rules{
+		decks:            6,
+		penetration:      0.75,
+		hitSoft17:        false,
+		payNum:           3,
+		payDen:           2,
+		doubleAfterSplit: true,
+		surrender:        true,
+		maxHands:         4,
+	}
+}
+
+// ParsePayout converts a payout string such as 3:2 or 6:5 into its numerator and denominator.
+func parsePayout(str string) (int, int, error) {
+	parts := strings.Split(str, ":")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("payout %q must be of the form n:d", str)
+	}
+	num, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("payout %q: %v", str, err)
+	}
+	den, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("payout %q: %v", str, err)
+	}
+	if num < 1 || den < 1 {
+		return 0, 0, fmt.Errorf("payout %q must be positive", str)
+	}
+	return num, den, nil
+}
+
+// Payout returns the winnings on a blackjack for the given bet rounded to the nearest chip with halves rounded up.
+// bet:  The wager on the hand.
+func (r rules) payout(bet int) int {
+	return (bet*r.payNum*2 + r.payDen) / (2 * r.payDen)
+}
+
+// Check makes sure the rules describe a playable table.
+func (r rules) check() error {
+	if r.decks < 1 || r.decks > 8 {
+		return fmt.Errorf("number of decks must be between 1 and 8 was %d", r.decks)
+	}
+	if r.penetration < 0.25 || r.penetration > 0.9 {
+		return fmt.Errorf("penetration must be between 0.25 and 0.9 was %.2f", r.penetration)
+	}
+	if r.maxHands < 1 {
+		return fmt.Errorf("maximum hands must be at least 1 was %d", r.maxHands)
+	}
+	return nil
+}
+
+// String describes the rules for the title line.
+func (r rules) String() string {
+	s17 := "S17"
+	if r.hitSoft17 {
+		s17 = "H17"
+	}
+	return fmt.Sprintf("%d decks, %s, BJ pays %d:%d", r.decks, s17, r.payNum, r.payDen)
+}
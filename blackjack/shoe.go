@@ -0,0 +1,61 @@
+package main
+
+import (
+	"github.com/tmasterson/cardgames/generic"
+)
+
+// Shoe is a multi-deck shoe built on a generic deck.
+// cut:  Position of the cut card.  Once it is reached the shoe is reshuffled before the next round.
+// penetration:  How far into the shoe the cut card is placed as a fraction of the cards.
+type shoe struct {
+	deck        generic.Deck
+	decks       int
+	cut         int
+	penetration float64
+}
+
+// NewShoe returns a shuffled shoe made of the given number of decks.
+// Aces are left as the high card as the hand value code counts them as 1 or 11.
+func newShoe(decks int, penetration float64) *shoe {
+	s := &shoe{deck: generic.NewDecks(decks), decks: decks, penetration: penetration}
+	s.shuffle()
+	return s
+}
+
+// Shuffle gathers all the cards back into the shoe, shuffles them and places the cut card.
+func (s *shoe) shuffle() {
+	for i := range s.deck.Cards {
+		s.deck.Cards[i].Faceup = false
+	}
+	s.deck.Shuffle()
+	s.deck.LastDealt = 0
+	s.deck.AllDealt = false
+	s.cut = int(float64(len(s.deck.Cards)) * s.penetration)
+	if s.cut < 1 || s.cut > len(s.deck.Cards) {
+		s.cut = len(s.deck.Cards)
+	}
+}
+
+// NeedsShuffle tells if the cut card has been reached.
+func (s *shoe) needsShuffle() bool {
+	return s.deck.LastDealt >= s.cut
+}
+
+// Remaining returns the number of cards left in the shoe.
+func (s *shoe) remaining() int {
+	return len(s.deck.Cards) - s.deck.LastDealt
+}
+
+// Draw deals one card from the shoe either face up or face down.
+// If the shoe is somehow emptied in the middle of a round it is reshuffled
+// so that the round can be finished.
+func (s *shoe) draw(up bool) generic.Card {
+	if s.deck.AllDealt {
+		s.shuffle()
+	}
+	n := 0
+	if up {
+		n = 1
+	}
+	return s.deck.Deal(1, n)[0]
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"github.com/tmasterson/cardgames/generic"
+)
+
+// The actions a player can take and that the hint key can suggest.
+const (
+	actHit       = 'H'
+	actStand     = 'S'
+	actDouble    = 'D'
+	actSplit     = 'P'
+	actSurrender = 'R'
+)
+
+// ActionNames gives the words shown for a hint.
+var actionNames = map[rune]string{
+	actHit:       "Hit",
+	actStand:     "Stand",
+	actDouble:    "Double",
+	actSplit:     "Split",
+	actSurrender: "Surrender",
+}
+
+// Hint returns the basic strategy play for a hand against the dealers up card.
+// The tables are the standard multi-deck ones with the hit soft 17 adjustments.
+//
+// h: The hand being played.
+// up: The dealers up card.
+// r: The table rules.
+// canDouble, canSplit, canSurrender: What the table currently allows.
+//
+// returns: One of the act constants.
+func hint(h *hand, up generic.Card, r rules, canDouble, canSplit, canSurrender bool) rune {
+	d := cardValue(up)
+	total, soft := h.value()
+	if canSurrender && !soft {
+		switch {
+		case total == 16 && !h.isPair() && d >= 9:
+			return actSurrender
+		case total == 15 && (d == 10 || (d == 11 && r.hitSoft17)):
+			return actSurrender
+		case total == 17 && d == 11 && r.hitSoft17:
+			return actSurrender
+		}
+	}
+	if canSplit && h.isPair() {
+		if splitPair(cardValue(h.cards[0]), d, r.doubleAfterSplit) {
+			return actSplit
+		}
+	}
+	if soft {
+		return softTotal(total, d, r.hitSoft17, canDouble)
+	}
+	return hardTotal(total, d, r.hitSoft17, canDouble)
+}
+
+// SplitPair tells if a pair of the given card value should be split.
+func splitPair(v, d int, das bool) bool {
+	switch v {
+	case 11, 8:
+		return true
+	case 10, 5:
+		return false
+	case 9:
+		return d <= 9 && d != 7
+	case 7:
+		return d <= 7
+	case 6:
+		if das {
+			return d <= 6
+		}
+		return d >= 3 && d <= 6
+	case 4:
+		return das && (d == 5 || d == 6)
+	case 2, 3:
+		if das {
+			return d <= 7
+		}
+		return d >= 4 && d <= 7
+	}
+	return false
+}
+
+// SoftTotal returns the play for a soft hand.
+func softTotal(total, d int, h17, canDouble bool) rune {
+	double := func(stand bool) rune {
+		if canDouble {
+			return actDouble
+		}
+		if stand {
+			return actStand
+		}
+		return actHit
+	}
+	switch {
+	case total >= 20:
+		return actStand
+	case total == 19:
+		if d == 6 && h17 {
+			return double(true)
+		}
+		return actStand
+	case total == 18:
+		if d <= 6 && (d >= 3 || h17) {
+			return double(true)
+		}
+		if d <= 8 {
+			return actStand
+		}
+		return actHit
+	case total == 17:
+		if d >= 3 && d <= 6 {
+			return double(false)
+		}
+	case total >= 15:
+		if d >= 4 && d <= 6 {
+			return double(false)
+		}
+	default:
+		if d == 5 || d == 6 {
+			return double(false)
+		}
+	}
+	return actHit
+}
+
+// HardTotal returns the play for a hard hand.
+func hardTotal(total, d int, h17, canDouble bool) rune {
+	switch {
+	case total >= 17:
+		return actStand
+	case total >= 13:
+		if d <= 6 {
+			return actStand
+		}
+	case total == 12:
+		if d >= 4 && d <= 6 {
+			return actStand
+		}
+	case total == 11:
+		if canDouble && (d <= 10 || h17) {
+			return actDouble
+		}
+	case total == 10:
+		if canDouble && d <= 9 {
+			return actDouble
+		}
+	case total == 9:
+		if canDouble && d >= 3 && d <= 6 {
+			return actDouble
+		}
+	}
+	return actHit
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/tmasterson/cardgames/generic"
+)
+
+func TestHint(t *testing.T) {
+	s17 := defaultRules()
+	h17 := defaultRules()
+	h17.hitSoft17 = true
+	tests := []struct {
+		cards []string
+		up    string
+		r     rules
+		want  rune
+	}{
+		{[]string{"8", "8"}, "T", s17, actSplit},
+		{[]string{"T", "T"}, "6", s17, actStand},
+		{[]string{"5", "6"}, "A", s17, actHit},
+		{[]string{"5", "6"}, "A", h17, actDouble},
+		{[]string{"T", "6"}, "T", s17, actSurrender},
+		{[]string{"T", "5"}, "A", s17, actHit},
+		{[]string{"T", "5"}, "A", h17, actSurrender},
+		{[]string{"A", "7"}, "2", s17, actStand},
+		{[]string{"A", "7"}, "2", h17, actDouble},
+		{[]string{"A", "7"}, "9", s17, actHit},
+		{[]string{"T", "2"}, "3", s17, actHit},
+		{[]string{"T", "2"}, "4", s17, actStand},
+		{[]string{"9", "9"}, "7", s17, actStand},
+		{[]string{"4", "4"}, "5", s17, actSplit},
+	}
+	for _, tt := range tests {
+		h := hand{}
+		for _, c := range tt.cards {
+			h.cards = append(h.cards, card(c, "S"))
+		}
+		got := hint(&h, card(tt.up, "H"), tt.r, true, true, true)
+		if got != tt.want {
+			t.Errorf("%v against %s expected %s but was %s", tt.cards, tt.up, actionNames[tt.want], actionNames[got])
+		}
+	}
+	h := hand{cards: []generic.Card{card("A", "S"), card("7", "D")}}
+	if got := hint(&h, card("4", "H"), s17, false, false, false); got != actStand {
+		t.Errorf("Expected Stand when doubling is not allowed but was %s", actionNames[got])
+	}
+}
@@ -0,0 +1,324 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+)
+
+// The phases of a round.
+const (
+	betting   = iota // waiting for a new round to be dealt
+	insurance        // dealer shows an ace and insurance is being offered
+	playing          // the player is acting on their hands
+)
+
+// Table holds everything needed to play rounds of blackjack.
+// hands:  The player's hands, more than one after a split.
+// active:  Index of the hand being played.
+// insured:  The amount of the insurance bet.
+// msg:  The outcome of the last action or round for the status line.
+type table struct {
+	rules    rules
+	shoe     *shoe
+	bankroll int
+	bet      int
+	hands    []*hand
+	active   int
+	dealer   hand
+	insured  int
+	phase    int
+	msg      string
+}
+
+// NewTable returns a table ready for the first bet.
+func newTable(r rules, bankroll, bet int) *table {
+	return &table{
+		rules:    r,
+		shoe:     newShoe(r.decks, r.penetration),
+		bankroll: bankroll,
+		bet:      bet,
+		phase:    betting,
+	}
+}
+
+// Current returns the hand being played.
+func (t *table) current() *hand {
+	return t.hands[t.active]
+}
+
+// Deal starts a new round.  The shoe is reshuffled first if the cut card has come out.
+func (t *table) deal() error {
+	if t.phase != betting {
+		return errors.New("finish the current round first")
+	}
+	if t.bet > t.bankroll {
+		return fmt.Errorf("bet of %d is more than your bankroll of %d", t.bet, t.bankroll)
+	}
+	t.msg = ""
+	if t.shoe.needsShuffle() {
+		t.shoe.shuffle()
+		t.msg = "Shoe shuffled. "
+	}
+	h := &hand{bet: t.bet}
+	t.hands = []*hand{h}
+	t.active = 0
+	t.insured = 0
+	t.dealer = hand{}
+	h.cards = append(h.cards, t.shoe.draw(true))
+	t.dealer.cards = append(t.dealer.cards, t.shoe.draw(true))
+	h.cards = append(h.cards, t.shoe.draw(true))
+	t.dealer.cards = append(t.dealer.cards, t.shoe.draw(false))
+	if t.dealer.cards[0].Rank == "A" {
+		t.phase = insurance
+		t.msg += "Insurance? (I/N)"
+		return nil
+	}
+	t.peek()
+	return nil
+}
+
+// Peek checks for dealer and player naturals once insurance is settled.
+func (t *table) peek() {
+	t.phase = playing
+	if cardValue(t.dealer.cards[0]) >= 10 && t.dealer.isBlackjack() {
+		t.settle()
+		return
+	}
+	if t.hands[0].isBlackjack() {
+		t.settle()
+	}
+}
+
+// Insure takes or declines the insurance bet of half the wager.
+// It can only be taken if the bankroll covers it as well as the wager.
+func (t *table) insure(take bool) error {
+	if t.phase != insurance {
+		return errors.New("insurance is not being offered")
+	}
+	if take {
+		if t.committed()+t.bet/2 > t.bankroll {
+			return fmt.Errorf("insurance of %d is more than your bankroll of %d covers", t.bet/2, t.bankroll-t.committed())
+		}
+		t.insured = t.bet / 2
+	}
+	t.msg = ""
+	t.peek()
+	return nil
+}
+
+// CanDouble tells if the current hand may be doubled.
+func (t *table) canDouble() bool {
+	if t.phase != playing {
+		return false
+	}
+	h := t.current()
+	if len(h.cards) != 2 || h.done {
+		return false
+	}
+	if h.split && !t.rules.doubleAfterSplit {
+		return false
+	}
+	return t.committed()+h.bet <= t.bankroll
+}
+
+// CanSplit tells if the current hand may be split.
+func (t *table) canSplit() bool {
+	if t.phase != playing {
+		return false
+	}
+	h := t.current()
+	return h.isPair() && !h.done && len(t.hands) < t.rules.maxHands && t.committed()+h.bet <= t.bankroll
+}
+
+// CanSurrender tells if late surrender is available on the current hand.
+func (t *table) canSurrender() bool {
+	return t.phase == playing && t.rules.surrender && len(t.hands) == 1 && len(t.hands[0].cards) == 2 && !t.hands[0].done
+}
+
+// Committed returns the total amount riding on the table.
+func (t *table) committed() int {
+	total := t.insured
+	for _, h := range t.hands {
+		total += h.bet
+	}
+	return total
+}
+
+// Hit deals a card to the current hand.
+func (t *table) hit() error {
+	if t.phase != playing {
+		return errors.New("no hand to hit")
+	}
+	h := t.current()
+	h.cards = append(h.cards, t.shoe.draw(true))
+	if total, _ := h.value(); total >= 21 {
+		h.done = true
+		t.next()
+	}
+	return nil
+}
+
+// Stand ends play on the current hand.
+func (t *table) stand() error {
+	if t.phase != playing {
+		return errors.New("no hand to stand on")
+	}
+	t.current().done = true
+	t.next()
+	return nil
+}
+
+// Double doubles the bet on the current hand which then gets exactly one card.
+func (t *table) double() error {
+	if !t.canDouble() {
+		return errors.New("you can not double this hand")
+	}
+	h := t.current()
+	h.bet *= 2
+	h.doubled = true
+	h.cards = append(h.cards, t.shoe.draw(true))
+	h.done = true
+	t.next()
+	return nil
+}
+
+// Split splits the current pair into two hands each getting a second card.
+// Split aces get only the one card.
+func (t *table) split() error {
+	if !t.canSplit() {
+		return errors.New("you can not split this hand")
+	}
+	h := t.current()
+	nh := &hand{bet: h.bet, split: true, cards: h.cards[1:2:2]}
+	h.cards = h.cards[:1:1]
+	h.split = true
+	h.cards = append(h.cards, t.shoe.draw(true))
+	nh.cards = append(nh.cards, t.shoe.draw(true))
+	t.hands = append(t.hands[:t.active+1], append([]*hand{nh}, t.hands[t.active+1:]...)...)
+	if h.cards[0].Rank == "A" {
+		h.done = true
+		nh.done = true
+		t.next()
+		return nil
+	}
+	if total, _ := h.value(); total == 21 {
+		h.done = true
+		t.next()
+	}
+	return nil
+}
+
+// Surrender gives up the hand losing half the bet.
+func (t *table) surrender() error {
+	if !t.canSurrender() {
+		return errors.New("surrender is not allowed now")
+	}
+	h := t.current()
+	h.surrendered = true
+	h.done = true
+	t.next()
+	return nil
+}
+
+// Next moves to the next unfinished hand or lets the dealer play if all are done.
+func (t *table) next() {
+	for ; t.active < len(t.hands); t.active++ {
+		if !t.hands[t.active].done {
+			return
+		}
+	}
+	t.active = len(t.hands) - 1
+	t.dealerPlay()
+	t.settle()
+}
+
+// DealerPlay turns the hole card and draws to 17 hitting soft 17 if the rules say so.
+// The dealer does not draw if every hand busted or was surrendered.
+func (t *table) dealerPlay() {
+	t.dealer.cards[1].Faceup = true
+	live := false
+	for _, h := range t.hands {
+		if !h.isBust() && !h.surrendered {
+			live = true
+		}
+	}
+	if !live {
+		return
+	}
+	for {
+		total, soft := t.dealer.value()
+		if total > 17 || (total == 17 && !(soft && t.rules.hitSoft17)) {
+			return
+		}
+		t.dealer.cards = append(t.dealer.cards, t.shoe.draw(true))
+	}
+}
+
+// Settle pays or collects every bet and ends the round.
+// Returns the net amount won or lost.
+func (t *table) settle() int {
+	t.dealer.cards[1].Faceup = true
+	dtotal, _ := t.dealer.value()
+	dbj := t.dealer.isBlackjack()
+	net := 0
+	if t.insured > 0 {
+		if dbj {
+			net += t.insured * 2
+		} else {
+			net -= t.insured
+		}
+	}
+	for _, h := range t.hands {
+		total, _ := h.value()
+		switch {
+		case h.surrendered:
+			net -= h.bet / 2
+			h.result = "surrendered"
+		case h.isBust():
+			net -= h.bet
+			h.result = "bust"
+		case h.isBlackjack() && dbj:
+			h.result = "push"
+		case h.isBlackjack():
+			net += t.rules.payout(h.bet)
+			h.result = "blackjack"
+		case dbj:
+			net -= h.bet
+			h.result = "dealer blackjack"
+		case dtotal > 21 || total > dtotal:
+			net += h.bet
+			h.result = "win"
+		case total == dtotal:
+			h.result = "push"
+		default:
+			net -= h.bet
+			h.result = "lose"
+		}
+		h.done = true
+	}
+	t.bankroll += net
+	t.phase = betting
+	switch {
+	case net > 0:
+		t.msg = fmt.Sprintf("You won %d.", net)
+	case net < 0:
+		t.msg = fmt.Sprintf("You lost %d.", -net)
+	default:
+		t.msg = "Push."
+	}
+	return net
+}
+
+// ChangeBet raises or lowers the bet by the given amount keeping it within the bankroll.
+func (t *table) changeBet(n int) {
+	if t.phase != betting {
+		return
+	}
+	t.bet += n
+	if t.bet < minBet {
+		t.bet = minBet
+	}
+	if t.bet > t.bankroll && t.bankroll >= minBet {
+		t.bet = t.bankroll - t.bankroll%minBet
+	}
+}
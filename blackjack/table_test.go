@@ -0,0 +1,209 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/tmasterson/cardgames/generic"
+)
+
+// Card makes a face down card from a rank and suit for stacking the shoe.
+func card(rank, suit string) generic.Card {
+	values := map[string]int{"2": 2, "3": 3, "4": 4, "5": 5, "6": 6, "7": 7, "8": 8, "9": 9, "T": 10, "J": 11, "Q": 12, "K": 13, "A": 14}
+	color := "black"
+	if suit == "H" || suit == "D" {
+		color = "red"
+	}
+	return generic.NewCard(rank, suit, color, values[rank], 0, false)
+}
+
+// StackShoe sets the next cards to come out of the shoe.
+// Cards are dealt player, dealer, player, dealer then in order.
+func stackShoe(t *table, ranks ...string) {
+	t.shoe.deck.Cards = t.shoe.deck.Cards[:0]
+	for _, r := range ranks {
+		t.shoe.deck.Cards = append(t.shoe.deck.Cards, card(r, "S"))
+	}
+	t.shoe.deck.LastDealt = 0
+	t.shoe.deck.AllDealt = false
+	t.shoe.cut = len(t.shoe.deck.Cards) + 1
+}
+
+func TestHandValue(t *testing.T) {
+	h := hand{cards: []generic.Card{card("A", "S"), card("6", "H")}}
+	total, soft := h.value()
+	if total != 17 || !soft {
+		t.Errorf("Expected 17 soft but was %d %v", total, soft)
+	}
+	h.cards = append(h.cards, card("K", "D"))
+	total, soft = h.value()
+	if total != 17 || soft {
+		t.Errorf("Expected 17 hard but was %d %v", total, soft)
+	}
+	h.cards = []generic.Card{card("A", "S"), card("A", "H"), card("9", "D")}
+	total, _ = h.value()
+	if total != 21 {
+		t.Errorf("Expected 21 but was %d", total)
+	}
+	h.cards = []generic.Card{card("A", "S"), card("Q", "H")}
+	if !h.isBlackjack() {
+		t.Errorf("Expected a blackjack for %s", h.String())
+	}
+	h.split = true
+	if h.isBlackjack() {
+		t.Errorf("A split hand should not be a blackjack")
+	}
+	h.cards = []generic.Card{card("K", "S"), card("T", "H")}
+	if !h.isPair() {
+		t.Errorf("Expected %s to be a pair", h.String())
+	}
+}
+
+func TestShoe(t *testing.T) {
+	s := newShoe(2, 0.5)
+	if s.remaining() != 104 || s.cut != 52 {
+		t.Errorf("Expected 104 cards cut at 52 but was %d %d", s.remaining(), s.cut)
+	}
+	for i := 0; i < 52; i++ {
+		if s.needsShuffle() {
+			t.Fatalf("Should not need a shuffle after %d cards", i)
+		}
+		if c := s.draw(true); !c.Faceup {
+			t.Errorf("Expected a face up card")
+		}
+	}
+	if !s.needsShuffle() {
+		t.Errorf("Expected the cut card to be reached")
+	}
+	s.shuffle()
+	if s.remaining() != 104 || s.needsShuffle() {
+		t.Errorf("Expected a full shoe but had %d", s.remaining())
+	}
+}
+
+func TestParsePayout(t *testing.T) {
+	num, den, err := parsePayout("6:5")
+	if err != nil || num != 6 || den != 5 {
+		t.Errorf("Expected 6 5 but was %d %d %v", num, den, err)
+	}
+	for _, bad := range []string{"3", "a:2", "3:0", "3:2:1"} {
+		if _, _, err := parsePayout(bad); err == nil {
+			t.Errorf("Expected an error for %s", bad)
+		}
+	}
+}
+
+func TestDealerPlay(t *testing.T) {
+	tb := newTable(defaultRules(), 100, 10)
+	stackShoe(tb, "T", "A", "8", "6", "5")
+	if err := tb.deal(); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	tb.insure(false)
+	tb.stand()
+	if len(tb.dealer.cards) != 2 {
+		t.Errorf("Dealer should stand on soft 17 but has %s", tb.dealer.String())
+	}
+	if tb.bankroll != 110 {
+		t.Errorf("Expected 110 but was %d", tb.bankroll)
+	}
+	r := defaultRules()
+	r.hitSoft17 = true
+	tb = newTable(r, 100, 10)
+	stackShoe(tb, "T", "A", "8", "6", "T")
+	tb.deal()
+	tb.insure(false)
+	tb.stand()
+	if len(tb.dealer.cards) != 3 {
+		t.Errorf("Dealer should hit soft 17 but has %s", tb.dealer.String())
+	}
+	if tb.bankroll != 110 {
+		t.Errorf("Expected 110 but was %d", tb.bankroll)
+	}
+}
+
+func TestBlackjackPayout(t *testing.T) {
+	tb := newTable(defaultRules(), 100, 10)
+	stackShoe(tb, "A", "9", "K", "7")
+	tb.deal()
+	if tb.phase != betting || tb.bankroll != 115 {
+		t.Errorf("Expected blackjack paid 15 but bankroll was %d", tb.bankroll)
+	}
+	r := defaultRules()
+	r.payNum, r.payDen = 6, 5
+	tb = newTable(r, 100, 10)
+	stackShoe(tb, "A", "9", "K", "7")
+	tb.deal()
+	if tb.bankroll != 112 {
+		t.Errorf("Expected blackjack paid 12 but bankroll was %d", tb.bankroll)
+	}
+	tb = newTable(defaultRules(), 100, 5)
+	stackShoe(tb, "A", "9", "K", "7")
+	tb.deal()
+	if tb.bankroll != 108 {
+		t.Errorf("Expected blackjack on 5 paid 8 but bankroll was %d", tb.bankroll)
+	}
+}
+
+func TestInsurance(t *testing.T) {
+	tb := newTable(defaultRules(), 100, 10)
+	stackShoe(tb, "9", "A", "9", "K")
+	tb.deal()
+	if tb.phase != insurance {
+		t.Fatalf("Expected insurance to be offered")
+	}
+	if err := tb.hit(); err == nil {
+		t.Errorf("Should not be able to hit before insurance is settled")
+	}
+	tb.insure(true)
+	if tb.phase != betting || tb.bankroll != 100 {
+		t.Errorf("Insurance should cover the loss but bankroll was %d", tb.bankroll)
+	}
+	tb = newTable(defaultRules(), 10, 10)
+	stackShoe(tb, "9", "A", "9", "K")
+	tb.deal()
+	if err := tb.insure(true); err == nil || tb.phase != insurance {
+		t.Errorf("Should not be able to insure with nothing left in the bankroll")
+	}
+	if err := tb.insure(false); err != nil || tb.bankroll != 0 {
+		t.Errorf("Expected bankroll 0 after declining insurance but was %d %v", tb.bankroll, err)
+	}
+}
+
+func TestDoubleSplitSurrender(t *testing.T) {
+	tb := newTable(defaultRules(), 100, 10)
+	stackShoe(tb, "6", "T", "5", "7", "K")
+	tb.deal()
+	if err := tb.double(); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if tb.bankroll != 120 {
+		t.Errorf("Expected doubled win of 20 but bankroll was %d", tb.bankroll)
+	}
+	tb = newTable(defaultRules(), 100, 10)
+	stackShoe(tb, "8", "T", "8", "7", "3", "T", "9")
+	tb.deal()
+	if err := tb.split(); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(tb.hands) != 2 || tb.hands[0].String() != "8S 3S" || tb.hands[1].String() != "8S TS" {
+		t.Fatalf("Split hands were wrong %v", tb.hands)
+	}
+	tb.double()
+	if tb.active != 1 || tb.hands[0].bet != 20 {
+		t.Errorf("Expected to move to the second hand after doubling the first")
+	}
+	if err := tb.surrender(); err == nil {
+		t.Errorf("Should not be able to surrender after a split")
+	}
+	tb.stand()
+	if tb.bankroll != 130 {
+		t.Errorf("Expected to win 30 but bankroll was %d", tb.bankroll)
+	}
+	tb = newTable(defaultRules(), 100, 10)
+	stackShoe(tb, "T", "T", "6", "7")
+	tb.deal()
+	tb.surrender()
+	if tb.bankroll != 95 || tb.hands[0].result != "surrendered" {
+		t.Errorf("Expected to lose 5 but bankroll was %d", tb.bankroll)
+	}
+}
@@ -0,0 +1,138 @@
+// Package bot lets programs play the card games so they can be tested against each other.
+// A Player is asked for each of its moves with a Request and answers with the move as JSON.
+// Players can be written in Go or run as separate programs, in any language, that read
+// requests and write replies as JSON lines on their standard input and output, see Process.
+//
+// Play runs a game with a player in each seat.  A player that is too slow, fails or makes an
+// illegal move has a fault counted against it and a move is made for it.  After too many faults
+// it is put out of the game and its moves are all made for it.  Tournament plays many games and
+// ranks the players.
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// ErrTimeout is the fault for a player that did not answer in time.
+var ErrTimeout = errors.New("no move in time")
+
+// Request asks a player for a move.
+// ID: Numbers the requests so a late reply can be told apart from the reply to a later request
+// Game: The name of the game such as klondike or hearts
+// Seat: The seat to move for
+// Action: The kind of move wanted, which depends on the game
+// State: The game as the seat may see it
+// Legal: The moves allowed, if the game can list them, the reply must be one of them
+type Request struct {
+	ID     int         `json:"id"`
+	Game   string      `json:"game"`
+	Seat   int         `json:"seat"`
+	Action string      `json:"action"`
+	State  interface{} `json:"state"`
+	Legal  interface{} `json:"legal,omitempty"`
+}
+
+// Reply is a player's answer to a request.  Move is in the same form as the legal moves.
+type Reply struct {
+	ID   int             `json:"id"`
+	Move json.RawMessage `json:"move"`
+}
+
+// Player is something that can play a game.
+// Move returns the move for a request.  It should give up when ctx is done.
+type Player interface {
+	Name() string
+	Move(ctx context.Context, req Request) (json.RawMessage, error)
+}
+
+// Game is a game that players can play through Play.
+// Next returns the request for the seat to move next.
+// Apply makes a move for a seat, returning an error if it is not legal.
+// Auto makes a legal move for a seat that could not move itself.
+// Scores gives the result for each seat at the end, the higher the better.
+type Game interface {
+	Name() string
+	Seats() int
+	Over() bool
+	Next() Request
+	Apply(seat int, move json.RawMessage) error
+	Auto(seat int)
+	Scores() []float64
+}
+
+// Options control how strict Play is with the players.
+// Timeout: How long a player has for each move
+// MaxFaults: The faults allowed before a player is put out of the game
+type Options struct {
+	Timeout   time.Duration
+	MaxFaults int
+}
+
+// DefaultOptions are the options used when none are given.
+var DefaultOptions = Options{Timeout: 5 * time.Second, MaxFaults: 3}
+
+// Result is the outcome of a game for each seat.
+// Out: The seat was put out of the game for too many faults
+// Errors: The last fault for each seat or an empty string
+type Result struct {
+	Scores []float64
+	Faults []int
+	Out    []bool
+	Errors []string
+	Moves  int
+}
+
+// ask gets a move from a player, giving up after the timeout.
+func ask(p Player, req Request, timeout time.Duration) (json.RawMessage, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	type answer struct {
+		move json.RawMessage
+		err  error
+	}
+	ch := make(chan answer, 1)
+	go func() {
+		m, err := p.Move(ctx, req)
+		ch <- answer{m, err}
+	}()
+	select {
+	case a := <-ch:
+		return a.move, a.err
+	case <-ctx.Done():
+		return nil, ErrTimeout
+	}
+}
+
+// Play plays a game with a player in each seat and returns the result.
+func Play(g Game, players []Player, o Options) (Result, error) {
+	n := g.Seats()
+	if len(players) != n {
+		return Result{}, errors.New("a player is needed for each seat")
+	}
+	r := Result{Faults: make([]int, n), Out: make([]bool, n), Errors: make([]string, n)}
+	for id := 1; !g.Over(); id++ {
+		req := g.Next()
+		req.ID = id
+		seat := req.Seat
+		r.Moves++
+		if r.Out[seat] {
+			g.Auto(seat)
+			continue
+		}
+		move, err := ask(players[seat], req, o.Timeout)
+		if err == nil {
+			err = g.Apply(seat, move)
+		}
+		if err != nil {
+			r.Faults[seat]++
+			r.Errors[seat] = err.Error()
+			r.Out[seat] = r.Faults[seat] > o.MaxFaults
+			g.Auto(seat)
+		}
+	}
+	r.Scores = g.Scores()
+	return r, nil
+}
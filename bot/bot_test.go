@@ -0,0 +1,136 @@
+package bot
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/tmasterson/cardgames/generic"
+	"github.com/tmasterson/cardgames/solitaire/klondike/engine"
+)
+
+// script is a player that gives the same move every time, after an optional wait.
+type script struct {
+	move string
+	wait time.Duration
+}
+
+func (s script) Name() string {
+	return "script"
+}
+
+func (s script) Move(ctx context.Context, req Request) (json.RawMessage, error) {
+	select {
+	case <-time.After(s.wait):
+	case <-ctx.Done():
+	}
+	return json.RawMessage(s.move), nil
+}
+
+// klondike returns a game dealt the same way every time.
+func klondike() *Klondike {
+	return NewKlondike(engine.Options{Draw: 3, Passes: 3, Build: 'T', Source: generic.NewSeedSource([]byte("bot"))})
+}
+
+func TestPlay(t *testing.T) {
+	o := Options{Timeout: time.Second, MaxFaults: 2}
+	r, err := Play(klondike(), []Player{script{move: `{"from": 20, "to": 0}`}}, o)
+	if err != nil {
+		t.Fatalf("Expected the game played but was %v", err)
+	}
+	if r.Faults[0] != 3 || !r.Out[0] || r.Errors[0] != engine.ErrIllegal.Error() || r.Moves < 4 {
+		t.Errorf("Expected the illegal mover put out after 3 faults but was %+v", r)
+	}
+	o.Timeout = 10 * time.Millisecond
+	r, _ = Play(klondike(), []Player{script{move: `{"deal": true}`, wait: time.Second}}, o)
+	if r.Faults[0] != 3 || r.Errors[0] != ErrTimeout.Error() {
+		t.Errorf("Expected the slow player to time out but was %+v", r)
+	}
+	if _, err := Play(NewHearts(100, nil), []Player{KlondikeComputer{}}, o); err == nil {
+		t.Errorf("Expected an error for too few players")
+	}
+}
+
+func TestKlondikeComputer(t *testing.T) {
+	r, err := Play(klondike(), []Player{KlondikeComputer{}}, DefaultOptions)
+	if err != nil || r.Faults[0] != 0 || r.Scores[0] < 0 || r.Scores[0] > 52 {
+		t.Errorf("Expected a game with no faults but was %+v %v", r, err)
+	}
+}
+
+// TestHelperBot is not a real test.  It is run as a separate program by TestProcess and
+// plays the first legal move, or passes the first three cards at hearts.
+func TestHelperBot(t *testing.T) {
+	if os.Getenv("BOT_HELPER") != "1" {
+		return
+	}
+	in := bufio.NewScanner(os.Stdin)
+	for in.Scan() {
+		var req struct {
+			ID     int               `json:"id"`
+			Action string            `json:"action"`
+			State  json.RawMessage   `json:"state"`
+			Legal  []json.RawMessage `json:"legal"`
+		}
+		if err := json.Unmarshal(in.Bytes(), &req); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		move := json.RawMessage(`null`)
+		if req.Action == "pass" {
+			var st HeartsState
+			json.Unmarshal(req.State, &st)
+			move, _ = json.Marshal(st.Hand[:3])
+		} else if len(req.Legal) > 0 {
+			move = req.Legal[0]
+		}
+		out, _ := json.Marshal(Reply{ID: req.ID, Move: move})
+		fmt.Println(string(out))
+	}
+	os.Exit(0)
+}
+
+// helper starts the test program as a bot.
+func helper(t *testing.T, name string) *Process {
+	os.Setenv("BOT_HELPER", "1")
+	defer os.Unsetenv("BOT_HELPER")
+	p, err := Start(name, os.Args[0], "-test.run=TestHelperBot")
+	if err != nil {
+		t.Fatalf("Expected the bot to start but was %v", err)
+	}
+	return p
+}
+
+func TestProcess(t *testing.T) {
+	p := helper(t, "first")
+	r, err := Play(klondike(), []Player{p}, DefaultOptions)
+	if err != nil || r.Faults[0] != 0 {
+		t.Errorf("Expected the bot to play klondike with no faults but was %+v %v", r, err)
+	}
+	var players []Player
+	for i := 0; i < 4; i++ {
+		players = append(players, p)
+	}
+	r, err = Play(NewHearts(26, nil), players, DefaultOptions)
+	if err != nil {
+		t.Fatalf("Expected the bot to play hearts but was %v", err)
+	}
+	for i, f := range r.Faults {
+		if f != 0 {
+			t.Errorf("Expected no faults but seat %d had %d, %s", i, f, r.Errors[i])
+		}
+	}
+	if err := p.Close(); err != nil {
+		t.Errorf("Expected the bot to stop but was %v", err)
+	}
+	if _, err := p.Move(context.Background(), Request{ID: 1}); err == nil {
+		t.Errorf("Expected an error from a stopped bot")
+	}
+	if _, err := Start("nothing"); err == nil {
+		t.Errorf("Expected an error with no program")
+	}
+}
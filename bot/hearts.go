@@ -0,0 +1,224 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/tmasterson/cardgames/generic"
+	"github.com/tmasterson/cardgames/tricks"
+	"github.com/tmasterson/cardgames/tricks/hearts/engine"
+)
+
+// seatNames are the names of the seats at hearts.
+var seatNames = []string{"South", "West", "North", "East"}
+
+// HeartsState is a game of hearts as one seat may see it.
+// Hand: The seat's own cards
+// Trick: The cards played to the trick so far, starting with the leader's
+// Played: Every card played this hand
+// Tricks: The number of tricks finished this hand
+// Number: The number of hands finished, which sets the pass direction
+type HeartsState struct {
+	Seat   int      `json:"seat"`
+	Hand   []string `json:"hand"`
+	Trick  []string `json:"trick"`
+	Leader int      `json:"leader"`
+	Played []string `json:"played"`
+	Tricks int      `json:"tricks"`
+	Scores []int    `json:"scores"`
+	Number int      `json:"number"`
+	Pass   string   `json:"pass"`
+}
+
+// Hearts is a game of hearts for four players.
+// Requests have the action pass, for which the reply is a list of three cards, or play, for which
+// it is one of the legal cards.  Cards are named by rank and suit such as TH or QS.
+// The score is the negative of the points taken so that the lowest points score highest.
+type Hearts struct {
+	game    *tricks.Game
+	source  generic.Source
+	target  int
+	hand    int
+	passes  []generic.Hand
+	passing bool
+}
+
+// NewHearts returns a game of hearts played until someone reaches target points.
+// The deck is shuffled with source, or math/rand if it is nil.
+func NewHearts(target int, source generic.Source) *Hearts {
+	h := &Hearts{game: tricks.NewGame(seatNames, nil, engine.Rules{}), source: source, target: target}
+	h.newHand()
+	return h
+}
+
+// newHand deals a hand and starts passing if this hand passes.
+func (h *Hearts) newHand() {
+	deck := generic.NewDeck()
+	deck.Source = h.source
+	deck.Shuffle()
+	h.game.Deal(&deck)
+	for i := range h.game.Players {
+		h.game.Players[i].Hand.SortBySuit(generic.AceHigh)
+	}
+	h.passes = make([]generic.Hand, len(h.game.Players))
+	h.passing = engine.PassDirection(h.hand) != tricks.PassNone
+	if !h.passing {
+		h.game.Turn = engine.FindLead(h.game)
+	}
+}
+
+// Name returns hearts.
+func (h *Hearts) Name() string {
+	return "hearts"
+}
+
+// Seats returns 4.
+func (h *Hearts) Seats() int {
+	return len(h.game.Players)
+}
+
+// Over tells if someone has reached the target.
+func (h *Hearts) Over() bool {
+	for _, p := range h.game.Players {
+		if p.Score >= h.target {
+			return true
+		}
+	}
+	return false
+}
+
+// Next asks the first seat still to choose its cards to pass or the seat whose turn it is to play.
+func (h *Hearts) Next() Request {
+	g := h.game
+	seat, action := g.Turn, "play"
+	if h.passing {
+		seat, action = 0, "pass"
+		for h.passes[seat] != nil {
+			seat++
+		}
+	}
+	st := HeartsState{Seat: seat, Hand: g.Players[seat].Hand.Names(), Trick: generic.Hand(g.Trick.Cards).Names(), Leader: g.Trick.Leader,
+		Played: g.Played.Names(), Tricks: g.Tricks, Number: h.hand, Pass: engine.PassNames[engine.PassDirection(h.hand)]}
+	for _, p := range g.Players {
+		st.Scores = append(st.Scores, p.Score)
+	}
+	req := Request{Game: h.Name(), Seat: seat, Action: action, State: st}
+	if !h.passing {
+		req.Legal = g.LegalCards(seat).Names()
+	}
+	return req
+}
+
+// Apply passes or plays the cards for a seat.
+func (h *Hearts) Apply(seat int, move json.RawMessage) error {
+	g := h.game
+	if h.passing {
+		var names []string
+		if err := json.Unmarshal(move, &names); err != nil {
+			return err
+		}
+		cards, err := g.Players[seat].Hand.Named(names)
+		if err != nil {
+			return err
+		}
+		if len(cards) != 3 {
+			return errors.New("three cards must be passed")
+		}
+		h.passes[seat] = cards
+		h.pass()
+		return nil
+	}
+	var name string
+	if err := json.Unmarshal(move, &name); err != nil {
+		return err
+	}
+	cards, err := g.Players[seat].Hand.Named([]string{name})
+	if err != nil {
+		return err
+	}
+	if _, err := g.Play(seat, cards[0]); err != nil {
+		return err
+	}
+	h.endHand()
+	return nil
+}
+
+// pass passes the cards once every seat has chosen them.
+func (h *Hearts) pass() {
+	for _, p := range h.passes {
+		if p == nil {
+			return
+		}
+	}
+	h.game.Pass(h.passes, engine.PassDirection(h.hand))
+	for i := range h.game.Players {
+		h.game.Players[i].Hand.SortBySuit(generic.AceHigh)
+	}
+	h.passing = false
+	h.game.Turn = engine.FindLead(h.game)
+}
+
+// endHand scores a finished hand and deals the next one.
+func (h *Hearts) endHand() {
+	if !h.game.HandOver() {
+		return
+	}
+	h.game.ScoreHand()
+	h.hand++
+	if !h.Over() {
+		h.newHand()
+	}
+}
+
+// Auto passes or plays for a seat as the computer would.
+func (h *Hearts) Auto(seat int) {
+	g := h.game
+	if h.passing {
+		h.passes[seat] = engine.ChoosePass(g.Players[seat].Hand)
+		h.pass()
+		return
+	}
+	g.Play(seat, engine.ChoosePlay(g, seat))
+	h.endHand()
+}
+
+// Scores returns the negative of each seat's points.
+func (h *Hearts) Scores() []float64 {
+	var scores []float64
+	for _, p := range h.game.Players {
+		scores = append(scores, -float64(p.Score))
+	}
+	return scores
+}
+
+// HeartsComputer is a hearts player that plays as the computer players in the hearts program do.
+type HeartsComputer struct{}
+
+// Name returns computer.
+func (HeartsComputer) Name() string {
+	return "computer"
+}
+
+// Move chooses the cards to pass or the card to play.
+func (HeartsComputer) Move(ctx context.Context, req Request) (json.RawMessage, error) {
+	st := req.State.(HeartsState)
+	g := tricks.NewGame(seatNames, nil, engine.Rules{})
+	g.Players[st.Seat].Hand = parseCards(st.Hand)
+	if req.Action == "pass" {
+		return json.Marshal(engine.ChoosePass(g.Players[st.Seat].Hand).Names())
+	}
+	g.Trick = tricks.Trick{Leader: st.Leader, Cards: parseCards(st.Trick)}
+	g.Played = parseCards(st.Played)
+	g.Tricks = st.Tricks
+	g.Turn = st.Seat
+	c := engine.ChoosePlay(g, st.Seat)
+	return json.Marshal(c.Rank + c.Suit)
+}
+
+// parseCards turns card names back into cards.
+func parseCards(names []string) generic.Hand {
+	deck := generic.NewDeck()
+	cards, _ := generic.Hand(deck.Cards).Named(names)
+	return cards
+}
@@ -0,0 +1,56 @@
+package bot
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/tmasterson/cardgames/generic"
+)
+
+func TestHearts(t *testing.T) {
+	h := NewHearts(100, generic.NewSeedSource([]byte("hearts")))
+	req := h.Next()
+	st := req.State.(HeartsState)
+	if req.Action != "pass" || req.Seat != 0 || len(st.Hand) != 13 || st.Pass != "left" || req.Legal != nil {
+		t.Fatalf("Expected south to pass left but was %+v", req)
+	}
+	for _, move := range []string{`["2C"]`, `["XX", "2C", "3C"]`, `"2C"`} {
+		if err := h.Apply(0, json.RawMessage(move)); err == nil {
+			t.Errorf("Expected passing %s to be refused", move)
+		}
+	}
+	pass, _ := json.Marshal(st.Hand[:3])
+	if err := h.Apply(0, pass); err != nil {
+		t.Fatalf("Expected the pass to be made but was %v", err)
+	}
+	if req = h.Next(); req.Seat != 1 || req.Action != "pass" {
+		t.Errorf("Expected west to pass next but was %+v", req)
+	}
+	for s := 1; s < 4; s++ {
+		h.Auto(s)
+	}
+	req = h.Next()
+	st = req.State.(HeartsState)
+	legal := req.Legal.([]string)
+	if req.Action != "play" || len(legal) != 1 || legal[0] != "2C" || st.Hand[0] != "2C" {
+		t.Fatalf("Expected the two of clubs to lead but was %+v", req)
+	}
+	if err := h.Apply(req.Seat, json.RawMessage(`"`+st.Hand[1]+`"`)); err == nil {
+		t.Errorf("Expected %s not to be allowed to lead", st.Hand[1])
+	}
+	players := []Player{HeartsComputer{}, HeartsComputer{}, HeartsComputer{}, HeartsComputer{}}
+	r, err := Play(h, players, DefaultOptions)
+	if err != nil {
+		t.Fatalf("Expected the game played but was %v", err)
+	}
+	total := 0.0
+	for i, sc := range r.Scores {
+		total += sc
+		if r.Faults[i] != 0 {
+			t.Errorf("Expected the computer not to fault but was %s", r.Errors[i])
+		}
+	}
+	if !h.Over() || int(-total)%26 != 0 {
+		t.Errorf("Expected the game over with a multiple of 26 points but was %v", r.Scores)
+	}
+}
@@ -0,0 +1,106 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/tmasterson/cardgames/server"
+	"github.com/tmasterson/cardgames/solitaire/klondike/engine"
+)
+
+// Klondike is a game of klondike for one player.
+// The state sent is a server.State, so face down cards are hidden, and the legal moves are
+// engine.Moves.  The score is the number of cards on the aces.
+// MaxMoves ends a game that is going nowhere.
+type Klondike struct {
+	game     *engine.Game
+	MaxMoves int
+}
+
+// NewKlondike returns a game of klondike dealt with the options.
+func NewKlondike(o engine.Options) *Klondike {
+	return &Klondike{game: engine.NewGame(o), MaxMoves: 1000}
+}
+
+// Name returns klondike.
+func (k *Klondike) Name() string {
+	return "klondike"
+}
+
+// Seats returns 1.
+func (k *Klondike) Seats() int {
+	return 1
+}
+
+// Over tells if the game is won, has run out of passes or moves or is stuck.
+func (k *Klondike) Over() bool {
+	g := k.game
+	return g.IsWon() || g.Over() || g.View().Moves >= k.MaxMoves || len(g.LegalMoves()) == 0
+}
+
+// Next asks for a move.
+func (k *Klondike) Next() Request {
+	return Request{Game: k.Name(), Action: "move", State: server.NewState("", k.game), Legal: k.game.LegalMoves()}
+}
+
+// Apply makes a move given as an engine.Move.
+func (k *Klondike) Apply(seat int, move json.RawMessage) error {
+	var m engine.Move
+	if err := json.Unmarshal(move, &m); err != nil {
+		return err
+	}
+	return k.game.Apply(m)
+}
+
+// Auto plays a card to the aces if it can, otherwise it makes the first legal move.
+func (k *Klondike) Auto(seat int) {
+	if m, ok := k.game.AutoMove(false); ok {
+		k.game.Apply(m)
+		return
+	}
+	if moves := k.game.LegalMoves(); len(moves) > 0 {
+		k.game.Apply(moves[0])
+	}
+}
+
+// Scores returns the number of cards on the aces.
+func (k *Klondike) Scores() []float64 {
+	v := k.game.View()
+	total := 0
+	for i := engine.FirstAce; i < engine.StackCount; i++ {
+		total += len(v.Stacks[i].Cards)
+	}
+	return []float64{float64(total)}
+}
+
+// KlondikeComputer is a simple klondike player that plays to the aces when it can, then moves
+// a card from the waste, then turns over a face down card and otherwise deals.
+type KlondikeComputer struct{}
+
+// Name returns computer.
+func (KlondikeComputer) Name() string {
+	return "computer"
+}
+
+// Move picks a move from the legal moves.
+func (KlondikeComputer) Move(ctx context.Context, req Request) (json.RawMessage, error) {
+	st := req.State.(server.State)
+	legal := req.Legal.([]engine.Move)
+	best, rank := legal[len(legal)-1], 0
+	for _, m := range legal {
+		r := 0
+		switch {
+		case m.Deal:
+		case m.To >= engine.FirstAce:
+			r = 3
+		case m.From == engine.Waste:
+			r = 2
+		case m.From < engine.Columns && len(st.Stacks[m.From]) > m.Count && st.Stacks[m.From][len(st.Stacks[m.From])-m.Count-1] == "??":
+			r = 1
+		}
+		if r > rank {
+			best, rank = m, r
+		}
+	}
+	return json.Marshal(best)
+}
@@ -0,0 +1,121 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// pending is how many replies can wait for Move to read them.
+const pending = 16
+
+// closeWait is how long Close waits for a program to stop before killing it.
+var closeWait = 5 * time.Second
+
+// Process is a player that is a separate program.  Each request is written to its standard input
+// as a line of JSON and it writes a Reply with the same id as a line on its standard output.
+// Replies with the id of an earlier request, which came too late, are thrown away.
+// Anything the program writes to its standard error is passed through to ours.
+type Process struct {
+	name    string
+	cmd     *exec.Cmd
+	in      io.WriteCloser
+	enc     *json.Encoder
+	replies chan Reply
+	mu      sync.Mutex
+	err     error
+}
+
+// Start runs a program as a player.
+// name: The name of the player in results
+// command: The program and its arguments
+func Start(name string, command ...string) (*Process, error) {
+	if len(command) == 0 {
+		return nil, errors.New("no program given for " + name)
+	}
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Stderr = os.Stderr
+	in, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	p := &Process{name: name, cmd: cmd, in: in, enc: json.NewEncoder(in), replies: make(chan Reply, pending)}
+	go p.read(out)
+	return p, nil
+}
+
+// read passes the replies from the program to Move until its output closes.
+func (p *Process) read(out io.Reader) {
+	dec := json.NewDecoder(out)
+	for {
+		var r Reply
+		if err := dec.Decode(&r); err != nil {
+			p.mu.Lock()
+			if err == io.EOF {
+				err = errors.New(p.name + " has stopped")
+			}
+			p.err = err
+			p.mu.Unlock()
+			close(p.replies)
+			return
+		}
+		select {
+		case p.replies <- r:
+		default:
+			// only late replies pile up, so throwing one away is no loss
+		}
+	}
+}
+
+// Name returns the name of the player.
+func (p *Process) Name() string {
+	return p.name
+}
+
+// Move sends a request to the program and waits for its reply.
+func (p *Process) Move(ctx context.Context, req Request) (json.RawMessage, error) {
+	if err := p.enc.Encode(req); err != nil {
+		return nil, err
+	}
+	for {
+		select {
+		case r, ok := <-p.replies:
+			if !ok {
+				p.mu.Lock()
+				defer p.mu.Unlock()
+				return nil, p.err
+			}
+			if r.ID == req.ID {
+				return r.Move, nil
+			}
+		case <-ctx.Done():
+			return nil, ErrTimeout
+		}
+	}
+}
+
+// Close stops the program, killing it if it does not stop when its input is closed.
+func (p *Process) Close() error {
+	p.in.Close()
+	done := make(chan error, 1)
+	go func() { done <- p.cmd.Wait() }()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(closeWait):
+		p.cmd.Process.Kill()
+		return <-done
+	}
+}
@@ -0,0 +1,116 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+)
+
+// Standing is how a player did in a tournament.
+// Wins: The games in which it had the best score, a shared best counts as a win for each player
+// Total: The sum of its scores
+// Out: The games it was put out of for too many faults
+type Standing struct {
+	Name   string
+	Games  int
+	Wins   int
+	Total  float64
+	Faults int
+	Out    int
+}
+
+// Average returns the player's average score.
+func (s Standing) Average() float64 {
+	if s.Games == 0 {
+		return 0
+	}
+	return s.Total / float64(s.Games)
+}
+
+// Tournament plays games between the players and returns their standings, best first.
+// newGame returns game number i and must give the same deal each time it is called with i.
+// A game for one player is played by every player so they all face the same deals.  In a game
+// with more seats the players take turns to sit out and move round the seats from game to game.
+func Tournament(players []Player, games int, newGame func(i int) Game, o Options) ([]Standing, error) {
+	if len(players) == 0 {
+		return nil, errors.New("there are no players")
+	}
+	standings := make([]Standing, len(players))
+	for i, p := range players {
+		standings[i].Name = p.Name()
+	}
+	seats := newGame(0).Seats()
+	if len(players) < seats {
+		return nil, fmt.Errorf("%d players are needed", seats)
+	}
+	// each table is the number of the game followed by the players in its seats
+	var tables [][]int
+	for i := 0; i < games; i++ {
+		if seats == 1 {
+			for j := range players {
+				tables = append(tables, []int{i, j})
+			}
+			continue
+		}
+		table := []int{i}
+		for s := 0; s < seats; s++ {
+			table = append(table, (i+s)%len(players))
+		}
+		tables = append(tables, table)
+	}
+	// a game for one seat is played once by each player, so the best score is over every
+	// table playing the same game number
+	results := make([]Result, len(tables))
+	best := make(map[int]float64)
+	for t, table := range tables {
+		g := newGame(table[0])
+		var at []Player
+		for _, j := range table[1:] {
+			at = append(at, players[j])
+		}
+		r, err := Play(g, at, o)
+		if err != nil {
+			return nil, err
+		}
+		results[t] = r
+		for _, sc := range r.Scores {
+			if b, ok := best[table[0]]; !ok || sc > b {
+				best[table[0]] = sc
+			}
+		}
+	}
+	for t, table := range tables {
+		r := results[t]
+		for s, j := range table[1:] {
+			st := &standings[j]
+			st.Games++
+			st.Total += r.Scores[s]
+			st.Faults += r.Faults[s]
+			if r.Out[s] {
+				st.Out++
+			}
+			if r.Scores[s] == best[table[0]] {
+				st.Wins++
+			}
+		}
+	}
+	sort.SliceStable(standings, func(i, j int) bool {
+		if standings[i].Wins != standings[j].Wins {
+			return standings[i].Wins > standings[j].Wins
+		}
+		return standings[i].Average() > standings[j].Average()
+	})
+	return standings, nil
+}
+
+// WriteStandings writes the standings as a table.
+func WriteStandings(w io.Writer, standings []Standing) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "Rank\tPlayer\tGames\tWins\tAverage\tFaults\tOut\t")
+	for i, s := range standings {
+		fmt.Fprintf(tw, "%d\t%s\t%d\t%d\t%.1f\t%d\t%d\t\n", i+1, s.Name, s.Games, s.Wins, s.Average(), s.Faults, s.Out)
+	}
+	return tw.Flush()
+}
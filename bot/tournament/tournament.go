@@ -0,0 +1,101 @@
+// This program runs a tournament between bots and prints a table ranking them.
+// Each argument is a bot, either computer for the built in player or a program to run, such as
+//
+//	tournament -game hearts -games 20 computer "python3 mybot.py" ./otherbot
+//
+// A bot can be given a name with name=program.  See the bot package for what the programs are sent
+// and how they answer.  The deals come from -seed so a tournament can be run again the same way.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/tmasterson/cardgames/bot"
+	"github.com/tmasterson/cardgames/generic"
+	"github.com/tmasterson/cardgames/solitaire/klondike/engine"
+)
+
+// named gives the built in player another name.
+type named struct {
+	bot.Player
+	name string
+}
+
+// Name returns the player's name.
+func (n named) Name() string {
+	return n.name
+}
+
+// source returns the random numbers for game i of a tournament.
+func source(seed string, i int) generic.Source {
+	return generic.NewSeedSource([]byte(fmt.Sprintf("%s/%d", seed, i)))
+}
+
+// stop closes the bot programs that were started, waiting for them to finish.
+func stop(started []*bot.Process) {
+	for _, p := range started {
+		if err := p.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", p.Name(), err)
+		}
+	}
+}
+
+func main() {
+	gameptr := flag.String("game", "klondike", "The game to play, klondike or hearts")
+	gamesptr := flag.Int("games", 10, "The number of games to play")
+	timeoutptr := flag.Duration("timeout", bot.DefaultOptions.Timeout, "The time allowed for each move")
+	faultsptr := flag.Int("faults", bot.DefaultOptions.MaxFaults, "The faults allowed before a bot is put out of a game")
+	drawptr := flag.Int("draw", 3, "The number of cards dealt at a time in klondike")
+	targetptr := flag.Int("target", 100, "Score that ends a game of hearts")
+	seedptr := flag.String("seed", fmt.Sprint(time.Now().UnixNano()), "The seed for the deals")
+	flag.Parse()
+	var newGame func(i int) bot.Game
+	var computer bot.Player
+	switch *gameptr {
+	case "klondike":
+		newGame = func(i int) bot.Game {
+			return bot.NewKlondike(engine.Options{Draw: *drawptr, Passes: *drawptr, Build: 'T', Source: source(*seedptr, i)})
+		}
+		computer = bot.KlondikeComputer{}
+	case "hearts":
+		newGame = func(i int) bot.Game {
+			return bot.NewHearts(*targetptr, source(*seedptr, i))
+		}
+		computer = bot.HeartsComputer{}
+	default:
+		fmt.Fprintf(os.Stderr, "There is no game %s, use klondike or hearts\n", *gameptr)
+		os.Exit(1)
+	}
+	var players []bot.Player
+	var started []*bot.Process
+	for _, arg := range flag.Args() {
+		name, command := arg, arg
+		if i := strings.Index(arg, "="); i > 0 {
+			name, command = arg[:i], arg[i+1:]
+		}
+		if command == "computer" {
+			players = append(players, named{computer, name})
+			continue
+		}
+		p, err := bot.Start(name, strings.Fields(command)...)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+			stop(started)
+			os.Exit(1)
+		}
+		started = append(started, p)
+		players = append(players, p)
+	}
+	standings, err := bot.Tournament(players, *gamesptr, newGame, bot.Options{Timeout: *timeoutptr, MaxFaults: *faultsptr})
+	stop(started)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("%s, %d games, seed %s\n\n", *gameptr, *gamesptr, *seedptr)
+	bot.WriteStandings(os.Stdout, standings)
+}
@@ -0,0 +1,50 @@
+package bot
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/tmasterson/cardgames/generic"
+)
+
+// dealer is a klondike player that only ever deals.
+type dealer struct{ script }
+
+func (dealer) Name() string {
+	return "dealer"
+}
+
+func TestTournament(t *testing.T) {
+	players := []Player{dealer{script{move: `{"deal": true}`}}, KlondikeComputer{}}
+	standings, err := Tournament(players, 3, func(i int) Game { return klondike() }, DefaultOptions)
+	if err != nil {
+		t.Fatalf("Expected the tournament played but was %v", err)
+	}
+	if len(standings) != 2 || standings[0].Name != "computer" || standings[0].Games != 3 || standings[1].Games != 3 || standings[0].Wins != 3 || standings[1].Wins != 0 {
+		t.Errorf("Expected the computer to beat the dealer but was %+v", standings)
+	}
+	var buf bytes.Buffer
+	WriteStandings(&buf, standings)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 || !strings.Contains(lines[0], "Rank") || !strings.Contains(lines[1], "computer") {
+		t.Errorf("Expected a table of standings but was\n%s", buf.String())
+	}
+	var five []Player
+	for i := 0; i < 5; i++ {
+		five = append(five, HeartsComputer{})
+	}
+	newHearts := func(i int) Game { return NewHearts(26, generic.NewSeedSource([]byte{byte(i)})) }
+	standings, err = Tournament(five, 5, newHearts, DefaultOptions)
+	if err != nil {
+		t.Fatalf("Expected the tournament played but was %v", err)
+	}
+	for _, s := range standings {
+		if s.Games != 4 {
+			t.Errorf("Expected each player to sit out one game in five but was %+v", s)
+		}
+	}
+	if _, err := Tournament(five[:3], 1, newHearts, DefaultOptions); err == nil {
+		t.Errorf("Expected an error with too few players for hearts")
+	}
+}
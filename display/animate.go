@@ -0,0 +1,136 @@
+package display
+
+import (
+	"time"
+
+	"github.com/gdamore/tcell"
+)
+
+// Point is a position on the screen.
+type Point struct {
+	X, Y int
+}
+
+// Animator moves a card's text across the screen from one pile to another.
+// The card is drawn by the game's own loop so that nothing else draws on the screen at the same
+// time.  While a card moves a goroutine posts a tcell.EventInterrupt carrying a Frame every Delay.
+// The game passes the data of each interrupt to Step, which moves the card on, and calls Draw
+// after drawing the board.  When the card arrives Step returns the data given to Move so the game
+// knows to finish the move.  A game that is paused stops calling Step and the card waits.
+// Frames is how many steps a move takes and Delay the time between them.  With no frames or
+// no delay the card arrives on a Frame posted at once so animation can be turned off.
+type Animator struct {
+	Frames int
+	Delay  time.Duration
+	m      *motion
+}
+
+// motion is a card on its way.
+// step: The index in path of where the card is
+// done: Closed to stop posting frames
+type motion struct {
+	str   string
+	style tcell.Style
+	path  []Point
+	step  int
+	data  interface{}
+	done  chan struct{}
+}
+
+// Frame is the data of the interrupts posted while a card is moving.
+type Frame struct {
+	m *motion
+}
+
+// NewAnimator returns an Animator with the given number of frames and delay between them.
+func NewAnimator(frames int, delay time.Duration) *Animator {
+	return &Animator{Frames: frames, Delay: delay}
+}
+
+// Path returns the points a card passes through going from one point to another in the
+// given number of frames.  The last point is always the destination.
+func Path(from, to Point, frames int) []Point {
+	if frames < 1 {
+		frames = 1
+	}
+	path := make([]Point, frames)
+	for i := 1; i <= frames; i++ {
+		path[i-1] = Point{
+			X: from.X + (to.X-from.X)*i/frames,
+			Y: from.Y + (to.Y-from.Y)*i/frames,
+		}
+	}
+	return path
+}
+
+// Move starts a card moving and returns at once, stopping any card already moving.
+// s: The screen variable
+// str: The text of the card
+// from, to: Where the card starts and finishes
+// style: The style for the card
+// data: Returned by Step when the card arrives
+func (a *Animator) Move(s tcell.Screen, str string, from, to Point, style tcell.Style, data interface{}) {
+	if a == nil || a.Frames < 1 || a.Delay <= 0 {
+		m := &motion{path: []Point{to}, data: data}
+		if a != nil {
+			a.Stop()
+			a.m = m
+		}
+		go s.PostEvent(tcell.NewEventInterrupt(Frame{m}))
+		return
+	}
+	a.Stop()
+	m := &motion{str: str, style: style, path: Path(from, to, a.Frames), data: data, done: make(chan struct{})}
+	a.m = m
+	go func() {
+		t := time.NewTicker(a.Delay)
+		defer t.Stop()
+		for {
+			select {
+			case <-t.C:
+				s.PostEvent(tcell.NewEventInterrupt(Frame{m}))
+			case <-m.done:
+				return
+			}
+		}
+	}()
+}
+
+// Step moves the card on when data is a Frame for it.  Other data and frames left over from a
+// card that was stopped are ignored.
+//
+// returns: The data given to Move and true when the card has arrived.
+func (a *Animator) Step(data interface{}) (interface{}, bool) {
+	f, ok := data.(Frame)
+	if !ok || f.m == nil || (a != nil && f.m != a.m) || (a == nil && f.m.done != nil) {
+		return nil, false
+	}
+	f.m.step++
+	if f.m.step < len(f.m.path) {
+		return nil, false
+	}
+	if a != nil {
+		a.Stop()
+	}
+	return f.m.data, true
+}
+
+// Draw draws the card where it has got to, if one is moving.
+func (a *Animator) Draw(s tcell.Screen) {
+	if a == nil || a.m == nil || a.m.step >= len(a.m.path) {
+		return
+	}
+	p := a.m.path[a.m.step]
+	PutString(s, p.X, p.Y, a.m.style, a.m.str)
+}
+
+// Stop stops the card moving without it arriving.
+func (a *Animator) Stop() {
+	if a == nil || a.m == nil {
+		return
+	}
+	if a.m.done != nil {
+		close(a.m.done)
+	}
+	a.m = nil
+}
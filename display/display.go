@@ -0,0 +1,74 @@
+// Package display holds the tcell drawing helpers shared by all of the console card games.
+// It uses the tcell package created by Garrett D'Amore which can be gotten by
+// go get -u github.com/gdamore/tcell
+package display
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell"
+	"github.com/mattn/go-runewidth"
+)
+
+// Box defines the coordinates of a box and the area where cards will
+// be printed on the single card groups i.e waste and aces.
+type Box struct {
+	Title                     string
+	LeftX, RightX, TopY, BotY int
+	CardArea                  int
+	Style                     tcell.Style
+}
+
+// PutString prints a string in a given area of the screen in a given style
+// s: The screen variable
+// x, y: Cooridinates of the left end of the string.
+// style: The string style.
+// str: The string to be printed.
+func PutString(s tcell.Screen, x, y int, style tcell.Style, str string) {
+	for _, c := range str {
+		w := runewidth.RuneWidth(c)
+		s.SetContent(x, y, c, nil, style)
+		x += w
+	}
+}
+
+// MakeBox draws a box on the screen.
+// s: The screen variable
+// title: A string that defines a title for the box.  Can be blank or null
+// leftX, topY, rightX, botY: The coordinates for the corners of the box
+// style: The style for the box
+//
+// If this were to be made more generic it should be set so that you can do it without borders.
+func MakeBox(s tcell.Screen, title string, leftX, topY, rightX, botY int, style tcell.Style) (Box, error) {
+	center := (rightX - leftX) / 2
+	var b Box
+	if len(title) > rightX-leftX-2 {
+		return b, fmt.Errorf("title length %d must not exceed right-left-2 %d", len(title), rightX-leftX-2)
+	}
+	b.Title = title
+	b.LeftX = leftX
+	b.TopY = topY
+	b.RightX = rightX
+	b.BotY = botY
+	b.CardArea = b.LeftX + center - 1
+	b.Style = style
+	// draw the box
+	for col := b.LeftX; col <= b.RightX; col++ {
+		s.SetContent(col, b.TopY, tcell.RuneHLine, nil, b.Style)
+		s.SetContent(col, b.BotY, tcell.RuneHLine, nil, b.Style)
+	}
+	for row := b.TopY; row <= b.BotY; row++ {
+		s.SetContent(b.LeftX, row, tcell.RuneVLine, nil, b.Style)
+		s.SetContent(b.RightX, row, tcell.RuneVLine, nil, b.Style)
+	}
+	s.SetContent(b.LeftX, b.TopY, tcell.RuneULCorner, nil, style)
+	s.SetContent(b.LeftX, b.BotY, tcell.RuneLLCorner, nil, style)
+	s.SetContent(b.RightX, b.TopY, tcell.RuneURCorner, nil, style)
+	s.SetContent(b.RightX, b.BotY, tcell.RuneLRCorner, nil, style)
+	titlepos := b.LeftX + center - len(title)/2
+	if titlepos == b.LeftX {
+		titlepos++
+	}
+	PutString(s, titlepos, b.TopY, style, title)
+	return b, nil
+}
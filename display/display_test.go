@@ -0,0 +1,310 @@
+package display
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell"
+	"github.com/tmasterson/cardgames/generic"
+)
+
+func mkTestScreen(t *testing.T, charset string) tcell.SimulationScreen {
+	s := tcell.NewSimulationScreen(charset)
+	if s == nil {
+		t.Fatalf("Failed to get simulation screen")
+	}
+	if e := s.Init(); e != nil {
+		t.Fatalf("Failed to initialize screen: %v", e)
+	}
+	return s
+}
+
+func TestPutString(t *testing.T) {
+	s := mkTestScreen(t, "")
+	defer s.Fini()
+	PutString(s, 1, 1, tcell.StyleDefault, "test")
+	s.Show()
+	txt, x, y := s.GetContents()
+	if len(txt) != x*y {
+		t.Errorf("Incorrect size of content: should be %d but was %d", x*y, len(txt))
+	}
+	var txt2 []byte
+	for i := 81; i < 85; i++ {
+		txt2 = append(txt2, txt[i].Bytes[0])
+	}
+	if string(txt2) != "test" {
+		t.Errorf("Incorrect string should be test but was %s", txt2)
+	}
+}
+
+func TestMakeBox(t *testing.T) {
+	s := mkTestScreen(t, "")
+	defer s.Fini()
+	box, err := MakeBox(s, "test", 1, 1, 6, 4, tcell.StyleDefault)
+	if err == nil {
+		t.Fatalf("Should get and error here")
+	}
+	box, err = MakeBox(s, "test", 1, 1, 8, 4, tcell.StyleDefault)
+	if err != nil {
+		t.Fatalf("Should not get an error here %s", err)
+	}
+	s.Show()
+	txt, x, y := s.GetContents()
+	if len(txt) != x*y {
+		t.Errorf("Incorrect size of content: should be %d but was %d", x*y, len(txt))
+	}
+	if box.LeftX != 1 || box.RightX != 8 || box.TopY != 1 || box.BotY != 4 || box.Style != tcell.StyleDefault || box.Title != "test" || box.CardArea != 3 {
+		t.Errorf("expected 1, 8, 1, 4, %v, test, 3 but was %d %d %d %d %v %s %d", tcell.StyleDefault, box.LeftX, box.RightX, box.TopY, box.BotY, box.Style, box.Title, box.CardArea)
+	}
+	w, _ := s.Size()
+	topline := box.TopY * w
+	centerpos := (box.RightX-box.LeftX)/2 + topline + box.LeftX
+	titlepos := centerpos - len("test")/2
+	if txt[titlepos].Runes[0] != 't' {
+		t.Errorf("expected t but was %c at %d %d", txt[titlepos].Runes[0], titlepos, centerpos)
+	}
+	if txt[topline+box.LeftX].Runes[0] != tcell.RuneULCorner {
+		t.Errorf("Expected %c but got %c", tcell.RuneULCorner, txt[topline+box.LeftX].Runes[0])
+	}
+	if txt[topline+box.LeftX+box.RightX-1].Runes[0] != tcell.RuneURCorner {
+		t.Errorf("Expected %c but got %c", tcell.RuneURCorner, txt[topline+box.LeftX+box.RightX-1].Runes[0])
+	}
+	botline := box.BotY * w
+	if txt[botline+box.LeftX].Runes[0] != tcell.RuneLLCorner {
+		t.Errorf("Expected %c but got %c", tcell.RuneLLCorner, txt[botline+box.LeftX].Runes[0])
+	}
+	if txt[botline+box.LeftX+box.RightX-1].Runes[0] != tcell.RuneLRCorner {
+		t.Errorf("Expected %c but got %c", tcell.RuneLRCorner, txt[botline+box.LeftX+box.RightX-1].Runes[0])
+	}
+}
+
+func TestPath(t *testing.T) {
+	path := Path(Point{0, 0}, Point{10, 5}, 5)
+	if len(path) != 5 {
+		t.Fatalf("Expected 5 points but was %d", len(path))
+	}
+	if path[0] != (Point{2, 1}) || path[4] != (Point{10, 5}) {
+		t.Errorf("Expected 2,1 first and 10,5 last but was %v %v", path[0], path[4])
+	}
+	path = Path(Point{3, 3}, Point{1, 1}, 0)
+	if len(path) != 1 || path[0] != (Point{1, 1}) {
+		t.Errorf("Expected just the destination but was %v", path)
+	}
+}
+
+func TestAnimatorMove(t *testing.T) {
+	s := mkTestScreen(t, "")
+	defer s.Fini()
+	a := NewAnimator(4, time.Millisecond)
+	a.Move(s, "AS", Point{0, 2}, Point{8, 2}, tcell.StyleDefault, 7)
+	a.Draw(s)
+	if r, _, _, _ := s.GetContent(2, 2); r != 'A' {
+		t.Errorf("Expected the card drawn at its first step but was %c", r)
+	}
+	if _, ok := a.Step(Tick{}); ok {
+		t.Errorf("Expected data that is not a frame to be ignored")
+	}
+	frames := 0
+	for {
+		ev, ok := s.PollEvent().(*tcell.EventInterrupt)
+		if !ok {
+			t.Fatalf("Expected an interrupt but was %v", ev)
+		}
+		frames++
+		if data, ok := a.Step(ev.Data()); ok {
+			if data != 7 {
+				t.Errorf("Expected 7 when the card arrived but was %v", data)
+			}
+			break
+		}
+	}
+	if frames != 4 {
+		t.Errorf("Expected the card to arrive after 4 frames but was %d", frames)
+	}
+	a.Move(s, "AS", Point{0, 2}, Point{8, 2}, tcell.StyleDefault, 7)
+	ev := s.PollEvent().(*tcell.EventInterrupt)
+	a.Stop()
+	if _, ok := a.Step(ev.Data()); ok {
+		t.Errorf("Expected no more steps once the card is stopped")
+	}
+	var none *Animator
+	none.Move(s, "AS", Point{0, 2}, Point{8, 2}, tcell.StyleDefault, "done")
+	for {
+		ev, ok := s.PollEvent().(*tcell.EventInterrupt)
+		if !ok {
+			t.Fatalf("Expected an interrupt but was %v", ev)
+		}
+		if data, ok := none.Step(ev.Data()); ok {
+			if data != "done" {
+				t.Errorf("Expected the card to arrive at once without an animator but was %v", data)
+			}
+			break
+		}
+	}
+}
+
+func TestStartTicker(t *testing.T) {
+	s := mkTestScreen(t, "")
+	defer s.Fini()
+	stop := StartTicker(s, time.Millisecond)
+	ev, ok := s.PollEvent().(*tcell.EventInterrupt)
+	stop()
+	if !ok {
+		t.Fatalf("Expected an interrupt")
+	}
+	if _, ok := ev.Data().(Tick); !ok {
+		t.Errorf("Expected a Tick but was %v", ev.Data())
+	}
+}
+
+func TestKeymap(t *testing.T) {
+	defaults := Keymap{"deal": {"Space"}, "quit": {"q"}, "left": {"Left", "h"}}
+	k, err := ParseKeymap(strings.NewReader("# my keys\n\nquit = x Esc\n"), defaults)
+	if err != nil {
+		t.Fatalf("Expected no error but was %v", err)
+	}
+	for _, c := range []struct {
+		ev     *tcell.EventKey
+		action string
+	}{
+		{tcell.NewEventKey(tcell.KeyRune, 'X', tcell.ModNone), "quit"},
+		{tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone), "quit"},
+		{tcell.NewEventKey(tcell.KeyRune, ' ', tcell.ModNone), "deal"},
+		{tcell.NewEventKey(tcell.KeyLeft, 0, tcell.ModNone), "left"},
+	} {
+		if !k.Is(c.ev, c.action) {
+			t.Errorf("Expected %s to be %s", KeyName(c.ev), c.action)
+		}
+	}
+	if k.Is(tcell.NewEventKey(tcell.KeyRune, 'q', tcell.ModNone), "quit") {
+		t.Errorf("Expected q to do nothing")
+	}
+	if s := k.Keys("left"); s != "Left/H" {
+		t.Errorf("Expected Left/H but was %s", s)
+	}
+	if _, err := ParseKeymap(strings.NewReader("jump = j\n"), defaults); err == nil {
+		t.Errorf("Expected an unknown action to be an error")
+	}
+	if _, err := ParseKeymap(strings.NewReader("quit\n"), defaults); err == nil {
+		t.Errorf("Expected a line without = to be an error")
+	}
+	if _, err := ParseKeymap(strings.NewReader("quit = H\n"), defaults); err == nil {
+		t.Errorf("Expected a key bound to quit and left to be an error")
+	}
+	shared := Keymap{"pick": {"Enter"}, "ace": {"Enter"}, "quit": {"q"}}
+	if _, err := ParseKeymap(strings.NewReader("pick = Enter Space\nace = space enter\n"), shared); err != nil {
+		t.Errorf("Expected actions that share a key by default to share others but was %v", err)
+	}
+	k, err = LoadKeymap("no such file", defaults)
+	if err != nil || k.Keys("quit") != "Q" {
+		t.Errorf("Expected the defaults without a file but was %v %v", k, err)
+	}
+}
+
+func TestKeymapIs(t *testing.T) {
+	k := Keymap{"pick": {"Enter"}, "ace": {"Enter"}}
+	ev := tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone)
+	if !k.Is(ev, "pick") || !k.Is(ev, "ace") || k.Is(ev, "quit") {
+		t.Errorf("Expected Enter to be pick and ace only")
+	}
+}
+
+func TestParseStyle(t *testing.T) {
+	st, err := ParseStyle("Red on #ffffff bold")
+	if err != nil {
+		t.Fatalf("Expected no error but was %v", err)
+	}
+	fg, bg, attr := st.Decompose()
+	if fg != tcell.ColorRed || bg != tcell.NewHexColor(0xffffff) || attr&tcell.AttrBold == 0 {
+		t.Errorf("Expected bold red on white but was %v %v %v", fg, bg, attr)
+	}
+	if _, err := ParseStyle("mauvish on black"); err == nil {
+		t.Errorf("Expected an unknown color to be an error")
+	}
+	if _, err := ParseStyle("red blue"); err == nil {
+		t.Errorf("Expected two foregrounds to be an error")
+	}
+	if _, err := ParseStyle("red on"); err == nil {
+		t.Errorf("Expected on without a color to be an error")
+	}
+}
+
+func TestThemeCard(t *testing.T) {
+	heart := generic.NewCard("A", "H", "red", 14, 8, true)
+	club := generic.NewCard("A", "C", "black", 14, 2, true)
+	light := Themes[DefaultTheme]
+	if light.Card(heart) != light.Red || light.Card(club) != light.Black {
+		t.Errorf("Expected the light theme to style cards by color")
+	}
+	four := Themes["fourcolor"]
+	if four.Card(club) == four.Card(generic.NewCard("A", "S", "black", 14, 16, true)) {
+		t.Errorf("Expected clubs and spades to differ in the four color theme")
+	}
+}
+
+func TestParseThemes(t *testing.T) {
+	text := `# my themes
+[felt]
+base = classic
+status = black on yellow
+
+[fourcolor]
+diamonds = orange on white
+`
+	themes, err := ParseThemes(strings.NewReader(text), Themes)
+	if err != nil {
+		t.Fatalf("Expected no error but was %v", err)
+	}
+	felt := themes["felt"]
+	if felt.Name != "felt" || felt.Screen != Themes["classic"].Screen {
+		t.Errorf("Expected felt to be based on classic but was %v", felt)
+	}
+	if felt.Status != style(tcell.ColorBlack, tcell.ColorYellow) {
+		t.Errorf("Expected the felt status black on yellow")
+	}
+	d := generic.NewCard("2", "D", "red", 2, 4, true)
+	if themes["fourcolor"].Card(d) != style(tcell.ColorOrange, tcell.ColorWhite) {
+		t.Errorf("Expected orange diamonds in the changed four color theme")
+	}
+	if Themes["fourcolor"].Card(d) != style(tcell.ColorNavy, tcell.ColorWhite) {
+		t.Errorf("Expected the built in four color theme to be unchanged")
+	}
+	bad := []string{"screen = red\n", "[x]\nscreen\n", "[x]\nfelt = red\n", "[x]\nbase = nothing\n", "[x]\nbox = nocolor\n"}
+	for _, b := range bad {
+		if _, err := ParseThemes(strings.NewReader(b), Themes); err == nil {
+			t.Errorf("Expected %q to be an error", b)
+		}
+	}
+	if themes, err = LoadThemes("no such file", Themes); err != nil || len(themes) != len(Themes) {
+		t.Errorf("Expected the built in themes without a file but was %v", err)
+	}
+}
+
+func TestFocus(t *testing.T) {
+	var b strings.Builder
+	f := ReportFocus(&b)
+	bracket := tcell.NewEventKey(tcell.KeyRune, '[', tcell.ModAlt)
+	if ev := f.Filter(bracket); ev != nil {
+		t.Errorf("Expected the start of a report to be held back but was %v", ev)
+	}
+	if ev, ok := f.Filter(tcell.NewEventKey(tcell.KeyRune, 'O', tcell.ModNone)).(*EventFocus); !ok || ev.Focused {
+		t.Errorf("Expected focus lost but was %v", ev)
+	}
+	f.Filter(bracket)
+	if ev, ok := f.Filter(tcell.NewEventKey(tcell.KeyRune, 'I', tcell.ModNone)).(*EventFocus); !ok || !ev.Focused {
+		t.Errorf("Expected focus gained but was %v", ev)
+	}
+	f.Filter(bracket)
+	if ev, ok := f.Filter(tcell.NewEventKey(tcell.KeyRune, 'x', tcell.ModNone)).(*tcell.EventKey); !ok || ev.Rune() != 'x' {
+		t.Errorf("Expected other keys passed through but was %v", ev)
+	}
+	f.Stop()
+	if b.String() != "\x1b[?1004h\x1b[?1004l" {
+		t.Errorf("Expected focus reporting turned on and off but was %q", b.String())
+	}
+	var none *Focus
+	if ev := none.Filter(bracket); ev != bracket {
+		t.Errorf("Expected a nil Focus to pass events through but was %v", ev)
+	}
+}
@@ -0,0 +1,78 @@
+package display
+
+import (
+	"io"
+	"time"
+
+	"github.com/gdamore/tcell"
+)
+
+// focusOn and focusOff turn xterm focus reporting on and off.
+const (
+	focusOn  = "\x1b[?1004h"
+	focusOff = "\x1b[?1004l"
+)
+
+// EventFocus tells that the terminal has gained or lost focus.
+type EventFocus struct {
+	Focused bool
+	t       time.Time
+}
+
+// When returns the time the report arrived.
+func (ev *EventFocus) When() time.Time {
+	return ev.t
+}
+
+// Focus follows the focus reports of a terminal that supports xterm focus reporting.  tcell does
+// not know the reports, ESC [ I when focus is gained and ESC [ O when it is lost, so they arrive
+// as the key Alt+[ followed by the key I or O, which Filter turns back into an EventFocus.
+// A nil Focus passes every event through.
+// bracket: The Alt+[ of a report has been seen
+type Focus struct {
+	w       io.Writer
+	bracket bool
+}
+
+// ReportFocus asks the terminal written to by w to report when it gains and loses focus.
+// Terminals that do not support it ignore the request.
+func ReportFocus(w io.Writer) *Focus {
+	io.WriteString(w, focusOn)
+	return &Focus{w: w}
+}
+
+// Stop asks the terminal to stop reporting focus.
+func (f *Focus) Stop() {
+	if f != nil {
+		io.WriteString(f.w, focusOff)
+	}
+}
+
+// Filter returns an EventFocus for the event that finishes a focus report, nil for the Alt+[
+// that starts one and any other event as it is.
+func (f *Focus) Filter(ev tcell.Event) tcell.Event {
+	if f == nil {
+		return ev
+	}
+	key, ok := ev.(*tcell.EventKey)
+	if !ok {
+		return ev
+	}
+	if f.bracket {
+		f.bracket = false
+		if key.Key() == tcell.KeyRune && key.Modifiers() == tcell.ModNone {
+			switch key.Rune() {
+			case 'I':
+				return &EventFocus{Focused: true, t: key.When()}
+			case 'O':
+				return &EventFocus{Focused: false, t: key.When()}
+			}
+		}
+		return ev
+	}
+	if key.Key() == tcell.KeyRune && key.Rune() == '[' && key.Modifiers() == tcell.ModAlt {
+		f.bracket = true
+		return nil
+	}
+	return ev
+}
@@ -0,0 +1,143 @@
+package display
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/gdamore/tcell"
+)
+
+// Keymap maps the name of an action in a game to the names of the keys that do it.
+// A key is named by its letter in lower case or by its tcell name such as Enter, Space,
+// Tab, Esc, Left or Up.
+type Keymap map[string][]string
+
+// KeyName returns the name of the key in an event as used in a Keymap.
+func KeyName(ev *tcell.EventKey) string {
+	if ev.Key() == tcell.KeyRune {
+		if ev.Rune() == ' ' {
+			return "Space"
+		}
+		return string(unicode.ToLower(ev.Rune()))
+	}
+	if name, ok := tcell.KeyNames[ev.Key()]; ok {
+		return name
+	}
+	return ""
+}
+
+// Is tells if the key in an event is bound to an action.
+func (k Keymap) Is(ev *tcell.EventKey, action string) bool {
+	name := KeyName(ev)
+	for _, key := range k[action] {
+		if strings.EqualFold(key, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Keys returns the keys bound to an action joined for showing in help text.
+func (k Keymap) Keys(action string) string {
+	var keys []string
+	for _, key := range k[action] {
+		if len(key) == 1 {
+			key = strings.ToUpper(key)
+		}
+		keys = append(keys, key)
+	}
+	return strings.Join(keys, "/")
+}
+
+// ParseKeymap reads key bindings, one action per line, as
+//
+//	action = key key ...
+//
+// Blank lines and lines starting with # are skipped.  Actions not in the file keep the keys
+// they have in defaults, an action that is not in defaults is an error.  So is a key bound to two
+// actions, unless the actions share a key in defaults as ones used at different times do.
+func ParseKeymap(r io.Reader, defaults Keymap) (Keymap, error) {
+	k := make(Keymap)
+	for action, keys := range defaults {
+		k[action] = keys
+	}
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		parts := strings.SplitN(text, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("line %d: expected action = keys", line)
+		}
+		action := strings.TrimSpace(parts[0])
+		if _, ok := defaults[action]; !ok {
+			return nil, fmt.Errorf("line %d: unknown action %s", line, action)
+		}
+		keys := strings.Fields(parts[1])
+		if len(keys) == 0 {
+			return nil, fmt.Errorf("line %d: no keys for %s", line, action)
+		}
+		k[action] = keys
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if err := k.conflict(defaults); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// conflict returns an error for a key bound to two actions that do not share a key in defaults.
+func (k Keymap) conflict(defaults Keymap) error {
+	var actions []string
+	for action := range k {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+	bound := make(map[string]string)
+	for _, action := range actions {
+		for _, key := range k[action] {
+			name := strings.ToLower(key)
+			if other, ok := bound[name]; ok && other != action && !defaults.share(other, action) {
+				return fmt.Errorf("%s is bound to both %s and %s", key, other, action)
+			}
+			bound[name] = action
+		}
+	}
+	return nil
+}
+
+// share tells if two actions have a key in common.
+func (k Keymap) share(a, b string) bool {
+	for _, x := range k[a] {
+		for _, y := range k[b] {
+			if strings.EqualFold(x, y) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// LoadKeymap reads key bindings from the file at path.  If there is no file the defaults are used.
+func LoadKeymap(path string, defaults Keymap) (Keymap, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return defaults, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseKeymap(f, defaults)
+}
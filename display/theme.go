@@ -0,0 +1,291 @@
+package display
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell"
+	"github.com/tmasterson/cardgames/generic"
+)
+
+// Theme is a set of styles for drawing a game.
+// Screen: The background and any text that is not otherwise styled
+// Box: The boxes, titles and help text
+// Red, Black: Cards of each color
+// Highlight: The cards under the cursor
+// Status: The status line
+// Suits: Styles for single suits, by the suit letter, used instead of Red and Black
+type Theme struct {
+	Name      string
+	Screen    tcell.Style
+	Box       tcell.Style
+	Red       tcell.Style
+	Black     tcell.Style
+	Highlight tcell.Style
+	Status    tcell.Style
+	Suits     map[string]tcell.Style
+}
+
+// DefaultTheme is the name of the theme used when none is chosen, black on white.
+const DefaultTheme = "light"
+
+// Themes are the themes built in.
+// classic: White on green felt with the cards on white
+// dark: Light text on black
+// light: Black on white
+// contrast: Bold white and yellow on black for low vision
+// fourcolor: A four color deck so no two suits share a color, for color blindness
+var Themes = map[string]Theme{
+	"classic": {
+		Name:      "classic",
+		Screen:    style(tcell.ColorWhite, tcell.ColorDarkGreen),
+		Box:       style(tcell.ColorYellow, tcell.ColorDarkGreen),
+		Red:       style(tcell.ColorRed, tcell.ColorWhite),
+		Black:     style(tcell.ColorBlack, tcell.ColorWhite),
+		Highlight: style(tcell.ColorWhite, tcell.ColorNavy),
+		Status:    style(tcell.ColorWhite, tcell.ColorDarkGreen).Bold(true),
+	},
+	"dark": {
+		Name:      "dark",
+		Screen:    style(tcell.ColorSilver, tcell.ColorBlack),
+		Box:       style(tcell.ColorGray, tcell.ColorBlack),
+		Red:       style(tcell.ColorRed, tcell.ColorBlack),
+		Black:     style(tcell.ColorWhite, tcell.ColorBlack),
+		Highlight: style(tcell.ColorBlack, tcell.ColorSilver),
+		Status:    style(tcell.ColorYellow, tcell.ColorBlack),
+	},
+	"light": {
+		Name:      "light",
+		Screen:    style(tcell.ColorBlack, tcell.ColorWhite),
+		Box:       style(tcell.ColorBlack, tcell.ColorWhite),
+		Red:       style(tcell.ColorMaroon, tcell.ColorWhite),
+		Black:     style(tcell.ColorBlack, tcell.ColorWhite),
+		Highlight: style(tcell.ColorWhite, tcell.ColorBlack),
+		Status:    style(tcell.ColorBlack, tcell.ColorWhite),
+	},
+	"contrast": {
+		Name:      "contrast",
+		Screen:    style(tcell.ColorWhite, tcell.ColorBlack).Bold(true),
+		Box:       style(tcell.ColorWhite, tcell.ColorBlack).Bold(true),
+		Red:       style(tcell.ColorYellow, tcell.ColorBlack).Bold(true),
+		Black:     style(tcell.ColorWhite, tcell.ColorBlack).Bold(true),
+		Highlight: style(tcell.ColorBlack, tcell.ColorYellow).Bold(true),
+		Status:    style(tcell.ColorBlack, tcell.ColorWhite).Bold(true),
+	},
+	"fourcolor": {
+		Name:      "fourcolor",
+		Screen:    style(tcell.ColorBlack, tcell.ColorWhite),
+		Box:       style(tcell.ColorBlack, tcell.ColorWhite),
+		Red:       style(tcell.ColorMaroon, tcell.ColorWhite),
+		Black:     style(tcell.ColorBlack, tcell.ColorWhite),
+		Highlight: style(tcell.ColorWhite, tcell.ColorBlack),
+		Status:    style(tcell.ColorBlack, tcell.ColorWhite),
+		Suits: map[string]tcell.Style{
+			"S": style(tcell.ColorBlack, tcell.ColorWhite),
+			"H": style(tcell.ColorMaroon, tcell.ColorWhite),
+			"D": style(tcell.ColorNavy, tcell.ColorWhite),
+			"C": style(tcell.ColorGreen, tcell.ColorWhite),
+		},
+	},
+}
+
+// style returns the default style with the colors given.
+func style(fg, bg tcell.Color) tcell.Style {
+	return tcell.StyleDefault.Foreground(fg).Background(bg)
+}
+
+// Card returns the style for a card.
+func (t Theme) Card(c generic.Card) tcell.Style {
+	if st, ok := t.Suits[c.Suit]; ok {
+		return st
+	}
+	if c.Color == "red" {
+		return t.Red
+	}
+	return t.Black
+}
+
+// ThemeNames returns the names of the themes in alphabetical order for help text.
+func ThemeNames(themes map[string]Theme) string {
+	var names []string
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// ParseStyle reads a style written as
+//
+//	color [on color] [bold] [dim] [reverse] [underline]
+//
+// Colors are W3C names such as red or navy, hex such as #ff8000, or default.
+func ParseStyle(spec string) (tcell.Style, error) {
+	st := tcell.StyleDefault
+	words := strings.Fields(strings.ToLower(spec))
+	fg := true
+	for i := 0; i < len(words); i++ {
+		switch words[i] {
+		case "bold":
+			st = st.Bold(true)
+		case "dim":
+			st = st.Dim(true)
+		case "reverse":
+			st = st.Reverse(true)
+		case "underline":
+			st = st.Underline(true)
+		case "on":
+			i++
+			if i == len(words) {
+				return st, fmt.Errorf("no color after on in %q", spec)
+			}
+			c, err := color(words[i])
+			if err != nil {
+				return st, err
+			}
+			st = st.Background(c)
+		default:
+			if !fg {
+				return st, fmt.Errorf("unexpected %s in %q", words[i], spec)
+			}
+			c, err := color(words[i])
+			if err != nil {
+				return st, err
+			}
+			st = st.Foreground(c)
+			fg = false
+		}
+	}
+	return st, nil
+}
+
+// color returns the color for a name, tcell.GetColor gives the default for names it does not know.
+func color(name string) (tcell.Color, error) {
+	if name == "default" {
+		return tcell.ColorDefault, nil
+	}
+	if c := tcell.GetColor(name); c != tcell.ColorDefault {
+		return c, nil
+	}
+	return tcell.ColorDefault, fmt.Errorf("unknown color %s", name)
+}
+
+// ParseThemes reads themes, each starting with its name in brackets followed by one style a line, as
+//
+//	[name]
+//	base = theme
+//	screen = white on green
+//	hearts = red on white bold
+//
+// The styles are screen, box, red, black, highlight and status, or spades, hearts, diamonds and
+// clubs for a single suit.  A theme starts as a copy of base, which should come first, of the theme
+// it replaces or of the default theme.  Blank lines and lines starting with # are skipped.
+// The themes read are added to a copy of themes which is returned.
+func ParseThemes(r io.Reader, themes map[string]Theme) (map[string]Theme, error) {
+	all := make(map[string]Theme)
+	for name, t := range themes {
+		all[name] = t
+	}
+	suits := map[string]string{"spades": "S", "hearts": "H", "diamonds": "D", "clubs": "C"}
+	var t *Theme
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		if strings.HasPrefix(text, "[") && strings.HasSuffix(text, "]") {
+			if t != nil {
+				all[t.Name] = *t
+			}
+			name := strings.TrimSpace(text[1 : len(text)-1])
+			if name == "" {
+				return nil, fmt.Errorf("line %d: theme with no name", line)
+			}
+			t = copyTheme(all, name)
+			continue
+		}
+		if t == nil {
+			return nil, fmt.Errorf("line %d: expected [name] before styles", line)
+		}
+		parts := strings.SplitN(text, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("line %d: expected name = style", line)
+		}
+		key := strings.ToLower(strings.TrimSpace(parts[0]))
+		value := strings.TrimSpace(parts[1])
+		if key == "base" {
+			if _, ok := all[value]; !ok {
+				return nil, fmt.Errorf("line %d: unknown theme %s", line, value)
+			}
+			name := t.Name
+			t = copyTheme(all, value)
+			t.Name = name
+			continue
+		}
+		st, err := ParseStyle(value)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		switch key {
+		case "screen":
+			t.Screen = st
+		case "box":
+			t.Box = st
+		case "red":
+			t.Red = st
+		case "black":
+			t.Black = st
+		case "highlight":
+			t.Highlight = st
+		case "status":
+			t.Status = st
+		default:
+			suit, ok := suits[key]
+			if !ok {
+				return nil, fmt.Errorf("line %d: unknown style %s", line, key)
+			}
+			t.Suits[suit] = st
+		}
+	}
+	if t != nil {
+		all[t.Name] = *t
+	}
+	return all, scanner.Err()
+}
+
+// copyTheme returns a copy of the theme called name, or of the default theme if there is none,
+// with its own map of suits.
+func copyTheme(themes map[string]Theme, name string) *Theme {
+	t, ok := themes[name]
+	if !ok {
+		t = themes[DefaultTheme]
+	}
+	t.Name = name
+	suits := make(map[string]tcell.Style)
+	for suit, st := range t.Suits {
+		suits[suit] = st
+	}
+	t.Suits = suits
+	return &t
+}
+
+// LoadThemes reads themes from the file at path and adds them to themes.
+// If there is no file the themes are returned as they are.
+func LoadThemes(path string, themes map[string]Theme) (map[string]Theme, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return themes, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseThemes(f, themes)
+}
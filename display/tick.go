@@ -0,0 +1,31 @@
+package display
+
+import (
+	"time"
+
+	"github.com/gdamore/tcell"
+)
+
+// Tick is the data of the interrupts posted by StartTicker.
+type Tick struct{}
+
+// StartTicker posts an interrupt carrying a Tick to the screen every d so a game can update
+// a clock while it waits for keys.
+//
+// returns: A function that stops the ticker.
+func StartTicker(s tcell.Screen, d time.Duration) func() {
+	t := time.NewTicker(d)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-t.C:
+				s.PostEvent(tcell.NewEventInterrupt(Tick{}))
+			case <-done:
+				t.Stop()
+				return
+			}
+		}
+	}()
+	return func() { close(done) }
+}
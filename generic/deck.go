@@ -31,10 +31,12 @@ type Card struct {
 
 // Deck holds the cards in the deck to be shuffled and the last one dealt.
 // AllDealt tells if all the cards in the deck have been dealt as we don't actually remove the cards we have dealt.
+// Source supplies the random numbers for Shuffle, when it is nil math/rand is used.
 type Deck struct {
 	Cards     []Card
 	LastDealt int
 	AllDealt  bool
+	Source    Source
 }
 
 // NewCard returns a new card with the given criteria.
@@ -79,11 +81,37 @@ func NewDeck() (deck Deck) {
 	return
 }
 
-// Shuffle the deck.
+// NewDecks returns n decks of cards combined into a single deck.
+// This is used for games such as blackjack that deal from a shoe or two deck solitaires.
+func NewDecks(n int) (deck Deck) {
+	for i := 0; i < n; i++ {
+		d := NewDeck()
+		deck.Cards = append(deck.Cards, d.Cards...)
+	}
+	deck.LastDealt = 0
+	deck.AllDealt = false
+	return
+}
+
+// Shuffle the deck using the deck's Source.
 func (d *Deck) Shuffle() {
+	d.ShuffleWith(d.source())
+}
+
+// source returns the deck's Source or math/rand if it has none.
+func (d *Deck) source() Source {
+	if d.Source == nil {
+		return mathSource{}
+	}
+	return d.Source
+}
+
+// ShuffleWith shuffles the deck using the given source of random numbers.
+// This is the Fisher-Yates shuffle so every order is equally likely as long as the source is uniform.
+func (d *Deck) ShuffleWith(src Source) {
 	for i := range d.Cards {
 		// Create a random int up to the number of cards
-		r := rand.Intn(i + 1)
+		r := src.Intn(i + 1)
 
 		// If the current card doesn't match the random
 		// int we generated then we'll switch them out
@@ -121,6 +149,23 @@ func (c *Card) Turn() {
 	}
 }
 
+// Names of the ranks and suits used to write out a card in full.
+var rankNames = map[string]string{
+	"2": "two", "3": "three", "4": "four", "5": "five", "6": "six", "7": "seven", "8": "eight",
+	"9": "nine", "T": "ten", "J": "jack", "Q": "queen", "K": "king", "A": "ace",
+}
+var suitNames = map[string]string{"S": "spades", "H": "hearts", "D": "diamonds", "C": "clubs"}
+
+// Name returns the card written out in full, such as ten of hearts, for reading aloud.
+func (c Card) Name() string {
+	return rankNames[c.Rank] + " of " + suitNames[c.Suit]
+}
+
+// SuitName returns the name of a suit such as spades for the letter S.
+func SuitName(suit string) string {
+	return suitNames[suit]
+}
+
 // Seed our randomness with the current time
 func init() {
 	rand.Seed(time.Now().UnixNano())
@@ -87,3 +87,32 @@ func TestTurn(t *testing.T) {
 		t.Error("expected true but was false")
 	}
 }
+
+func TestName(t *testing.T) {
+	c := Card{"T", "H", 10, 8, "red", true}
+	if c.Name() != "ten of hearts" {
+		t.Errorf("expected ten of hearts but was %s", c.Name())
+	}
+	if SuitName("C") != "clubs" {
+		t.Errorf("expected clubs but was %s", SuitName("C"))
+	}
+}
+
+func TestNewDecks(t *testing.T) {
+	deck := NewDecks(6)
+	if len(deck.Cards) != 312 {
+		t.Errorf("expected length of 312 got %d.", len(deck.Cards))
+	}
+	cnt := 0
+	for _, c := range deck.Cards {
+		if c.Rank == "A" && c.Suit == "S" {
+			cnt++
+		}
+	}
+	if cnt != 6 {
+		t.Errorf("expected 6 aces of spades but got %d.", cnt)
+	}
+	if deck.LastDealt != 0 || deck.AllDealt {
+		t.Errorf("expected a fresh deck but was %d %v.", deck.LastDealt, deck.AllDealt)
+	}
+}
@@ -0,0 +1,197 @@
+package generic
+
+import (
+	"errors"
+	"sort"
+)
+
+// Hand holds the cards held by a player.
+// It is a plain slice of cards so the result of Deal can be used directly as a Hand.
+type Hand []Card
+
+// RankOrder tells how aces are ranked when sorting or looking for runs.
+type RankOrder int
+
+// The rank orderings.
+const (
+	AceHigh RankOrder = iota // Ace ranks above the king
+	AceLow                   // Ace ranks below the two
+)
+
+// Value returns the rank of a card under the ordering.
+// Aces may have been given a value of 1 or 14 so both are handled.
+func (ro RankOrder) Value(c Card) int {
+	if c.Rank != "A" {
+		return c.Rvalue
+	}
+	if ro == AceLow {
+		return 1
+	}
+	return 14
+}
+
+// SortBySuit sorts the hand by suit value and then by rank within each suit.
+// The sort is stable so equal cards from more than one deck keep their order.
+func (h Hand) SortBySuit(ro RankOrder) {
+	sort.SliceStable(h, func(i, j int) bool {
+		if h[i].Svalue != h[j].Svalue {
+			return h[i].Svalue < h[j].Svalue
+		}
+		return ro.Value(h[i]) < ro.Value(h[j])
+	})
+}
+
+// SortByRank sorts the hand by rank and then by suit value for cards of the same rank.
+func (h Hand) SortByRank(ro RankOrder) {
+	sort.SliceStable(h, func(i, j int) bool {
+		vi, vj := ro.Value(h[i]), ro.Value(h[j])
+		if vi != vj {
+			return vi < vj
+		}
+		return h[i].Svalue < h[j].Svalue
+	})
+}
+
+// Find returns the index of the first card with the same rank and suit as c or -1 if there is none.
+func (h Hand) Find(c Card) int {
+	for i, card := range h {
+		if card.Rank == c.Rank && card.Suit == c.Suit {
+			return i
+		}
+	}
+	return -1
+}
+
+// Contains tells if the hand holds a card with the same rank and suit as c.
+func (h Hand) Contains(c Card) bool {
+	return h.Find(c) >= 0
+}
+
+// Remove takes the first card matching c out of the hand.
+// Returns false if the card was not in the hand.
+func (h *Hand) Remove(c Card) bool {
+	i := h.Find(c)
+	if i < 0 {
+		return false
+	}
+	*h = append((*h)[:i], (*h)[i+1:]...)
+	return true
+}
+
+// Names returns the cards as rank and suit such as TH or QS.
+func (h Hand) Names() []string {
+	names := []string{}
+	for _, c := range h {
+		names = append(names, c.Rank+c.Suit)
+	}
+	return names
+}
+
+// Named returns the cards of the hand with the given names in the order they are named.
+// It is an error for a name not to be in the hand or to be given twice.
+func (h Hand) Named(names []string) (Hand, error) {
+	var found Hand
+	for _, name := range names {
+		i := 0
+		for i < len(h) && h[i].Rank+h[i].Suit != name {
+			i++
+		}
+		if i == len(h) {
+			return nil, errors.New(name + " is not in the hand")
+		}
+		if found.Contains(h[i]) {
+			return nil, errors.New(name + " is given twice")
+		}
+		found = append(found, h[i])
+	}
+	return found, nil
+}
+
+// Suit returns the cards of the given suit in the order they are held.
+func (h Hand) Suit(suit string) Hand {
+	var cards Hand
+	for _, c := range h {
+		if c.Suit == suit {
+			cards = append(cards, c)
+		}
+	}
+	return cards
+}
+
+// HasSuit tells if the hand holds any cards of the given suit.
+func (h Hand) HasSuit(suit string) bool {
+	for _, c := range h {
+		if c.Suit == suit {
+			return true
+		}
+	}
+	return false
+}
+
+// GroupBySuit returns the cards in the hand keyed by suit.
+func (h Hand) GroupBySuit() map[string]Hand {
+	groups := make(map[string]Hand)
+	for _, c := range h {
+		groups[c.Suit] = append(groups[c.Suit], c)
+	}
+	return groups
+}
+
+// GroupByRank returns the cards in the hand keyed by rank.
+func (h Hand) GroupByRank() map[string]Hand {
+	groups := make(map[string]Hand)
+	for _, c := range h {
+		groups[c.Rank] = append(groups[c.Rank], c)
+	}
+	return groups
+}
+
+// Sets returns the groups of at least n cards that share a rank, such as the sets in rummy.
+// The sets are returned in rank order.
+func (h Hand) Sets(n int) []Hand {
+	sorted := append(Hand(nil), h...)
+	sorted.SortByRank(AceHigh)
+	var sets []Hand
+	for i := 0; i < len(sorted); {
+		j := i + 1
+		for j < len(sorted) && sorted[j].Rank == sorted[i].Rank {
+			j++
+		}
+		if j-i >= n {
+			sets = append(sets, append(Hand(nil), sorted[i:j]...))
+		}
+		i = j
+	}
+	return sets
+}
+
+// Runs returns the sequences of at least n cards of the same suit in consecutive rank, such as
+// the runs in rummy.  Only the longest run through a card is returned and duplicate cards
+// from a second deck are skipped.
+// The runs are returned ordered by suit and then rank.
+func (h Hand) Runs(n int, ro RankOrder) []Hand {
+	sorted := append(Hand(nil), h...)
+	sorted.SortBySuit(ro)
+	var runs []Hand
+	var run Hand
+	flush := func() {
+		if len(run) >= n {
+			runs = append(runs, run)
+		}
+		run = nil
+	}
+	for _, c := range sorted {
+		if len(run) > 0 {
+			last := run[len(run)-1]
+			switch {
+			case last.Suit == c.Suit && ro.Value(last) == ro.Value(c):
+				continue
+			case last.Suit != c.Suit || ro.Value(last)+1 != ro.Value(c):
+				flush()
+			}
+		}
+		run = append(run, c)
+	}
+	flush()
+	return runs
+}
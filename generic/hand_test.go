@@ -0,0 +1,119 @@
+package generic
+
+import "testing"
+
+// cards builds a hand from rank and suit pairs such as "AS", "TD".
+func cards(names ...string) Hand {
+	values := map[byte]int{'2': 2, '3': 3, '4': 4, '5': 5, '6': 6, '7': 7, '8': 8, '9': 9, 'T': 10, 'J': 11, 'Q': 12, 'K': 13, 'A': 14}
+	svalues := map[byte]int{'C': 2, 'D': 4, 'H': 8, 'S': 16}
+	var h Hand
+	for _, n := range names {
+		h = append(h, NewCard(n[:1], n[1:], "", values[n[0]], svalues[n[1]], true))
+	}
+	return h
+}
+
+func names(h Hand) string {
+	s := ""
+	for i, c := range h {
+		if i > 0 {
+			s += " "
+		}
+		s += c.Rank + c.Suit
+	}
+	return s
+}
+
+func TestSortBySuit(t *testing.T) {
+	h := cards("AS", "2C", "KH", "5C", "2S")
+	h.SortBySuit(AceHigh)
+	if names(h) != "2C 5C KH 2S AS" {
+		t.Errorf("expected 2C 5C KH 2S AS but was %s", names(h))
+	}
+	h.SortBySuit(AceLow)
+	if names(h) != "2C 5C KH AS 2S" {
+		t.Errorf("expected 2C 5C KH AS 2S but was %s", names(h))
+	}
+}
+
+func TestSortByRank(t *testing.T) {
+	h := cards("AS", "2C", "KH", "2S", "AD")
+	h.SortByRank(AceHigh)
+	if names(h) != "2C 2S KH AD AS" {
+		t.Errorf("expected 2C 2S KH AD AS but was %s", names(h))
+	}
+	h[0].Rvalue = 1 // aces made low as in klondike still sort high
+	h.SortByRank(AceHigh)
+	if names(h) != "2C 2S KH AD AS" {
+		t.Errorf("expected 2C 2S KH AD AS but was %s", names(h))
+	}
+}
+
+func TestFindRemove(t *testing.T) {
+	h := cards("AS", "2C", "KH")
+	if h.Find(cards("KH")[0]) != 2 || h.Contains(cards("KS")[0]) {
+		t.Errorf("Find did not work on %s", names(h))
+	}
+	if !h.Remove(cards("2C")[0]) || names(h) != "AS KH" {
+		t.Errorf("expected AS KH but was %s", names(h))
+	}
+	if h.Remove(cards("2C")[0]) {
+		t.Errorf("should not remove a card that is not there")
+	}
+	if !h.HasSuit("H") || h.HasSuit("C") || len(h.Suit("S")) != 1 {
+		t.Errorf("suit checks failed on %s", names(h))
+	}
+}
+
+func TestNamed(t *testing.T) {
+	h := cards("AS", "2C", "KH")
+	if n := h.Names(); len(n) != 3 || n[0] != "AS" || n[2] != "KH" {
+		t.Errorf("Expected AS 2C KH but was %v", n)
+	}
+	found, err := h.Named([]string{"KH", "AS"})
+	if err != nil || names(found) != "KH AS" {
+		t.Errorf("Expected KH AS but was %v %v", names(found), err)
+	}
+	for _, bad := range [][]string{{"3C"}, {"AS", "AS"}} {
+		if _, err := h.Named(bad); err == nil {
+			t.Errorf("Expected %v to be an error", bad)
+		}
+	}
+	if n := Hand(nil).Names(); n == nil || len(n) != 0 {
+		t.Errorf("Expected an empty list for no cards")
+	}
+}
+
+func TestGroups(t *testing.T) {
+	h := cards("AS", "2C", "AH", "5C", "AD")
+	if len(h.GroupBySuit()["C"]) != 2 || len(h.GroupByRank()["A"]) != 3 {
+		t.Errorf("groups were wrong for %s", names(h))
+	}
+}
+
+func TestSets(t *testing.T) {
+	h := cards("7S", "2C", "7H", "5C", "7D", "2D", "KS")
+	sets := h.Sets(2)
+	if len(sets) != 2 || names(sets[0]) != "2C 2D" || names(sets[1]) != "7D 7H 7S" {
+		t.Errorf("expected sets 2C 2D and 7D 7H 7S but was %v", sets)
+	}
+	if len(h.Sets(3)) != 1 {
+		t.Errorf("expected 1 set of 3 but was %d", len(h.Sets(3)))
+	}
+}
+
+func TestRuns(t *testing.T) {
+	h := cards("4H", "3H", "5H", "5H", "9C", "QS", "KS", "AS", "2S")
+	runs := h.Runs(3, AceHigh)
+	if len(runs) != 2 || names(runs[0]) != "3H 4H 5H" || names(runs[1]) != "QS KS AS" {
+		t.Errorf("expected runs 3H 4H 5H and QS KS AS but was %v", runs)
+	}
+	runs = h.Runs(3, AceLow)
+	if len(runs) != 1 || names(runs[0]) != "3H 4H 5H" {
+		t.Errorf("expected run 3H 4H 5H but was %v", runs)
+	}
+	runs = h.Runs(2, AceLow)
+	if len(runs) != 3 || names(runs[2]) != "QS KS" {
+		t.Errorf("expected 3 runs ending QS KS but was %v", runs)
+	}
+}
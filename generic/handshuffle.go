@@ -0,0 +1,73 @@
+package generic
+
+// The methods in this file model the ways people really shuffle cards.  Unlike Shuffle
+// a single one of these does not randomize the deck so they can be combined, for example
+// a few riffles followed by a cut, the way a dealer would.  They all work on every card
+// in the deck and use the deck's Source for randomness.
+
+// Riffle does one riffle shuffle using the Gilbert-Shannon-Reeds model.
+// The deck is cut into two packets with the size of the top packet following a binomial
+// distribution, then cards are dropped from the bottom of either packet with a chance
+// proportional to the packet's size.
+func (d *Deck) Riffle() {
+	src := d.source()
+	n := len(d.Cards)
+	cut := 0
+	for i := 0; i < n; i++ {
+		cut += src.Intn(2)
+	}
+	left := append([]Card(nil), d.Cards[:cut]...)
+	right := append([]Card(nil), d.Cards[cut:]...)
+	// fill from the bottom of the deck up
+	for i := n - 1; i >= 0; i-- {
+		a, b := len(left), len(right)
+		if src.Intn(a+b) < a {
+			d.Cards[i] = left[a-1]
+			left = left[:a-1]
+		} else {
+			d.Cards[i] = right[b-1]
+			right = right[:b-1]
+		}
+	}
+}
+
+// Overhand does one overhand shuffle.
+// Small packets are slid off the top of the deck into the other hand so the order of
+// the packets is reversed but the cards within each packet keep their order.
+// Following Pemantle's model there is a one in four chance of a break after each card.
+func (d *Deck) Overhand() {
+	src := d.source()
+	cards := make([]Card, 0, len(d.Cards))
+	start := 0
+	for i := range d.Cards {
+		if i == len(d.Cards)-1 || src.Intn(4) == 0 {
+			// this packet goes on top of the ones already moved
+			cards = append(append([]Card(nil), d.Cards[start:i+1]...), cards...)
+			start = i + 1
+		}
+	}
+	copy(d.Cards, cards)
+}
+
+// Cut moves the top n cards to the bottom of the deck.
+// A cut of zero or of the whole deck leaves it unchanged.
+func (d *Deck) Cut(n int) {
+	if n <= 0 || n >= len(d.Cards) {
+		return
+	}
+	d.Cards = append(d.Cards[n:], d.Cards[:n]...)
+}
+
+// CutRandom cuts the deck somewhere in the middle half the way a player cuts for the dealer.
+func (d *Deck) CutRandom() {
+	n := len(d.Cards)
+	if n < 4 {
+		return
+	}
+	d.Cut(n/4 + d.source().Intn(n/2))
+}
+
+// Burn deals n cards face down and returns them.  They are out of play the same as dealt cards.
+func (d *Deck) Burn(n int) []Card {
+	return d.Deal(n, 0)
+}
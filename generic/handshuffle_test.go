@@ -0,0 +1,117 @@
+package generic
+
+import "testing"
+
+// fixedSource returns the given values in order for testing.
+type fixedSource struct {
+	values []int
+}
+
+func (f *fixedSource) Intn(n int) int {
+	v := f.values[0] % n
+	f.values = f.values[1:]
+	return v
+}
+
+// numbered returns a deck of n cards whose ranks are 0 to n-1 in order.
+func numbered(n int) Deck {
+	var d Deck
+	for i := 0; i < n; i++ {
+		d.Cards = append(d.Cards, Card{Rvalue: i})
+	}
+	return d
+}
+
+func order(d Deck) []int {
+	var o []int
+	for _, c := range d.Cards {
+		o = append(o, c.Rvalue)
+	}
+	return o
+}
+
+// risingSequences counts the rising sequences in a numbered deck.
+func risingSequences(d Deck) int {
+	pos := make([]int, len(d.Cards))
+	for i, c := range d.Cards {
+		pos[c.Rvalue] = i
+	}
+	r := 1
+	for i := 1; i < len(pos); i++ {
+		if pos[i] < pos[i-1] {
+			r++
+		}
+	}
+	return r
+}
+
+func TestRiffle(t *testing.T) {
+	d := numbered(52)
+	d.Riffle()
+	if len(d.Cards) != 52 {
+		t.Fatalf("expected 52 cards but had %d", len(d.Cards))
+	}
+	seen := make(map[int]bool)
+	for _, c := range d.Cards {
+		seen[c.Rvalue] = true
+	}
+	if len(seen) != 52 {
+		t.Errorf("cards were lost or duplicated in the riffle")
+	}
+	if r := risingSequences(d); r > 2 {
+		t.Errorf("one riffle can make at most 2 rising sequences but had %d", r)
+	}
+	// cut 2 from the top of four cards then drop right, left, right, left from the bottom
+	d = numbered(4)
+	d.Source = &fixedSource{values: []int{1, 1, 0, 0, 3, 0, 1, 0}}
+	d.Riffle()
+	o := order(d)
+	if o[0] != 0 || o[1] != 2 || o[2] != 1 || o[3] != 3 {
+		t.Errorf("expected 0 2 1 3 but was %v", o)
+	}
+}
+
+func TestOverhand(t *testing.T) {
+	d := numbered(6)
+	// breaks after cards 1 and 3 giving packets 0-1, 2-3 and 4-5
+	d.Source = &fixedSource{values: []int{1, 0, 1, 0, 1}}
+	d.Overhand()
+	o := order(d)
+	want := []int{4, 5, 2, 3, 0, 1}
+	for i := range want {
+		if o[i] != want[i] {
+			t.Fatalf("expected %v but was %v", want, o)
+		}
+	}
+}
+
+func TestCut(t *testing.T) {
+	d := numbered(5)
+	d.Cut(2)
+	o := order(d)
+	if o[0] != 2 || o[4] != 1 {
+		t.Errorf("expected 2 3 4 0 1 but was %v", o)
+	}
+	d.Cut(0)
+	d.Cut(5)
+	if order(d)[0] != 2 {
+		t.Errorf("cuts of 0 or the whole deck should do nothing")
+	}
+	d = numbered(52)
+	d.CutRandom()
+	if top := d.Cards[0].Rvalue; top < 13 || top >= 39 {
+		t.Errorf("expected a cut in the middle half but the top card was %d", top)
+	}
+}
+
+func TestBurn(t *testing.T) {
+	d := NewDeck()
+	burnt := d.Burn(1)
+	if len(burnt) != 1 || burnt[0].Faceup || d.LastDealt != 1 {
+		t.Errorf("expected 1 face down card burnt")
+	}
+	hand := d.Deal(1, 1)
+	if hand[0].Rank != d.Cards[1].Rank || hand[0].Suit != d.Cards[1].Suit {
+		t.Errorf("expected the card after the burn card to be dealt")
+	}
+}
@@ -0,0 +1,133 @@
+// This program measures how many riffle shuffles are needed to randomize a deck.
+//
+// For each number of riffles it prints the total variation distance from a random deck,
+// worked out exactly with the Bayer and Diaconis formula for the Gilbert-Shannon-Reeds
+// model, along with the average number of rising sequences found by actually riffling a
+// deck with generic.Deck.Riffle.  A random deck has (n+1)/2 rising sequences on average.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/tmasterson/cardgames/generic"
+)
+
+// Eulerian returns the number of orders of n cards that have r rising sequences for r from 1 to n.
+// Index 0 is unused.
+func eulerian(n int) []*big.Int {
+	// row[m] is the count with m descents, that is m+1 rising sequences
+	row := []*big.Int{big.NewInt(1)}
+	for i := 2; i <= n; i++ {
+		next := make([]*big.Int, i)
+		for m := 0; m < i; m++ {
+			v := new(big.Int)
+			if m < len(row) {
+				v.Mul(big.NewInt(int64(m+1)), row[m])
+			}
+			if m > 0 {
+				v.Add(v, new(big.Int).Mul(big.NewInt(int64(i-m)), row[m-1]))
+			}
+			next[m] = v
+		}
+		row = next
+	}
+	return append([]*big.Int{nil}, row...)
+}
+
+// Distance returns the total variation distance between a deck of n cards after k riffles and
+// a perfectly random deck.  After k riffles an order with r rising sequences has the chance
+// C(2^k + n - r, n) / 2^(nk).
+func distance(n, k int) float64 {
+	counts := eulerian(n)
+	fact := new(big.Int).MulRange(1, int64(n))
+	uniform := new(big.Rat).SetFrac(big.NewInt(1), fact)
+	denom := new(big.Int).Lsh(big.NewInt(1), uint(n*k))
+	twoK := new(big.Int).Lsh(big.NewInt(1), uint(k))
+	total := new(big.Rat)
+	for r := 1; r <= n; r++ {
+		top := new(big.Int).Add(twoK, big.NewInt(int64(n-r)))
+		var ways *big.Int
+		if top.Cmp(big.NewInt(int64(n))) < 0 {
+			ways = new(big.Int)
+		} else {
+			ways = binomial(top, n)
+		}
+		p := new(big.Rat).SetFrac(ways, denom)
+		p.Sub(p, uniform)
+		p.Abs(p)
+		p.Mul(p, new(big.Rat).SetInt(counts[r]))
+		total.Add(total, p)
+	}
+	total.Quo(total, big.NewRat(2, 1))
+	f, _ := total.Float64()
+	return f
+}
+
+// Binomial returns top choose n for a large top.
+func binomial(top *big.Int, n int) *big.Int {
+	v := big.NewInt(1)
+	for i := 0; i < n; i++ {
+		v.Mul(v, new(big.Int).Sub(top, big.NewInt(int64(i))))
+	}
+	return v.Div(v, new(big.Int).MulRange(1, int64(n)))
+}
+
+// RisingSequences counts the rising sequences in a deck compared to the order in start.
+// Cards are matched by rank and suit so the deck should hold one of each card.
+func risingSequences(start, d []generic.Card) int {
+	pos := make(map[string]int)
+	for i, c := range d {
+		pos[c.Rank+c.Suit] = i
+	}
+	r := 1
+	for i := 1; i < len(start); i++ {
+		if pos[start[i].Rank+start[i].Suit] < pos[start[i-1].Rank+start[i-1].Suit] {
+			r++
+		}
+	}
+	return r
+}
+
+// Simulate riffles a fresh deck k times for each trial and returns the average number of rising sequences.
+func simulate(k, trials int) float64 {
+	start := generic.NewDeck()
+	total := 0
+	for t := 0; t < trials; t++ {
+		d := generic.NewDeck()
+		for i := 0; i < k; i++ {
+			d.Riffle()
+		}
+		total += risingSequences(start.Cards, d.Cards)
+	}
+	return float64(total) / float64(trials)
+}
+
+func main() {
+	maxptr := flag.Int("max", 12, "Largest number of riffles to measure")
+	trialptr := flag.Int("trials", 2000, "Number of decks to riffle for each count, 0 skips the simulation")
+	flag.Parse()
+	if *maxptr < 1 {
+		fmt.Fprintf(os.Stderr, "max must be at least 1\n")
+		os.Exit(1)
+	}
+	n := len(generic.NewDeck().Cards)
+	fmt.Printf("Riffles  Distance  Rising sequences (random deck averages %.1f)\n", float64(n+1)/2)
+	enough := 0
+	for k := 1; k <= *maxptr; k++ {
+		d := distance(n, k)
+		if enough == 0 && d < 0.5 {
+			enough = k
+		}
+		if *trialptr > 0 {
+			fmt.Printf("%7d  %8.3f  %6.2f\n", k, d, simulate(k, *trialptr))
+		} else {
+			fmt.Printf("%7d  %8.3f\n", k, d)
+		}
+	}
+	if enough > 0 {
+		fmt.Printf("%d riffles bring a %d card deck within a distance of 0.5 from random.\n", enough, n)
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"github.com/tmasterson/cardgames/generic"
+)
+
+func TestEulerian(t *testing.T) {
+	e := eulerian(4)
+	want := []int64{1, 11, 11, 1}
+	for r := 1; r <= 4; r++ {
+		if e[r].Int64() != want[r-1] {
+			t.Errorf("expected %d orders with %d rising sequences but was %v", want[r-1], r, e[r])
+		}
+	}
+}
+
+func TestDistance(t *testing.T) {
+	// The values published by Bayer and Diaconis for a 52 card deck.
+	want := map[int]float64{5: 0.924, 6: 0.614, 7: 0.334, 8: 0.167, 9: 0.085, 10: 0.043}
+	for k, v := range want {
+		if d := distance(52, k); math.Abs(d-v) > 0.001 {
+			t.Errorf("expected %.3f after %d riffles but was %.3f", v, k, d)
+		}
+	}
+	if d := distance(52, 1); d < 0.999 {
+		t.Errorf("one riffle should be nowhere near random but was %.3f", d)
+	}
+}
+
+func TestRisingSequences(t *testing.T) {
+	start := generic.NewDeck()
+	d := generic.NewDeck()
+	if r := risingSequences(start.Cards, d.Cards); r != 1 {
+		t.Errorf("expected 1 rising sequence but was %d", r)
+	}
+	d.Riffle()
+	if r := risingSequences(start.Cards, d.Cards); r > 2 {
+		t.Errorf("expected at most 2 rising sequences but was %d", r)
+	}
+	if avg := simulate(7, 200); avg < 20 || avg > 26.5 {
+		t.Errorf("expected about 26 rising sequences after 7 riffles but was %.2f", avg)
+	}
+}
@@ -0,0 +1,139 @@
+package generic
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	mrand "math/rand"
+	"time"
+)
+
+// Source is the interface for the random numbers used when shuffling.
+// Intn returns a number in [0, n).  A *rand.Rand from math/rand satisfies it.
+type Source interface {
+	Intn(n int) int
+}
+
+// mathSource uses the global math/rand generator seeded in init.
+type mathSource struct{}
+
+// Intn returns a number from the global math/rand generator.
+func (mathSource) Intn(n int) int {
+	return mrand.Intn(n)
+}
+
+// CryptoSource takes its random numbers from crypto/rand so shuffles can not be predicted.
+// It panics if the operating system can not supply random numbers.
+type CryptoSource struct{}
+
+// Intn returns a uniformly distributed number from crypto/rand.
+func (CryptoSource) Intn(n int) int {
+	v, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		panic(fmt.Sprintf("crypto/rand failed: %v", err))
+	}
+	return int(v.Int64())
+}
+
+// SeedSource is a deterministic source built from a seed so a shuffle can be repeated
+// and checked by anyone who knows the seed.
+// The numbers are SHA-256 hashes of the seed and a counter.
+type SeedSource struct {
+	seed    []byte
+	counter uint64
+	buf     []byte
+}
+
+// NewSeedSource returns a source that always gives the same numbers for the same seed.
+func NewSeedSource(seed []byte) *SeedSource {
+	return &SeedSource{seed: append([]byte(nil), seed...)}
+}
+
+// uint64 returns the next 64 random bits.
+func (s *SeedSource) uint64() uint64 {
+	if len(s.buf) < 8 {
+		var ctr [8]byte
+		binary.BigEndian.PutUint64(ctr[:], s.counter)
+		s.counter++
+		sum := sha256.Sum256(append(append([]byte(nil), s.seed...), ctr[:]...))
+		s.buf = sum[:]
+	}
+	v := binary.BigEndian.Uint64(s.buf[:8])
+	s.buf = s.buf[8:]
+	return v
+}
+
+// Intn returns a number in [0, n).
+// Values from the top of the range that would make small numbers more likely are thrown away.
+func (s *SeedSource) Intn(n int) int {
+	if n <= 0 {
+		panic("invalid argument to Intn")
+	}
+	limit := ^uint64(0) - ^uint64(0)%uint64(n)
+	for {
+		v := s.uint64()
+		if v < limit {
+			return int(v % uint64(n))
+		}
+	}
+}
+
+// Commitment is used to prove a deal was fixed before play started.
+// The Hash is shown before play and the Seed revealed afterwards.  Anyone can then
+// check the seed matches the hash and shuffle a deck with NewSeedSource(Seed) to see the same deal.
+type Commitment struct {
+	Seed []byte
+	Hash string
+}
+
+// NewCommitment returns a commitment to a new random seed taken from crypto/rand.
+func NewCommitment() (Commitment, error) {
+	seed := make([]byte, 32)
+	if _, err := rand.Read(seed); err != nil {
+		return Commitment{}, err
+	}
+	return CommitTo(seed), nil
+}
+
+// CommitTo returns the commitment for a known seed.
+func CommitTo(seed []byte) Commitment {
+	sum := sha256.Sum256(seed)
+	return Commitment{Seed: seed, Hash: hex.EncodeToString(sum[:])}
+}
+
+// Source returns the shuffle source for the committed seed.
+func (c Commitment) Source() *SeedSource {
+	return NewSeedSource(c.Seed)
+}
+
+// SeedString returns the seed in hex as it is revealed to the players.
+func (c Commitment) SeedString() string {
+	return hex.EncodeToString(c.Seed)
+}
+
+// ParseSeed converts a revealed hex seed back into a commitment.
+func ParseSeed(str string) (Commitment, error) {
+	seed, err := hex.DecodeString(str)
+	if err != nil {
+		return Commitment{}, fmt.Errorf("seed %q is not valid hex: %v", str, err)
+	}
+	return CommitTo(seed), nil
+}
+
+// VerifySeed tells if the revealed hex seed matches the hash shown before play.
+func VerifySeed(seed, hash string) bool {
+	c, err := ParseSeed(seed)
+	return err == nil && c.Hash == hash
+}
+
+// DailySeed returns the commitment for the deal of the day for a game.  Everyone playing the
+// game on the same date gets the same deal, which can be replayed later from the seed.
+// game: The name of the game, different games get different deals on the same day
+// day: The time, only the year, month and day in UTC are used so the day changes at the same
+// moment for everyone wherever they are
+func DailySeed(game string, day time.Time) Commitment {
+	return CommitTo([]byte("cardgames daily " + game + " " + day.UTC().Format("2006-01-02")))
+}
@@ -0,0 +1,183 @@
+package generic
+
+import (
+	"math"
+	mrand "math/rand"
+	"testing"
+	"time"
+)
+
+// permChiSquare shuffles a four card deck many times and returns the chi-square
+// statistic of how often each of the 24 orders came up against an even spread.
+// shuffle is called to shuffle the deck each time.
+func permChiSquare(trials int, shuffle func(d *Deck)) float64 {
+	counts := make(map[string]int)
+	for i := 0; i < trials; i++ {
+		d := Deck{Cards: []Card{{Rank: "A"}, {Rank: "B"}, {Rank: "C"}, {Rank: "D"}}}
+		shuffle(&d)
+		key := ""
+		for _, c := range d.Cards {
+			key += c.Rank
+		}
+		counts[key]++
+	}
+	expected := float64(trials) / 24
+	chi := 0.0
+	for _, n := range counts {
+		chi += (float64(n) - expected) * (float64(n) - expected) / expected
+	}
+	// orders that never came up still count
+	chi += float64(24-len(counts)) * expected
+	return chi
+}
+
+// The critical value of chi-square with 23 degrees of freedom at p = 0.000001.
+const chiCritical = 70.0
+
+func TestShuffleUniform(t *testing.T) {
+	sources := map[string]Source{
+		"math/rand":   mrand.New(mrand.NewSource(1)),
+		"crypto/rand": CryptoSource{},
+		"seed":        NewSeedSource([]byte("uniformity")),
+	}
+	for name, src := range sources {
+		chi := permChiSquare(24000, func(d *Deck) { d.ShuffleWith(src) })
+		if chi > chiCritical {
+			t.Errorf("%s shuffle is not uniform, chi-square was %.1f", name, chi)
+		}
+	}
+	chi := permChiSquare(24000, func(d *Deck) { d.Shuffle() })
+	if chi > chiCritical {
+		t.Errorf("default shuffle is not uniform, chi-square was %.1f", chi)
+	}
+}
+
+func TestShuffleDetectsBias(t *testing.T) {
+	// Swapping every card with any position is a well known biased shuffle.
+	src := mrand.New(mrand.NewSource(1))
+	chi := permChiSquare(24000, func(d *Deck) {
+		for i := range d.Cards {
+			r := src.Intn(len(d.Cards))
+			d.Cards[r], d.Cards[i] = d.Cards[i], d.Cards[r]
+		}
+	})
+	if chi < chiCritical {
+		t.Errorf("expected the biased shuffle to be caught but chi-square was %.1f", chi)
+	}
+}
+
+func TestShufflePositions(t *testing.T) {
+	// Every card should be equally likely to end up in every position of a full deck.
+	const trials = 10000
+	var counts [52][52]int
+	d := NewDeck()
+	d.Source = NewSeedSource([]byte("positions"))
+	index := make(map[string]int)
+	for i, c := range d.Cards {
+		index[c.Rank+c.Suit] = i
+	}
+	for n := 0; n < trials; n++ {
+		d.Shuffle()
+		for pos, c := range d.Cards {
+			counts[index[c.Rank+c.Suit]][pos]++
+		}
+	}
+	expected := float64(trials) / 52
+	chi := 0.0
+	for i := range counts {
+		for j := range counts[i] {
+			diff := float64(counts[i][j]) - expected
+			chi += diff * diff / expected
+		}
+	}
+	// With this many degrees of freedom chi-square is close to normal.
+	dof := 51.0 * 51.0
+	if z := (chi - dof) / math.Sqrt(2*dof); z > 5 {
+		t.Errorf("card positions are not uniform, z was %.2f", z)
+	}
+}
+
+func TestSeedSource(t *testing.T) {
+	d1 := NewDeck()
+	d2 := NewDeck()
+	d1.ShuffleWith(NewSeedSource([]byte("same")))
+	d2.ShuffleWith(NewSeedSource([]byte("same")))
+	for i := range d1.Cards {
+		if d1.Cards[i] != d2.Cards[i] {
+			t.Fatalf("expected the same deal from the same seed")
+		}
+	}
+	d2 = NewDeck()
+	d2.ShuffleWith(NewSeedSource([]byte("different")))
+	same := 0
+	for i := range d1.Cards {
+		if d1.Cards[i] == d2.Cards[i] {
+			same++
+		}
+	}
+	if same == len(d1.Cards) {
+		t.Errorf("expected a different deal from a different seed")
+	}
+	s := NewSeedSource([]byte("range"))
+	for i := 0; i < 1000; i++ {
+		if v := s.Intn(7); v < 0 || v >= 7 {
+			t.Fatalf("Intn(7) returned %d", v)
+		}
+	}
+}
+
+func TestCommitment(t *testing.T) {
+	c, err := NewCommitment()
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(c.Seed) != 32 || len(c.Hash) != 64 {
+		t.Errorf("expected a 32 byte seed and 64 character hash but was %d %d", len(c.Seed), len(c.Hash))
+	}
+	if !VerifySeed(c.SeedString(), c.Hash) {
+		t.Errorf("revealed seed should match the hash")
+	}
+	if VerifySeed("00"+c.SeedString()[2:], c.Hash) && c.SeedString()[:2] != "00" {
+		t.Errorf("a changed seed should not match the hash")
+	}
+	if VerifySeed("not hex", c.Hash) {
+		t.Errorf("a bad seed should not match the hash")
+	}
+	p, err := ParseSeed(c.SeedString())
+	if err != nil || p.Hash != c.Hash {
+		t.Errorf("expected the parsed seed to give the same hash %v", err)
+	}
+	d1 := NewDeck()
+	d1.Source = c.Source()
+	d1.Shuffle()
+	d2 := NewDeck()
+	d2.Source = p.Source()
+	d2.Shuffle()
+	for i := range d1.Cards {
+		if d1.Cards[i] != d2.Cards[i] {
+			t.Fatalf("expected the revealed seed to give the same deal")
+		}
+	}
+}
+
+func TestDailySeed(t *testing.T) {
+	day := time.Date(2026, 10, 19, 20, 0, 0, 0, time.UTC)
+	c := DailySeed("klondike", day)
+	if c.SeedString() != DailySeed("klondike", day.Add(-12*time.Hour)).SeedString() {
+		t.Errorf("Expected the same seed all day")
+	}
+	if c.SeedString() == DailySeed("klondike", day.AddDate(0, 0, 1)).SeedString() {
+		t.Errorf("Expected a different seed the next day")
+	}
+	east := time.FixedZone("east", 10*60*60)
+	if c.SeedString() != DailySeed("klondike", day.In(east)).SeedString() {
+		t.Errorf("Expected the same seed at the same moment in another time zone")
+	}
+	if c.SeedString() == DailySeed("yukon", day).SeedString() {
+		t.Errorf("Expected a different seed for a different game")
+	}
+	p, err := ParseSeed(c.SeedString())
+	if err != nil || p.Hash != c.Hash {
+		t.Errorf("Expected the daily seed to be replayable but was %v", err)
+	}
+}
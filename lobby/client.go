@@ -0,0 +1,51 @@
+package lobby
+
+import (
+	"net"
+)
+
+// Client is a connection to a lobby server.
+// Send and Receive may be used from different goroutines but neither from more than one at a time.
+type Client struct {
+	conn net.Conn
+	t    transport
+}
+
+// Dial connects to the server at addr.
+func Dial(addr string) (*Client, error) {
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(conn), nil
+}
+
+// DialWebSocket connects to the server's WebSocket at a ws:// URL such as ws://localhost:8082/ws.
+func DialWebSocket(url string) (*Client, error) {
+	conn, ws, err := dialWebSocket(url)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{conn: conn, t: ws}, nil
+}
+
+// NewClient returns a client using a connection that is already open.
+func NewClient(conn net.Conn) *Client {
+	return &Client{conn: conn, t: newLines(conn)}
+}
+
+// Send sends a message to the server.
+func (c *Client) Send(m Message) error {
+	return c.t.write(m)
+}
+
+// Receive waits for the next message from the server.
+func (c *Client) Receive() (Message, error) {
+	return c.t.read()
+}
+
+// Close closes the connection.  The server keeps a seat in a game that has started so it can be
+// taken back with its token.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
@@ -0,0 +1,418 @@
+// This program plays hearts at a table on a lobby server, see the lobbyserver program.
+// It uses the ncurses tcell created by Garrett D'Amore which can be gotten by
+// go get -u github.com/gdamore/tcell
+//
+// The game starts once four people have sat down at the table, or when someone seated presses s
+// in which case the computer plays the empty seats.  If the connection to the server is lost the
+// program tries to connect again and take back its seat.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gdamore/tcell"
+	"github.com/tmasterson/cardgames/display"
+	"github.com/tmasterson/cardgames/lobby"
+)
+
+// Define the boxes for easier manipulation
+var tableArea, scoreArea, chatArea display.Box
+
+// Retries is how many times to try connecting again after the connection is lost
+// and retryDelay the wait before each try.
+var retries = 5
+var retryDelay = 2 * time.Second
+
+// chatLines is how many lines of chat are kept.
+const chatLines = 3
+
+// View holds what the client knows of the table.
+// table, name, seat:  What was asked for on the command line, seat -1 for any seat.
+// watch:  Watch the table without sitting down.
+// token:  The token for taking back the seat.
+// selected:  The cards chosen to pass.
+// typing:  A chat line is being typed into line.
+// msg:  The message for the status line.
+type view struct {
+	table    string
+	name     string
+	seat     int
+	watch    bool
+	token    string
+	state    lobby.State
+	selected []string
+	chat     []string
+	typing   bool
+	line     string
+	msg      string
+}
+
+// DrawScreen draws the screen putting all the boxes in place
+// s: The screen variable
+// style: The style for the screen
+//
+// Returns: Returns an error if one occurs otherwise nil
+func drawScreen(s tcell.Screen, style tcell.Style) error {
+	w, h := s.Size()
+	if w < 80 || h < 25 {
+		return errors.New("Screen size must be at least 80 by 25")
+	}
+	title := "Hearts"
+	display.PutString(s, w/2-len(title)/2, 0, style, title)
+	var err error
+	tableArea, err = display.MakeBox(s, "Table", 0, 2, 50, 14, style)
+	if err != nil {
+		return err
+	}
+	scoreArea, err = display.MakeBox(s, "Scores", tableArea.RightX+2, 2, tableArea.RightX+24, 8, style)
+	if err != nil {
+		return err
+	}
+	chatArea, err = display.MakeBox(s, "Chat", scoreArea.LeftX, scoreArea.BotY+1, w-1, tableArea.BotY, style)
+	if err != nil {
+		return err
+	}
+	help := []string{
+		"Press the letter under a card to play it or to choose it for passing.",
+		"Enter passes the cards.  S starts the game with the computer in the empty seats.",
+		"T talks to the table.  Q quits.",
+	}
+	for i, line := range help {
+		display.PutString(s, 0, h-5+i, style, line)
+	}
+	s.Show()
+	return nil
+}
+
+// SeatPosition returns where on the table a seat is shown.  Your own seat is at the bottom.
+func seatPosition(seat, mine int) (int, int) {
+	midX := (tableArea.LeftX + tableArea.RightX) / 2
+	midY := (tableArea.TopY + tableArea.BotY) / 2
+	switch (seat - mine + lobby.Seats) % lobby.Seats {
+	case 1:
+		return tableArea.LeftX + 4, midY
+	case 2:
+		return midX - 1, tableArea.TopY + 2
+	case 3:
+		return tableArea.RightX - 12, midY
+	}
+	return midX - 1, tableArea.BotY - 2
+}
+
+// ShowTable prints the trick, the scores, the chat, the players hand and the status line.
+// s: Screen variable
+// v: The view of the table
+// style: The style for the screen
+func showTable(s tcell.Screen, v *view, style tcell.Style) {
+	w, h := s.Size()
+	st := v.state
+	mine := st.Seat
+	if mine < 0 {
+		mine = 0
+	}
+	blank := strings.Repeat(" ", tableArea.RightX-tableArea.LeftX-1)
+	for y := tableArea.TopY + 1; y < tableArea.BotY; y++ {
+		display.PutString(s, tableArea.LeftX+1, y, style, blank)
+	}
+	trick, leader := st.Trick, st.Leader
+	if len(trick) == 0 {
+		trick, leader = st.LastTrick, st.LastLeader
+	}
+	for i, p := range st.Players {
+		x, y := seatPosition(i, mine)
+		name := p.Name
+		switch {
+		case name == "":
+			name = "(empty)"
+		case !p.Computer && !p.Connected:
+			name += " (away)"
+		case st.Started && !st.Over && !st.Passing && st.Turn == i:
+			name += " *"
+		}
+		display.PutString(s, x, y, style, name)
+	}
+	for i, c := range trick {
+		x, y := seatPosition((leader+i)%lobby.Seats, mine)
+		display.PutString(s, x+1, y+1, style, c)
+	}
+	for i, p := range st.Players {
+		display.PutString(s, scoreArea.LeftX+2, scoreArea.TopY+1+i, style, fmt.Sprintf("%-10.10s %3d %3d", p.Name, p.Score, p.Tricks))
+	}
+	display.PutString(s, scoreArea.LeftX+2, scoreArea.TopY+5, style, fmt.Sprintf("Pass: %-8s", st.Pass))
+	cw := chatArea.RightX - chatArea.LeftX - 1
+	for i := 0; i < chatLines; i++ {
+		line := ""
+		if i < len(v.chat) {
+			line = v.chat[i]
+		}
+		if len(line) > cw {
+			line = line[:cw]
+		}
+		display.PutString(s, chatArea.LeftX+1, chatArea.TopY+1+i, style, line+strings.Repeat(" ", cw-len(line)))
+	}
+	hy := tableArea.BotY + 2
+	for y := hy; y < hy+3; y++ {
+		display.PutString(s, 0, y, style, strings.Repeat(" ", w-1))
+	}
+	for i, c := range st.Hand {
+		display.PutString(s, i*5+1, hy, style, c)
+		display.PutString(s, i*5+1, hy+1, style, string(rune('a'+i)))
+		if contains(v.selected, c) {
+			display.PutString(s, i*5+1, hy+2, style, "^^")
+		}
+	}
+	status := v.msg
+	switch {
+	case v.typing:
+		status = "Say: " + v.line
+	case status == "":
+		status = st.Msg
+	}
+	display.PutString(s, 0, h-1, style, strings.Repeat(" ", w-1))
+	display.PutString(s, 0, h-1, style, status)
+	s.Show()
+}
+
+// contains tells if a card is in a list of cards.
+func contains(cards []string, c string) bool {
+	for _, x := range cards {
+		if x == c {
+			return true
+		}
+	}
+	return false
+}
+
+// hello returns the message to send on connecting, taking back the seat if there is a token.
+func (v *view) hello() lobby.Message {
+	switch {
+	case v.token != "":
+		return lobby.Message{Type: lobby.TypeSeat, Table: v.table, Token: v.token}
+	case v.watch:
+		return lobby.Message{Type: lobby.TypeJoin, Table: v.table}
+	}
+	return lobby.Message{Type: lobby.TypeSeat, Table: v.table, Seat: v.seat, Name: v.name}
+}
+
+// Receive updates the view with a message from the server.
+func (v *view) receive(m lobby.Message) {
+	switch m.Type {
+	case lobby.TypeSeated:
+		v.token = m.Token
+		v.seat = m.Seat
+	case lobby.TypeState:
+		v.state = *m.State
+		if !v.state.Passing || v.state.Passed {
+			v.selected = v.selected[:0]
+		}
+		v.msg = ""
+	case lobby.TypeChat:
+		v.chat = append(v.chat, m.Name+": "+m.Text)
+		if len(v.chat) > chatLines {
+			v.chat = v.chat[len(v.chat)-chatLines:]
+		}
+	case lobby.TypeError:
+		v.msg = m.Error + "."
+	}
+}
+
+// ProcessKey handles the processing of key strokes.
+//
+// ev:  The event that that contains the key.
+// v:  The view the key acts on.
+//
+// returns: A message to send to the server or nil, and false if the player wants to quit
+func processKey(ev *tcell.EventKey, v *view) (*lobby.Message, bool) {
+	if v.typing {
+		switch ev.Key() {
+		case tcell.KeyEnter:
+			v.typing = false
+			if v.line != "" {
+				m := &lobby.Message{Type: lobby.TypeChat, Text: v.line}
+				v.line = ""
+				return m, true
+			}
+		case tcell.KeyEscape:
+			v.typing = false
+			v.line = ""
+		case tcell.KeyBackspace, tcell.KeyBackspace2:
+			if r := []rune(v.line); len(r) > 0 {
+				v.line = string(r[:len(r)-1])
+			}
+		case tcell.KeyRune:
+			v.line += string(ev.Rune())
+		}
+		return nil, true
+	}
+	st := v.state
+	choosing := st.Passing && !st.Passed
+	switch {
+	case ev.Key() == tcell.KeyEnter:
+		if !choosing {
+			break
+		}
+		if len(v.selected) != 3 {
+			v.msg = "Choose exactly three cards to pass."
+			break
+		}
+		return &lobby.Message{Type: lobby.TypeAction, Action: lobby.ActionPass, Cards: append([]string(nil), v.selected...)}, true
+	case ev.Key() != tcell.KeyRune:
+	case unicode.ToUpper(ev.Rune()) == 'Q':
+		return nil, false
+	case unicode.ToUpper(ev.Rune()) == 'T':
+		v.typing = true
+	case unicode.ToUpper(ev.Rune()) == 'S':
+		return &lobby.Message{Type: lobby.TypeAction, Action: lobby.ActionStart}, true
+	case ev.Rune() >= 'a' && int(ev.Rune()-'a') < len(st.Hand):
+		c := st.Hand[ev.Rune()-'a']
+		if !choosing {
+			return &lobby.Message{Type: lobby.TypeAction, Action: lobby.ActionPlay, Cards: []string{c}}, true
+		}
+		for i, x := range v.selected {
+			if x == c {
+				v.selected = append(v.selected[:i], v.selected[i+1:]...)
+				return nil, true
+			}
+		}
+		if len(v.selected) < 3 {
+			v.selected = append(v.selected, c)
+		}
+	}
+	return nil, true
+}
+
+// Connect connects to the server and sends the message to sit down or take back the seat.
+// An address starting ws:// is connected to as a WebSocket.
+func connect(addr string, v *view) (*lobby.Client, error) {
+	dial := lobby.Dial
+	if strings.HasPrefix(addr, "ws://") {
+		dial = lobby.DialWebSocket
+	}
+	c, err := dial(addr)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.Send(v.hello()); err != nil {
+		c.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// Listen reads messages from the server and posts them to the screen as they arrive.
+// When the connection is lost the error is posted instead.
+func listen(s tcell.Screen, c *lobby.Client) {
+	for {
+		m, err := c.Receive()
+		if err != nil {
+			s.PostEvent(tcell.NewEventInterrupt(err))
+			return
+		}
+		s.PostEvent(tcell.NewEventInterrupt(m))
+	}
+}
+
+// PlayGame is the main function that handles all aspects of the game.
+//
+// s: Screen variable.
+// c: The connection to the server.
+// addr: The server's address for connecting again.
+// v: The view of the table.
+// style: The style for the screen.
+//
+// returns: An error if the connection was lost and could not be made again.
+func playGame(s tcell.Screen, c *lobby.Client, addr string, v *view, style tcell.Style) error {
+	go listen(s, c)
+	defer func() { c.Close() }()
+	for {
+		showTable(s, v, style)
+		switch ev := s.PollEvent().(type) {
+		case *tcell.EventKey:
+			if ev.Key() == tcell.KeyCtrlL {
+				s.Sync()
+				break
+			}
+			m, ok := processKey(ev, v)
+			if !ok {
+				return nil
+			}
+			if m != nil {
+				// a failed send is noticed by listen
+				c.Send(*m)
+			}
+		case *tcell.EventInterrupt:
+			switch data := ev.Data().(type) {
+			case lobby.Message:
+				v.receive(data)
+			case error:
+				err := data
+				for i := 0; i < retries && err != nil; i++ {
+					v.msg = fmt.Sprintf("Lost the connection, trying again (%d of %d).", i+1, retries)
+					showTable(s, v, style)
+					time.Sleep(retryDelay)
+					var nc *lobby.Client
+					if nc, err = connect(addr, v); err == nil {
+						c = nc
+					}
+				}
+				if err != nil {
+					return err
+				}
+				v.msg = ""
+				go listen(s, c)
+			}
+		}
+	}
+}
+
+func main() {
+	addrptr := flag.String("addr", "localhost:8081", "The address of the lobby server, or its ws:// URL")
+	tableptr := flag.String("table", "hearts", "The table to play at")
+	nameptr := flag.String("name", os.Getenv("USER"), "Your name at the table")
+	seatptr := flag.Int("seat", -1, "The seat to take from 0 to 3, -1 for any free seat")
+	tokenptr := flag.String("token", "", "Take back a seat in a game that has started")
+	watchptr := flag.Bool("watch", false, "Watch the table without sitting down")
+	flag.Parse()
+	v := &view{table: *tableptr, name: *nameptr, seat: *seatptr, watch: *watchptr, token: *tokenptr}
+	c, err := connect(*addrptr, v)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	tcell.SetEncodingFallback(tcell.EncodingFallbackASCII)
+	s, e := tcell.NewScreen()
+	if e != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", e)
+		os.Exit(1)
+	}
+	if e = s.Init(); e != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", e)
+		os.Exit(1)
+	}
+	s.Clear()
+	s.HideCursor()
+	if err := drawScreen(s, tcell.StyleDefault); err != nil {
+		s.Fini()
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	err = playGame(s, c, *addrptr, v, tcell.StyleDefault)
+	s.Fini()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+	}
+	for _, p := range v.state.Players {
+		fmt.Printf("%-10s %d\n", p.Name, p.Score)
+	}
+	if v.state.Over {
+		fmt.Println(v.state.Msg)
+	} else if v.token != "" && v.state.Started {
+		fmt.Printf("Your seat is kept for you, come back with -token %s\n", v.token)
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"net"
+	"testing"
+
+	"github.com/gdamore/tcell"
+	"github.com/tmasterson/cardgames/lobby"
+)
+
+func key(r rune) *tcell.EventKey {
+	return tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone)
+}
+
+func TestProcessKey(t *testing.T) {
+	v := &view{state: lobby.State{Started: true, Passing: true, Hand: []string{"2C", "5C", "QS", "AH"}}}
+	for _, k := range "abbcd" {
+		if m, _ := processKey(key(k), v); m != nil {
+			t.Errorf("Expected no message while choosing but was %+v", m)
+		}
+	}
+	if len(v.selected) != 3 || v.selected[0] != "2C" || v.selected[1] != "QS" || v.selected[2] != "AH" {
+		t.Errorf("Expected 2C QS AH chosen but was %v", v.selected)
+	}
+	m, _ := processKey(tcell.NewEventKey(tcell.KeyEnter, ' ', tcell.ModNone), v)
+	if m == nil || m.Action != lobby.ActionPass || len(m.Cards) != 3 {
+		t.Fatalf("Expected the three cards passed but was %+v", m)
+	}
+	v.receive(lobby.Message{Type: lobby.TypeState, State: &lobby.State{Started: true, Hand: []string{"2C", "5C"}}})
+	if len(v.selected) != 0 {
+		t.Errorf("Expected the choice cleared once passing is over")
+	}
+	if m, _ := processKey(key('b'), v); m == nil || m.Action != lobby.ActionPlay || m.Cards[0] != "5C" {
+		t.Errorf("Expected 5C played but was %+v", m)
+	}
+	processKey(key('t'), v)
+	for _, k := range "hix" {
+		processKey(key(k), v)
+	}
+	processKey(tcell.NewEventKey(tcell.KeyBackspace2, 0, tcell.ModNone), v)
+	if m, _ := processKey(tcell.NewEventKey(tcell.KeyEnter, ' ', tcell.ModNone), v); m == nil || m.Type != lobby.TypeChat || m.Text != "hi" || v.typing {
+		t.Errorf("Expected to say hi but was %+v", m)
+	}
+	if _, ok := processKey(key('q'), v); ok {
+		t.Errorf("Expected q to quit")
+	}
+}
+
+func TestReceive(t *testing.T) {
+	v := &view{table: "t", seat: -1, name: "Ann"}
+	if m := v.hello(); m.Type != lobby.TypeSeat || m.Name != "Ann" || m.Token != "" {
+		t.Errorf("Expected to ask for a seat but was %+v", m)
+	}
+	v.receive(lobby.Message{Type: lobby.TypeSeated, Seat: 2, Token: "abc"})
+	if m := v.hello(); m.Token != "abc" || v.seat != 2 {
+		t.Errorf("Expected to take back the seat with the token but was %+v", m)
+	}
+	for _, text := range []string{"1", "2", "3", "4"} {
+		v.receive(lobby.Message{Type: lobby.TypeChat, Name: "Bob", Text: text})
+	}
+	if len(v.chat) != chatLines || v.chat[0] != "Bob: 2" {
+		t.Errorf("Expected the last %d lines of chat but was %v", chatLines, v.chat)
+	}
+	v.receive(lobby.Message{Type: lobby.TypeError, Error: "no"})
+	if v.msg != "no." {
+		t.Errorf("Expected the error shown but was %q", v.msg)
+	}
+}
+
+// wait feeds messages from the server to a view until one of type typ.
+func wait(t *testing.T, c *lobby.Client, v *view, typ string) {
+	for {
+		m, err := c.Receive()
+		if err != nil {
+			t.Fatalf("Expected a %s message but was %v", typ, err)
+		}
+		v.receive(m)
+		if m.Type == typ {
+			return
+		}
+	}
+}
+
+func TestReconnect(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	go lobby.NewServer(100).Serve(l)
+	addr := l.Addr().String()
+	v := &view{table: "t", seat: 1}
+	c, err := connect(addr, v)
+	if err != nil {
+		t.Fatalf("Expected to connect but was %v", err)
+	}
+	wait(t, c, v, lobby.TypeSeated)
+	wait(t, c, v, lobby.TypeState)
+	c.Send(lobby.Message{Type: lobby.TypeAction, Action: lobby.ActionStart})
+	wait(t, c, v, lobby.TypeState)
+	hand := v.state.Hand
+	c.Close()
+	if c, err = connect(addr, v); err != nil {
+		t.Fatalf("Expected to connect again but was %v", err)
+	}
+	defer c.Close()
+	wait(t, c, v, lobby.TypeState)
+	if v.seat != 1 || len(v.state.Hand) != 13 || v.state.Hand[0] != hand[0] || !v.state.Players[1].Connected {
+		t.Errorf("Expected to be back in seat 1 with the same hand but was %+v", v.state)
+	}
+}
+
+func TestDrawScreen(t *testing.T) {
+	s := tcell.NewSimulationScreen("")
+	if e := s.Init(); e != nil {
+		t.Fatalf("Failed to initialize screen: %v", e)
+	}
+	defer s.Fini()
+	s.SetSize(30, 10)
+	if err := drawScreen(s, tcell.StyleDefault); err == nil {
+		t.Errorf("Expected an error here")
+	}
+	s.SetSize(80, 25)
+	if err := drawScreen(s, tcell.StyleDefault); err != nil {
+		t.Errorf("There should be no errors")
+	}
+	v := &view{state: lobby.State{Seat: 2, Players: make([]lobby.PlayerState, lobby.Seats), Hand: []string{"2C"}, Trick: []string{"3C"}}}
+	v.chat = []string{"Bob: a long line of chat that goes past the edge of the chat box"}
+	showTable(s, v, tcell.StyleDefault)
+}
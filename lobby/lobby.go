@@ -0,0 +1,259 @@
+package lobby
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/tmasterson/cardgames/generic"
+)
+
+// Outgoing is how many messages may wait to be sent to a client.  A client that falls further
+// behind than this is disconnected so it can not hold up the table.
+const outgoing = 64
+
+// DefaultAbandon is how long a game that has started is kept once everyone has left its table.
+const DefaultAbandon = 30 * time.Minute
+
+// Server holds the tables and the clients connected to them.
+// A Server is safe to use from more than one goroutine.
+// Abandon: How long a game that has started is kept for its players to come back once there is
+// no one at the table, set before serving
+type Server struct {
+	Abandon time.Duration
+	mu      sync.Mutex
+	tables  map[string]*table
+	target  int
+	source  generic.Source
+}
+
+// client is a connection to the server.
+// seat is -1 when the client is not seated.
+type client struct {
+	conn  net.Conn
+	t     transport
+	send  chan Message
+	table *table
+	seat  int
+}
+
+// NewServer returns a server whose games end when a player reaches target points.
+func NewServer(target int) *Server {
+	return &Server{Abandon: DefaultAbandon, tables: make(map[string]*table), target: target, source: generic.CryptoSource{}}
+}
+
+// Serve accepts connections on l until it is closed.
+func (sv *Server) Serve(l net.Listener) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+		go sv.handle(conn, newLines(conn))
+	}
+}
+
+// ServeHTTP takes a WebSocket connection, such as one from a web page, and carries the same
+// messages over it as Serve does, one to each text frame.
+func (sv *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, ws, err := upgrade(w, r)
+	if err != nil {
+		return
+	}
+	sv.handle(conn, ws)
+}
+
+// handle reads the messages from a connection until it closes.
+func (sv *Server) handle(conn net.Conn, t transport) {
+	c := &client{conn: conn, t: t, send: make(chan Message, outgoing), seat: -1}
+	go c.write()
+	for {
+		m, err := t.read()
+		if err != nil {
+			if err != io.EOF {
+				c.deliver(Message{Type: TypeError, Error: err.Error()})
+			}
+			break
+		}
+		sv.mu.Lock()
+		sv.receive(c, m)
+		sv.mu.Unlock()
+	}
+	sv.mu.Lock()
+	sv.leave(c)
+	close(c.send)
+	sv.mu.Unlock()
+}
+
+// write sends the messages for a client until its channel is closed and then closes the connection.
+func (c *client) write() {
+	for m := range c.send {
+		if err := c.t.write(m); err != nil {
+			c.conn.Close()
+		}
+	}
+	c.conn.Close()
+}
+
+// deliver queues a message for a client, dropping the connection if it is too far behind.
+func (c *client) deliver(m Message) {
+	select {
+	case c.send <- m:
+	default:
+		c.conn.Close()
+	}
+}
+
+// newToken returns a random token for a seat.
+func newToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// receive acts on a message from a client.  The server must be locked.
+func (sv *Server) receive(c *client, m Message) {
+	var err error
+	switch m.Type {
+	case TypeTables:
+		tables := []TableInfo{}
+		for _, t := range sv.tables {
+			tables = append(tables, t.info())
+		}
+		sort.Slice(tables, func(i, j int) bool { return tables[i].Name < tables[j].Name })
+		c.deliver(Message{Type: TypeTables, Tables: tables})
+		return
+	case TypeJoin:
+		err = sv.join(c, m.Table)
+	case TypeSeat:
+		err = sv.sit(c, m)
+	case TypeChat:
+		if c.table == nil {
+			err = errors.New("join a table first")
+			break
+		}
+		name := "someone"
+		if c.seat >= 0 {
+			name = c.table.seats[c.seat].name
+		}
+		for o := range c.table.clients {
+			o.deliver(Message{Type: TypeChat, Table: c.table.name, Seat: c.seat, Name: name, Text: m.Text})
+		}
+		return
+	case TypeAction:
+		if c.seat < 0 {
+			err = errors.New("take a seat first")
+			break
+		}
+		err = c.table.act(c.seat, m.Action, m.Cards)
+	case TypeLeave:
+		sv.leave(c)
+		return
+	default:
+		err = errors.New("no such message type " + m.Type)
+	}
+	if err != nil {
+		c.deliver(Message{Type: TypeError, Seat: c.seat, Error: err.Error()})
+		return
+	}
+	sv.update(c.table)
+}
+
+// join puts a client at a table to watch, making the table if it does not exist.
+func (sv *Server) join(c *client, name string) error {
+	if name == "" {
+		return errors.New("a table needs a name")
+	}
+	if c.table != nil && c.table.name == name {
+		return nil
+	}
+	sv.leave(c)
+	t, ok := sv.tables[name]
+	if !ok {
+		t = newTable(name, sv.target, sv.source)
+		sv.tables[name] = t
+	}
+	if t.expiry != nil {
+		t.expiry.Stop()
+		t.expiry = nil
+	}
+	t.clients[c] = true
+	c.table = t
+	return nil
+}
+
+// sit gives a client a seat, or gives back the seat holding the token in the message.
+func (sv *Server) sit(c *client, m Message) error {
+	if err := sv.join(c, m.Table); err != nil {
+		return err
+	}
+	t := c.table
+	if c.seat >= 0 {
+		t.stand(c)
+	}
+	if m.Token != "" {
+		if err := t.reclaim(c, m.Token); err != nil {
+			return err
+		}
+		c.deliver(Message{Type: TypeSeated, Table: t.name, Seat: c.seat, Token: m.Token})
+		return nil
+	}
+	token, err := newToken()
+	if err != nil {
+		return err
+	}
+	if err := t.sit(c, m.Seat, m.Name, token); err != nil {
+		return err
+	}
+	c.deliver(Message{Type: TypeSeated, Table: t.name, Seat: c.seat, Token: token})
+	return nil
+}
+
+// leave takes a client away from its table, throwing the table away if no one is left
+// and there is no game to come back to.  A game that has started is thrown away if no one
+// comes back to it within the Abandon time.
+func (sv *Server) leave(c *client) {
+	t := c.table
+	if t == nil {
+		return
+	}
+	t.stand(c)
+	delete(t.clients, c)
+	c.table = nil
+	if len(t.clients) == 0 {
+		if !t.playing() {
+			delete(sv.tables, t.name)
+			return
+		}
+		t.expiry = time.AfterFunc(sv.Abandon, func() { sv.expire(t) })
+	}
+	sv.update(t)
+}
+
+// expire throws away a table that is still empty when its time is up.
+func (sv *Server) expire(t *table) {
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+	if len(t.clients) == 0 && sv.tables[t.name] == t {
+		delete(sv.tables, t.name)
+	}
+}
+
+// update sends everyone at a table the state as seen from their seat.
+func (sv *Server) update(t *table) {
+	if t == nil {
+		return
+	}
+	for c := range t.clients {
+		st := t.state(c.seat)
+		c.deliver(Message{Type: TypeState, Table: t.name, Seat: c.seat, State: &st})
+	}
+}
@@ -0,0 +1,228 @@
+package lobby
+
+import (
+	"encoding/json"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// serve starts a server on a loopback port and returns its address and a function to stop it.
+func serve(t *testing.T) (string, func()) {
+	l := listen(t, NewServer(100))
+	return l.addr, l.stop
+}
+
+// listener is a server listening on a loopback port.
+type listener struct {
+	addr string
+	stop func()
+}
+
+// listen serves TCP connections for a server on a loopback port.
+func listen(t *testing.T, sv *Server) listener {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Expected to listen on loopback but was %v", err)
+	}
+	go sv.Serve(l)
+	return listener{addr: l.Addr().String(), stop: func() { l.Close() }}
+}
+
+// dial connects a client to a server.
+func dial(t *testing.T, addr string) *Client {
+	c, err := Dial(addr)
+	if err != nil {
+		t.Fatalf("Expected to connect but was %v", err)
+	}
+	return c
+}
+
+// expect sends a message if it is not empty and then reads messages until one of type typ.
+func expect(t *testing.T, c *Client, m Message, typ string) Message {
+	if m.Type != "" {
+		if err := c.Send(m); err != nil {
+			t.Fatalf("Expected to send %v but was %v", m, err)
+		}
+	}
+	t.Helper()
+	c.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for {
+		r, err := c.Receive()
+		if err != nil {
+			t.Fatalf("Expected a %s message but was %v", typ, err)
+		}
+		if r.Type == typ {
+			return r
+		}
+		if r.Type == TypeError {
+			t.Fatalf("Expected a %s message but was the error %s", typ, r.Error)
+		}
+	}
+}
+
+// state reads messages until the next state.
+func state(t *testing.T, c *Client) State {
+	t.Helper()
+	return *expect(t, c, Message{}, TypeState).State
+}
+
+func TestPlay(t *testing.T) {
+	addr, stop := serve(t)
+	defer stop()
+	a, b, w := dial(t, addr), dial(t, addr), dial(t, addr)
+	defer a.Close()
+	defer b.Close()
+	defer w.Close()
+	if m := expect(t, a, Message{Type: TypeSeat, Table: "t", Seat: -1, Name: "Ann"}, TypeSeated); m.Seat != 0 || m.Token == "" {
+		t.Errorf("Expected the first seat with a token but was %+v", m)
+	}
+	state(t, a)
+	if m := expect(t, b, Message{Type: TypeSeat, Table: "t", Seat: 1}, TypeSeated); m.Seat != 1 {
+		t.Errorf("Expected the second seat but was %+v", m)
+	}
+	state(t, b)
+	state(t, a)
+	expect(t, w, Message{Type: TypeJoin, Table: "t"}, TypeState)
+	state(t, a)
+	state(t, b)
+	m := expect(t, a, Message{Type: TypeAction, Action: ActionStart}, TypeState)
+	sa, sb, sw := *m.State, state(t, b), state(t, w)
+	if !sa.Started || !sa.Passing || len(sa.Hand) != 13 || sb.Players[0].Name != "Ann" || sb.Players[1].Name != "West" || !sb.Players[2].Computer {
+		t.Fatalf("Expected the game started with computers in the empty seats but was %+v", sb)
+	}
+	if len(sw.Hand) != 0 || sw.Players[0].Cards != 13 {
+		t.Errorf("Expected someone watching to see no hand but was %v", sw.Hand)
+	}
+	data, _ := json.Marshal(sa)
+	for _, c := range sb.Hand {
+		if strings.Contains(string(data), `"`+c+`"`) {
+			t.Errorf("Expected West's %s to be hidden from South", c)
+		}
+	}
+	expect(t, a, Message{Type: TypeAction, Action: ActionPass, Cards: sa.Hand[:3]}, TypeState)
+	state(t, b)
+	state(t, w)
+	sb = *expect(t, b, Message{Type: TypeAction, Action: ActionPass, Cards: sb.Hand[:3]}, TypeState).State
+	sa = state(t, a)
+	state(t, w)
+	for sa.Number == 0 {
+		mine, other := a, b
+		s := sa
+		if len(sb.Legal) > 0 {
+			mine, other, s = b, a, sb
+		}
+		if len(s.Legal) == 0 {
+			t.Fatalf("Expected someone to have a card to play but was %+v", s)
+		}
+		if e := expect(t, other, Message{Type: TypeAction, Action: ActionPlay, Cards: s.Legal[:1]}, TypeError); e.Error == "" {
+			t.Errorf("Expected a play out of turn to be refused")
+		}
+		ms := *expect(t, mine, Message{Type: TypeAction, Action: ActionPlay, Cards: s.Legal[:1]}, TypeState).State
+		os := state(t, other)
+		state(t, w)
+		if mine == a {
+			sa, sb = ms, os
+		} else {
+			sa, sb = os, ms
+		}
+	}
+	total := 0
+	for _, p := range sa.Points {
+		total += p
+	}
+	if (total != 26 && total != 78) || sa.Pass != "right" || len(sa.Hand) != 13 {
+		t.Errorf("Expected the second hand passing right after 26 points but was %+v", sa)
+	}
+}
+
+func TestReconnect(t *testing.T) {
+	addr, stop := serve(t)
+	defer stop()
+	a, b := dial(t, addr), dial(t, addr)
+	defer b.Close()
+	token := expect(t, a, Message{Type: TypeSeat, Table: "t", Seat: 3}, TypeSeated).Token
+	state(t, a)
+	expect(t, b, Message{Type: TypeSeat, Table: "t", Seat: 0}, TypeSeated)
+	state(t, a)
+	state(t, b)
+	hand := *expect(t, a, Message{Type: TypeAction, Action: ActionStart}, TypeState).State
+	state(t, b)
+	a.Close()
+	if st := state(t, b); st.Players[3].Connected || st.Players[3].Computer {
+		t.Errorf("Expected East to be away but was %+v", st.Players[3])
+	}
+	c := dial(t, addr)
+	defer c.Close()
+	if e := expect(t, c, Message{Type: TypeSeat, Table: "t", Token: "wrong"}, TypeError); e.Error == "" {
+		t.Errorf("Expected a wrong token to be refused")
+	}
+	if m := expect(t, c, Message{Type: TypeSeat, Table: "t", Token: token}, TypeSeated); m.Seat != 3 {
+		t.Errorf("Expected to be back in seat 3 but was %d", m.Seat)
+	}
+	st := state(t, c)
+	if strings.Join(st.Hand, " ") != strings.Join(hand.Hand, " ") || !st.Players[3].Connected {
+		t.Errorf("Expected the same hand back but was %v and %v", st.Hand, hand.Hand)
+	}
+	if e := expect(t, c, Message{Type: TypeSeat, Table: "t", Seat: 1}, TypeError); e.Error == "" {
+		t.Errorf("Expected no new seats once the game has started")
+	}
+}
+
+func TestLobby(t *testing.T) {
+	addr, stop := serve(t)
+	defer stop()
+	a, b := dial(t, addr), dial(t, addr)
+	defer a.Close()
+	defer b.Close()
+	if m := expect(t, a, Message{Type: TypeTables}, TypeTables); len(m.Tables) != 0 {
+		t.Errorf("Expected no tables but was %v", m.Tables)
+	}
+	if e := expect(t, a, Message{Type: TypeChat, Text: "hello"}, TypeError); e.Error == "" {
+		t.Errorf("Expected chat away from a table to be refused")
+	}
+	expect(t, a, Message{Type: TypeSeat, Table: "red", Seat: 2, Name: "Ann"}, TypeSeated)
+	expect(t, b, Message{Type: TypeJoin, Table: "red"}, TypeState)
+	m := expect(t, b, Message{Type: TypeTables}, TypeTables)
+	if len(m.Tables) != 1 || m.Tables[0] != (TableInfo{Name: "red", Seated: 1}) {
+		t.Errorf("Expected the red table with one seated but was %v", m.Tables)
+	}
+	if m := expect(t, a, Message{Type: TypeChat, Text: "hello"}, TypeChat); m.Name != "Ann" || m.Text != "hello" {
+		t.Errorf("Expected Ann to say hello but was %+v", m)
+	}
+	if m := expect(t, b, Message{}, TypeChat); m.Name != "Ann" || m.Seat != 2 {
+		t.Errorf("Expected to hear Ann but was %+v", m)
+	}
+	a.Send(Message{Type: TypeLeave})
+	if st := state(t, b); st.Players[2].Name != "" {
+		t.Errorf("Expected the seat free again before the game has started but was %+v", st.Players[2])
+	}
+	b.Send(Message{Type: TypeLeave})
+	if m := expect(t, b, Message{Type: TypeTables}, TypeTables); len(m.Tables) != 0 {
+		t.Errorf("Expected the empty table thrown away but was %v", m.Tables)
+	}
+}
+
+func TestAbandon(t *testing.T) {
+	sv := NewServer(100)
+	sv.Abandon = 100 * time.Millisecond
+	l := listen(t, sv)
+	defer l.stop()
+	a, b := dial(t, l.addr), dial(t, l.addr)
+	defer b.Close()
+	token := expect(t, a, Message{Type: TypeSeat, Table: "t", Seat: 0}, TypeSeated).Token
+	state(t, a)
+	expect(t, a, Message{Type: TypeAction, Action: ActionStart}, TypeState)
+	a.Close()
+	time.Sleep(50 * time.Millisecond)
+	c := dial(t, l.addr)
+	if m := expect(t, c, Message{Type: TypeSeat, Table: "t", Token: token}, TypeSeated); m.Seat != 0 {
+		t.Errorf("Expected the seat back before the game was abandoned but was %d", m.Seat)
+	}
+	c.Close()
+	time.Sleep(300 * time.Millisecond)
+	if m := expect(t, b, Message{Type: TypeTables}, TypeTables); len(m.Tables) != 0 {
+		t.Errorf("Expected the abandoned game thrown away but was %v", m.Tables)
+	}
+}
@@ -0,0 +1,49 @@
+// This program runs a lobby where people can meet to play hearts over the network with the
+// heartsclient program.  See the lobby package for the protocol.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+
+	"github.com/tmasterson/cardgames/lobby"
+)
+
+func main() {
+	addrptr := flag.String("addr", "localhost:8081", "The address to listen on")
+	wsptr := flag.String("ws", "localhost:8082", "The address to accept WebSocket connections on at /ws, empty for none")
+	targetptr := flag.Int("target", 100, "Score that ends a game")
+	abandonptr := flag.Duration("abandon", lobby.DefaultAbandon, "How long a game is kept once everyone has left it")
+	flag.Parse()
+	l, err := net.Listen("tcp", *addrptr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	sv := lobby.NewServer(*targetptr)
+	sv.Abandon = *abandonptr
+	if *wsptr != "" {
+		wl, err := net.Listen("tcp", *wsptr)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+		mux := http.NewServeMux()
+		mux.Handle("/ws", sv)
+		go func() {
+			if err := http.Serve(wl, mux); err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
+				os.Exit(1)
+			}
+		}()
+		fmt.Printf("Lobby open to WebSockets at ws://%s/ws\n", wl.Addr())
+	}
+	fmt.Printf("Lobby open on %s\n", l.Addr())
+	if err := sv.Serve(l); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+}
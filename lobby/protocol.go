@@ -0,0 +1,115 @@
+// Package lobby lets people play hearts with each other over the network.  A server holds tables
+// that clients join to watch, take a seat at, chat at and play at.  The server owns the deck and
+// the game so a client is only ever sent its own hand, never the cards of the other players.
+//
+// The protocol is JSON lines over TCP, one Message per line, or the same messages one to each
+// text frame of a WebSocket, which the Server accepts as an http.Handler.  A message may be at
+// most a megabyte and a WebSocket from a web page must come from a page on the server's own host.
+// A client sends
+//
+//	{"type": "tables"}                                  List the tables
+//	{"type": "join", "table": "t"}                      Watch a table, it is made if it does not exist
+//	{"type": "seat", "table": "t", "seat": 2, "name": "Ann"}  Sit down, seat -1 takes any free seat
+//	{"type": "seat", "table": "t", "token": "..."}      Take back a seat after losing the connection
+//	{"type": "chat", "text": "hello"}                   Talk to the table
+//	{"type": "action", "action": "start"}               Start the game, empty seats are played by the computer
+//	{"type": "action", "action": "pass", "cards": ["QS", "AH", "KH"]}
+//	{"type": "action", "action": "play", "cards": ["2C"]}
+//	{"type": "leave"}                                   Leave the table
+//
+// and the server answers with tables, seated (giving the seat and its token), chat, state and
+// error messages.  A state message is sent to everyone at a table whenever anything changes.
+//
+// Once a game has started a seat is kept when its player's connection is lost and play waits
+// for them to come back with the token they were given when they sat down.  A game that everyone
+// has left is thrown away if no one comes back to it within the server's Abandon time.
+package lobby
+
+// The types of message.
+const (
+	TypeTables = "tables"
+	TypeJoin   = "join"
+	TypeSeat   = "seat"
+	TypeSeated = "seated"
+	TypeChat   = "chat"
+	TypeAction = "action"
+	TypeLeave  = "leave"
+	TypeState  = "state"
+	TypeError  = "error"
+)
+
+// The actions a seated player can take.
+const (
+	ActionStart = "start"
+	ActionPass  = "pass"
+	ActionPlay  = "play"
+)
+
+// Seats is the number of seats at a table.
+const Seats = 4
+
+// SeatNames are the names of the seats, used for players who do not give one.
+var SeatNames = []string{"South", "West", "North", "East"}
+
+// Message is a line of the protocol.  Every message has a Type and only the fields it needs.
+// Seat is -1 for any seat in a seat request.
+// Cards are named by rank and suit such as TH or QS.
+type Message struct {
+	Type   string      `json:"type"`
+	Table  string      `json:"table,omitempty"`
+	Seat   int         `json:"seat"`
+	Name   string      `json:"name,omitempty"`
+	Token  string      `json:"token,omitempty"`
+	Text   string      `json:"text,omitempty"`
+	Action string      `json:"action,omitempty"`
+	Cards  []string    `json:"cards,omitempty"`
+	Tables []TableInfo `json:"tables,omitempty"`
+	State  *State      `json:"state,omitempty"`
+	Error  string      `json:"error,omitempty"`
+}
+
+// TableInfo describes a table in the list of tables.
+type TableInfo struct {
+	Name    string `json:"name"`
+	Seated  int    `json:"seated"`
+	Playing bool   `json:"playing"`
+}
+
+// PlayerState is what everyone can see of a seat.
+// Cards: The number of cards in the hand
+// Tricks: The number of tricks taken this hand
+type PlayerState struct {
+	Name      string `json:"name"`
+	Computer  bool   `json:"computer"`
+	Connected bool   `json:"connected"`
+	Cards     int    `json:"cards"`
+	Tricks    int    `json:"tricks"`
+	Score     int    `json:"score"`
+}
+
+// State is a table as seen from one seat.  Only that seat's hand is included.
+// Seat: The seat it is seen from, -1 for someone watching
+// Legal: The cards the seat may play now
+// Number: The number of hands finished, which sets the pass direction
+// Passed: The seat has chosen its cards to pass and is waiting for the others
+// Points: The points each seat took in the last hand
+type State struct {
+	Table      string        `json:"table"`
+	Seat       int           `json:"seat"`
+	Players    []PlayerState `json:"players"`
+	Started    bool          `json:"started"`
+	Hand       []string      `json:"hand"`
+	Legal      []string      `json:"legal"`
+	Trick      []string      `json:"trick"`
+	Leader     int           `json:"leader"`
+	LastTrick  []string      `json:"lasttrick"`
+	LastLeader int           `json:"lastleader"`
+	Turn       int           `json:"turn"`
+	Number     int           `json:"number"`
+	Pass       string        `json:"pass"`
+	Passing    bool          `json:"passing"`
+	Passed     bool          `json:"passed"`
+	Points     []int         `json:"points"`
+	Msg        string        `json:"msg"`
+	Over       bool          `json:"over"`
+}
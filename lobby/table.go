@@ -0,0 +1,337 @@
+package lobby
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tmasterson/cardgames/generic"
+	"github.com/tmasterson/cardgames/tricks"
+	"github.com/tmasterson/cardgames/tricks/hearts/engine"
+)
+
+// seat is a place at a table.
+// token: Given to the player who sits down so they can take the seat back, empty for a free seat
+// client: The player's connection, nil while they are away
+type seat struct {
+	name     string
+	token    string
+	client   *client
+	computer bool
+}
+
+// table is a game of hearts and the people at it.
+// clients: Everyone at the table whether seated or watching
+// hand: The number of hands finished, which sets the pass direction
+// passes: The cards each seat has chosen to pass, nil until it has chosen
+// points: The points taken in the last hand
+// expiry: Throws the table away, set while a game is left with no one at the table
+type table struct {
+	name    string
+	seats   [Seats]seat
+	clients map[*client]bool
+	game    *tricks.Game
+	source  generic.Source
+	target  int
+	hand    int
+	passing bool
+	passes  []generic.Hand
+	points  []int
+	msg     string
+	over    bool
+	expiry  *time.Timer
+}
+
+// newTable returns an empty table.
+func newTable(name string, target int, source generic.Source) *table {
+	return &table{name: name, clients: make(map[*client]bool), target: target, source: source}
+}
+
+// taken tells if a seat is in use.
+func (t *table) taken(i int) bool {
+	return t.seats[i].token != "" || t.seats[i].computer
+}
+
+// playing tells if a game is under way.
+func (t *table) playing() bool {
+	return t.game != nil && !t.over
+}
+
+// info describes the table for the list of tables.
+func (t *table) info() TableInfo {
+	n := 0
+	for i := range t.seats {
+		if t.taken(i) && !t.seats[i].computer {
+			n++
+		}
+	}
+	return TableInfo{Name: t.name, Seated: n, Playing: t.playing()}
+}
+
+// sit gives seat i to a player, who can take it back later with token.
+// Seat -1 takes the first free seat.  The game starts once every seat is taken.
+func (t *table) sit(c *client, i int, name, token string) error {
+	if t.playing() {
+		return errors.New("the game has started")
+	}
+	if i < 0 {
+		for i = 0; i < Seats && t.taken(i); i++ {
+		}
+	}
+	if i >= Seats {
+		return errors.New("there are no free seats")
+	}
+	if t.taken(i) {
+		return fmt.Errorf("seat %d is taken", i)
+	}
+	if name == "" {
+		name = SeatNames[i]
+	}
+	t.seats[i] = seat{name: name, token: token, client: c}
+	c.seat = i
+	t.msg = name + " sat down."
+	for i := range t.seats {
+		if !t.taken(i) {
+			return nil
+		}
+	}
+	t.start()
+	return nil
+}
+
+// reclaim gives a seat back to the player holding its token.
+// Anyone else still connected to the seat is left watching.
+func (t *table) reclaim(c *client, token string) error {
+	for i := range t.seats {
+		s := &t.seats[i]
+		if s.token == "" || s.token != token {
+			continue
+		}
+		if s.client != nil && s.client != c {
+			s.client.seat = -1
+		}
+		s.client = c
+		c.seat = i
+		t.msg = s.name + " is back."
+		return nil
+	}
+	return errors.New("no seat has that token")
+}
+
+// stand takes a player out of their seat when they leave or lose their connection.
+// Once a game has started the seat is kept for them to come back to.
+func (t *table) stand(c *client) {
+	if c.seat < 0 {
+		return
+	}
+	s := &t.seats[c.seat]
+	if t.playing() {
+		s.client = nil
+		t.msg = s.name + " has gone away."
+	} else {
+		t.msg = s.name + " left."
+		*s = seat{}
+	}
+	c.seat = -1
+}
+
+// start begins a new game with the computer playing any empty seats.
+func (t *table) start() {
+	var names []string
+	var computer []bool
+	for i := range t.seats {
+		if !t.taken(i) {
+			t.seats[i] = seat{name: SeatNames[i], computer: true}
+		}
+		names = append(names, t.seats[i].name)
+		computer = append(computer, t.seats[i].computer)
+	}
+	t.game = tricks.NewGame(names, computer, engine.Rules{})
+	t.hand = 0
+	t.points = nil
+	t.over = false
+	t.newHand()
+	t.advance()
+}
+
+// newHand shuffles and deals a hand and starts passing if this hand passes.
+// The computer players choose their cards to pass straight away.
+func (t *table) newHand() {
+	deck := generic.NewDeck()
+	deck.Source = t.source
+	deck.Shuffle()
+	g := t.game
+	g.Deal(&deck)
+	for i := range g.Players {
+		g.Players[i].Hand.SortBySuit(generic.AceHigh)
+	}
+	dir := engine.PassDirection(t.hand)
+	if dir == tricks.PassNone {
+		t.startPlay()
+		return
+	}
+	t.passing = true
+	t.passes = make([]generic.Hand, Seats)
+	for i, p := range g.Players {
+		if p.Computer {
+			t.passes[i] = engine.ChoosePass(p.Hand)
+		}
+	}
+	t.msg = "Choose three cards to pass " + engine.PassNames[dir] + "."
+	t.pass()
+}
+
+// pass passes the cards once every seat has chosen them and starts play.
+func (t *table) pass() error {
+	for _, p := range t.passes {
+		if p == nil {
+			return nil
+		}
+	}
+	g := t.game
+	if err := g.Pass(t.passes, engine.PassDirection(t.hand)); err != nil {
+		return err
+	}
+	for i := range g.Players {
+		g.Players[i].Hand.SortBySuit(generic.AceHigh)
+	}
+	t.startPlay()
+	return nil
+}
+
+// startPlay gives the lead to the holder of the two of clubs.
+func (t *table) startPlay() {
+	t.passing = false
+	t.passes = nil
+	t.game.Turn = engine.FindLead(t.game)
+	t.msg = t.game.Players[t.game.Turn].Name + " leads."
+}
+
+// act carries out an action for a seat.
+func (t *table) act(i int, action string, cards []string) error {
+	if action == ActionStart {
+		if t.playing() {
+			return errors.New("the game has started")
+		}
+		t.start()
+		return nil
+	}
+	if !t.playing() {
+		return errors.New("the game has not started")
+	}
+	g := t.game
+	chosen, err := g.Players[i].Hand.Named(cards)
+	if err != nil {
+		return err
+	}
+	switch action {
+	case ActionPass:
+		if !t.passing {
+			return errors.New("it is not time to pass")
+		}
+		if t.passes[i] != nil {
+			return errors.New("you have already passed")
+		}
+		if len(chosen) != 3 {
+			return errors.New("choose exactly three cards to pass")
+		}
+		t.passes[i] = chosen
+		t.msg = g.Players[i].Name + " has chosen."
+		if err := t.pass(); err != nil {
+			return err
+		}
+	case ActionPlay:
+		if t.passing {
+			return errors.New("cards are being passed")
+		}
+		if len(chosen) != 1 {
+			return errors.New("play one card")
+		}
+		if _, err := g.Play(i, chosen[0]); err != nil {
+			return err
+		}
+		t.msg = ""
+	default:
+		return errors.New("no such action " + action)
+	}
+	t.advance()
+	return nil
+}
+
+// advance plays for the computer players until a person has to play, scoring each hand
+// as it finishes and dealing the next until someone reaches the target.
+func (t *table) advance() {
+	g := t.game
+	for !t.over && !t.passing {
+		if g.HandOver() {
+			t.endHand()
+			continue
+		}
+		if !g.Players[g.Turn].Computer {
+			return
+		}
+		g.Play(g.Turn, engine.ChoosePlay(g, g.Turn))
+	}
+}
+
+// endHand scores the hand and deals the next one unless the game is over.
+func (t *table) endHand() {
+	g := t.game
+	t.points = g.ScoreHand()
+	t.hand++
+	var parts []string
+	best := 0
+	for i, p := range g.Players {
+		parts = append(parts, fmt.Sprintf("%s %d", p.Name, t.points[i]))
+		if p.Score >= t.target {
+			t.over = true
+		}
+		if p.Score < g.Players[best].Score {
+			best = i
+		}
+	}
+	scores := "Hand scores: " + strings.Join(parts, ", ") + "."
+	if t.over {
+		t.msg = scores + " " + g.Players[best].Name + " won."
+		return
+	}
+	t.newHand()
+	t.msg = scores + " " + t.msg
+}
+
+// state returns the table as seen from seat i, or by someone watching if i is -1.
+func (t *table) state(i int) State {
+	st := State{Table: t.name, Seat: i, Started: t.game != nil, Hand: []string{}, Legal: []string{}, Trick: []string{},
+		LastTrick: []string{}, Points: t.points, Msg: t.msg, Over: t.over, Number: t.hand, Passing: t.passing}
+	st.Pass = engine.PassNames[engine.PassDirection(t.hand)]
+	for j, s := range t.seats {
+		p := PlayerState{Name: s.name, Computer: s.computer, Connected: s.client != nil}
+		if t.game != nil {
+			gp := t.game.Players[j]
+			p.Cards = len(gp.Hand)
+			p.Tricks = len(gp.Taken) / Seats
+			p.Score = gp.Score
+		}
+		st.Players = append(st.Players, p)
+	}
+	if t.game == nil {
+		return st
+	}
+	g := t.game
+	st.Trick = generic.Hand(g.Trick.Cards).Names()
+	st.Leader = g.Trick.Leader
+	st.LastTrick = generic.Hand(g.LastTrick.Cards).Names()
+	st.LastLeader = g.LastTrick.Leader
+	st.Turn = g.Turn
+	if i < 0 {
+		return st
+	}
+	st.Hand = g.Players[i].Hand.Names()
+	if t.passing {
+		st.Passed = t.passes[i] != nil
+	} else if !t.over && g.Turn == i {
+		st.Legal = g.LegalCards(i).Names()
+	}
+	return st
+}
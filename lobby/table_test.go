@@ -0,0 +1,70 @@
+package lobby
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestComputerTable(t *testing.T) {
+	tb := newTable("robots", 1, nil)
+	tb.start()
+	if !tb.over || tb.hand == 0 {
+		t.Fatalf("Expected the computers to play until someone had a point")
+	}
+	st := tb.state(-1)
+	if !st.Over || !strings.Contains(st.Msg, "won") || len(st.Hand) != 0 || len(st.Points) != Seats {
+		t.Errorf("Expected the game over with the winner named but was %+v", st)
+	}
+	total := 0
+	for _, p := range st.Players {
+		if !p.Computer {
+			t.Errorf("Expected every seat played by the computer")
+		}
+		total += p.Score
+	}
+	if total%26 != 0 {
+		t.Errorf("Expected the scores to total a multiple of 26 but was %d", total)
+	}
+}
+
+func TestAct(t *testing.T) {
+	tb := newTable("one", 100, nil)
+	c := &client{seat: -1}
+	if err := tb.sit(c, 2, "", "token"); err != nil || c.seat != 2 || tb.seats[2].name != "North" {
+		t.Fatalf("Expected to sit in the north seat but was %v", err)
+	}
+	if err := tb.sit(&client{seat: -1}, 2, "", "other"); err == nil {
+		t.Errorf("Expected a taken seat to be refused")
+	}
+	if err := tb.act(2, ActionPlay, nil); err == nil {
+		t.Errorf("Expected no play before the start")
+	}
+	if err := tb.act(2, ActionStart, nil); err != nil || !tb.passing {
+		t.Fatalf("Expected the game to start with passing but was %v", err)
+	}
+	st := tb.state(2)
+	if len(st.Hand) != 13 || st.Pass != "left" || st.Passed {
+		t.Fatalf("Expected a hand of 13 to pass left but was %+v", st)
+	}
+	for _, cards := range [][]string{st.Hand[:2], {st.Hand[0], st.Hand[0], st.Hand[1]}, {"XX", st.Hand[0], st.Hand[1]}} {
+		if err := tb.act(2, ActionPass, cards); err == nil {
+			t.Errorf("Expected passing %v to be refused", cards)
+		}
+	}
+	if err := tb.act(2, ActionPlay, st.Hand[:1]); err == nil {
+		t.Errorf("Expected no play while passing")
+	}
+	if err := tb.act(2, ActionPass, st.Hand[:3]); err != nil {
+		t.Fatalf("Expected the pass to be made but was %v", err)
+	}
+	if err := tb.act(2, ActionPass, st.Hand[3:6]); err == nil {
+		t.Errorf("Expected a second pass to be refused")
+	}
+	st = tb.state(2)
+	if tb.passing || len(st.Hand) != 13 || st.Turn != 2 || len(st.Legal) == 0 {
+		t.Fatalf("Expected the computers to have played up to north but was %+v", st)
+	}
+	if err := tb.act(2, ActionPlay, st.Legal[:1]); err != nil {
+		t.Errorf("Expected %s to be played but was %v", st.Legal[0], err)
+	}
+}
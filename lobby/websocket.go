@@ -0,0 +1,330 @@
+package lobby
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// transport carries messages over a connection, as JSON lines or WebSocket frames.
+type transport interface {
+	read() (Message, error)
+	write(m Message) error
+}
+
+// lines is the JSON lines transport used over plain TCP.
+type lines struct {
+	enc *json.Encoder
+	in  *bufio.Scanner
+}
+
+// newLines returns the JSON lines transport for a connection.
+// Lines longer than maxFrame are refused.
+func newLines(conn net.Conn) *lines {
+	in := bufio.NewScanner(conn)
+	in.Buffer(make([]byte, 0, 4096), maxFrame)
+	return &lines{enc: json.NewEncoder(conn), in: in}
+}
+
+// read reads the next line.
+func (l *lines) read() (Message, error) {
+	if !l.in.Scan() {
+		if err := l.in.Err(); err != nil {
+			return Message{}, err
+		}
+		return Message{}, io.EOF
+	}
+	var m Message
+	err := json.Unmarshal(l.in.Bytes(), &m)
+	return m, err
+}
+
+// write writes a message as a line.
+func (l *lines) write(m Message) error {
+	return l.enc.Encode(m)
+}
+
+// The WebSocket opcodes used.
+const (
+	opContinue = 0
+	opText     = 1
+	opBinary   = 2
+	opClose    = 8
+	opPing     = 9
+	opPong     = 10
+)
+
+// maxFrame is the largest message accepted, as a line or over a WebSocket.
+const maxFrame = 1 << 20
+
+// wsGUID is added to the key to make the accept header of the WebSocket handshake.
+const wsGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// ErrHandshake is returned when a WebSocket connection can not be set up.
+var ErrHandshake = errors.New("bad websocket handshake")
+
+// ErrOrigin is returned when a WebSocket request comes from a page on another host.
+var ErrOrigin = errors.New("websocket origin is not allowed")
+
+// websocket is the transport for a WebSocket connection, one message to each text frame.
+// mask is true for the client side, which must mask the frames it sends and only accepts
+// unmasked frames; the server side only accepts masked frames.
+type websocket struct {
+	conn net.Conn
+	r    *bufio.Reader
+	mask bool
+	mu   sync.Mutex
+}
+
+// accept returns the Sec-WebSocket-Accept value for a Sec-WebSocket-Key.
+func accept(key string) string {
+	sum := sha1.Sum([]byte(key + wsGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// headerHas tells if a comma separated header has a value, ignoring case.
+func headerHas(h http.Header, name, value string) bool {
+	for _, v := range h[http.CanonicalHeaderKey(name)] {
+		for _, s := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(s), value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sameOrigin tells if a request has no Origin, as from a program, or one on the host it was sent to.
+// A page on another site can not then play in the name of whoever has it open.
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(u.Host, r.Host)
+}
+
+// upgrade takes over an HTTP request that asks for a WebSocket and completes the handshake.
+func upgrade(w http.ResponseWriter, r *http.Request) (net.Conn, *websocket, error) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if r.Method != http.MethodGet || !headerHas(r.Header, "Connection", "upgrade") ||
+		!headerHas(r.Header, "Upgrade", "websocket") || r.Header.Get("Sec-WebSocket-Version") != "13" || key == "" {
+		http.Error(w, ErrHandshake.Error(), http.StatusBadRequest)
+		return nil, nil, ErrHandshake
+	}
+	if !sameOrigin(r) {
+		http.Error(w, ErrOrigin.Error(), http.StatusForbidden)
+		return nil, nil, ErrOrigin
+	}
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "websockets are not supported", http.StatusInternalServerError)
+		return nil, nil, ErrHandshake
+	}
+	conn, rw, err := hj.Hijack()
+	if err != nil {
+		return nil, nil, err
+	}
+	fmt.Fprintf(rw, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n\r\n", accept(key))
+	if err := rw.Flush(); err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	return conn, &websocket{conn: conn, r: rw.Reader}, nil
+}
+
+// dialWebSocket connects to a WebSocket at a ws:// URL and completes the handshake.
+func dialWebSocket(rawurl string) (net.Conn, *websocket, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, nil, err
+	}
+	if u.Scheme != "ws" {
+		return nil, nil, fmt.Errorf("%s is not a ws:// address", rawurl)
+	}
+	host := u.Host
+	if u.Port() == "" {
+		host += ":80"
+	}
+	conn, err := net.Dial("tcp", host)
+	if err != nil {
+		return nil, nil, err
+	}
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	key := base64.StdEncoding.EncodeToString(b)
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	if err := req.Write(conn); err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	r := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(r, req)
+	if err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusSwitchingProtocols || resp.Header.Get("Sec-WebSocket-Accept") != accept(key) {
+		conn.Close()
+		return nil, nil, ErrHandshake
+	}
+	return conn, &websocket{conn: conn, r: r, mask: true}, nil
+}
+
+// readFrame reads one frame and returns whether it is the last of its message, its opcode and
+// its payload, unmasked.
+func (ws *websocket) readFrame() (bool, byte, []byte, error) {
+	var head [2]byte
+	if _, err := io.ReadFull(ws.r, head[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin, op := head[0]&0x80 != 0, head[0]&0x0f
+	masked := head[1]&0x80 != 0
+	if masked == ws.mask {
+		if masked {
+			return false, 0, nil, errors.New("websocket frame from the server is masked")
+		}
+		return false, 0, nil, errors.New("websocket frame from the client is not masked")
+	}
+	n := uint64(head[1] & 0x7f)
+	switch n {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(ws.r, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		n = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(ws.r, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		n = binary.BigEndian.Uint64(ext[:])
+	}
+	if n > maxFrame {
+		return false, 0, nil, fmt.Errorf("websocket frame of %d bytes is too big", n)
+	}
+	var key [4]byte
+	if masked {
+		if _, err := io.ReadFull(ws.r, key[:]); err != nil {
+			return false, 0, nil, err
+		}
+	}
+	payload := make([]byte, n)
+	if _, err := io.ReadFull(ws.r, payload); err != nil {
+		return false, 0, nil, err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= key[i%4]
+		}
+	}
+	return fin, op, payload, nil
+}
+
+// writeFrame writes a whole message as one frame, masked on the client side.
+func (ws *websocket) writeFrame(op byte, payload []byte) error {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	b := []byte{0x80 | op}
+	var bit byte
+	if ws.mask {
+		bit = 0x80
+	}
+	switch n := len(payload); {
+	case n < 126:
+		b = append(b, bit|byte(n))
+	case n <= 0xffff:
+		b = append(b, bit|126, byte(n>>8), byte(n))
+	default:
+		var ext [8]byte
+		binary.BigEndian.PutUint64(ext[:], uint64(n))
+		b = append(append(b, bit|127), ext[:]...)
+	}
+	if ws.mask {
+		var key [4]byte
+		if _, err := rand.Read(key[:]); err != nil {
+			return err
+		}
+		b = append(b, key[:]...)
+		start := len(b)
+		b = append(b, payload...)
+		for i := start; i < len(b); i++ {
+			b[i] ^= key[(i-start)%4]
+		}
+	} else {
+		b = append(b, payload...)
+	}
+	_, err := ws.conn.Write(b)
+	return err
+}
+
+// read returns the next message, answering pings and closes on the way.
+// A close from the other side is returned as io.EOF.
+func (ws *websocket) read() (Message, error) {
+	var data []byte
+	for {
+		fin, op, payload, err := ws.readFrame()
+		if err != nil {
+			return Message{}, err
+		}
+		switch op {
+		case opPing:
+			if err := ws.writeFrame(opPong, payload); err != nil {
+				return Message{}, err
+			}
+			continue
+		case opPong:
+			continue
+		case opClose:
+			ws.writeFrame(opClose, nil)
+			return Message{}, io.EOF
+		case opText, opBinary, opContinue:
+			if len(data)+len(payload) > maxFrame {
+				return Message{}, errors.New("websocket message is too big")
+			}
+			data = append(data, payload...)
+		default:
+			return Message{}, fmt.Errorf("unknown websocket opcode %d", op)
+		}
+		if fin {
+			break
+		}
+	}
+	var m Message
+	err := json.Unmarshal(data, &m)
+	return m, err
+}
+
+// write sends a message as a text frame.
+func (ws *websocket) write(m Message) error {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return ws.writeFrame(opText, b)
+}
@@ -0,0 +1,99 @@
+package lobby
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWebSocket(t *testing.T) {
+	sv := NewServer(100)
+	hs := httptest.NewServer(sv)
+	defer hs.Close()
+	if resp, err := http.Get(hs.URL); err != nil || resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected a plain request to be refused but was %v %v", resp, err)
+	}
+	if _, err := DialWebSocket("http://" + hs.Listener.Addr().String()); err == nil {
+		t.Errorf("Expected an error for an address that is not ws://")
+	}
+	a, err := DialWebSocket("ws://" + hs.Listener.Addr().String() + "/ws")
+	if err != nil {
+		t.Fatalf("Expected to connect but was %v", err)
+	}
+	defer a.Close()
+	l := listen(t, sv)
+	defer l.stop()
+	b := dial(t, l.addr)
+	defer b.Close()
+	expect(t, a, Message{Type: TypeSeat, Table: "web", Seat: 0, Name: "Ann"}, TypeSeated)
+	expect(t, b, Message{Type: TypeJoin, Table: "web"}, TypeState)
+	// a ping is answered and a long message needs the longer length
+	if err := a.t.(*websocket).writeFrame(opPing, []byte("hi")); err != nil {
+		t.Fatalf("Expected to send a ping but was %v", err)
+	}
+	text := strings.Repeat("hello ", 50)
+	if m := expect(t, a, Message{Type: TypeChat, Text: text}, TypeChat); m.Text != text {
+		t.Errorf("Expected the long chat back but was %q", m.Text)
+	}
+	if m := expect(t, b, Message{}, TypeChat); m.Name != "Ann" || m.Text != text {
+		t.Errorf("Expected the TCP client to hear Ann but was %+v", m)
+	}
+}
+
+func TestWebSocketOrigin(t *testing.T) {
+	hs := httptest.NewServer(NewServer(100))
+	defer hs.Close()
+	req, err := http.NewRequest(http.MethodGet, hs.URL+"/ws", nil)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Origin", "http://elsewhere.example")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil || resp.StatusCode != http.StatusForbidden {
+		t.Errorf("Expected a page from another host to be refused but was %v %v", resp, err)
+	}
+	if err == nil {
+		resp.Body.Close()
+	}
+	req = httptest.NewRequest(http.MethodGet, "http://cards.example:8082/ws", nil)
+	if !sameOrigin(req) {
+		t.Errorf("Expected a request without an Origin to be allowed")
+	}
+	req.Header.Set("Origin", "http://cards.example:8082")
+	if !sameOrigin(req) {
+		t.Errorf("Expected a page from the same host to be allowed")
+	}
+}
+
+func TestWebSocketMask(t *testing.T) {
+	for _, mask := range []bool{false, true} {
+		a, b := net.Pipe()
+		ws := &websocket{conn: a, r: bufio.NewReader(a), mask: mask}
+		peer := &websocket{conn: b, r: bufio.NewReader(b), mask: mask}
+		go peer.write(Message{Type: TypeChat, Text: "hi"})
+		if _, err := ws.read(); err == nil {
+			t.Errorf("Expected a frame masked %v to be refused when mask is %v", mask, mask)
+		}
+		a.Close()
+		b.Close()
+	}
+}
+
+func TestLinesTooLong(t *testing.T) {
+	a, b := net.Pipe()
+	defer a.Close()
+	go func() {
+		b.Write([]byte(`{"type":"chat","text":"` + strings.Repeat("x", maxFrame) + `"}` + "\n"))
+		b.Close()
+	}()
+	if _, err := newLines(a).read(); err != bufio.ErrTooLong {
+		t.Errorf("Expected a line too long but was %v", err)
+	}
+}
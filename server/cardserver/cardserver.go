@@ -0,0 +1,34 @@
+// This program serves games of klondike over HTTP with JSON so they can be played from a web page
+// or by a bot.  See the server package for the requests it answers.
+//
+// Open the address it serves on, http://localhost:8080/ by default, in a browser to play klondike
+// with the mouse.  The page is built into the program so nothing else is needed.
+//
+// Games are kept in memory unless -data gives a directory to save them in, in which case they are
+// read back when the server starts again.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/tmasterson/cardgames/server"
+)
+
+func main() {
+	addrptr := flag.String("addr", "localhost:8080", "The address to listen on")
+	dataptr := flag.String("data", "", "Directory to save the games in, by default they are only kept in memory")
+	flag.Parse()
+	store, err := server.NewStore(*dataptr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Serving games on %s\n", *addrptr)
+	if err := http.ListenAndServe(*addrptr, server.New(store)); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+}
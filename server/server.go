@@ -0,0 +1,222 @@
+// Package server lets games of klondike be played over HTTP with JSON so web pages and bots can be
+// written as clients of the engine.  The requests are
+//
+//	POST   /games             Deal a new game, the body gives its options
+//	GET    /games             List the ids of the games
+//	GET    /games/{id}        Get the state of a game
+//	DELETE /games/{id}        Throw a game away
+//	GET    /games/{id}/moves  List the legal moves
+//	POST   /games/{id}/moves  Make a move, the body is a move such as {"from": 7, "to": 2}
+//
+// Moves use the stack numbers of the engine package.  Errors are returned as {"error": "..."}.
+//
+// GET / is a web page for playing klondike in a browser with the mouse.
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/tmasterson/cardgames/generic"
+	"github.com/tmasterson/cardgames/solitaire/klondike/engine"
+)
+
+// NewGame is the body of a request for a new game.
+// Draw: The number of cards dealt at a time, 3 if it is not given
+// Passes: The number of passes through the deck, 0 for no limit, the same as draw if it is not given
+// Build: alternate, suit or color, alternate if it is not given
+// Seed: A seed shown after a fair game to deal the same game again
+type NewGame struct {
+	Draw   int    `json:"draw"`
+	Passes *int   `json:"passes"`
+	Build  string `json:"build"`
+	Seed   string `json:"seed"`
+}
+
+// State is a game as sent to a client.  Face down cards are sent as ?? so they can not be seen.
+// Stacks: The cards in each stack from the bottom up, numbered as in the engine package
+// Stock: The number of cards left to deal
+type State struct {
+	ID     string     `json:"id"`
+	Draw   int        `json:"draw"`
+	Passes int        `json:"passes"`
+	Build  string     `json:"build"`
+	Stacks [][]string `json:"stacks"`
+	Stock  int        `json:"stock"`
+	Pass   int        `json:"pass"`
+	Moves  int        `json:"moves"`
+	Won    bool       `json:"won"`
+	Over   bool       `json:"over"`
+}
+
+// The names of the builds in requests.
+var builds = map[string]rune{"alternate": 'T', "suit": 'S', "color": 'M'}
+
+// Server answers the requests for the games in a store.
+type Server struct {
+	store *Store
+}
+
+// New returns a server for the games in a store.
+func New(store *Store) *Server {
+	return &Server{store: store}
+}
+
+// NewState returns the state of a game to send to a client, with the face down cards hidden.
+func NewState(id string, g *engine.Game) State {
+	v := g.View()
+	st := State{ID: id, Draw: v.Draw, Passes: v.Passes, Stock: len(v.Stock), Pass: v.Pass, Moves: v.Moves, Won: g.IsWon(), Over: g.Over()}
+	for name, b := range builds {
+		if b == v.Build {
+			st.Build = name
+		}
+	}
+	st.Stacks = make([][]string, len(v.Stacks))
+	for i, p := range v.Stacks {
+		st.Stacks[i] = []string{}
+		for _, c := range p.Cards {
+			str := "??"
+			if c.Faceup {
+				str = c.Rank + c.Suit
+			}
+			st.Stacks[i] = append(st.Stacks[i], str)
+		}
+	}
+	return st
+}
+
+// writeJSON sends a value as JSON with a status code.
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError sends an error as JSON with a status code.
+func writeError(w http.ResponseWriter, code int, err error) {
+	writeJSON(w, code, map[string]string{"error": err.Error()})
+}
+
+// ServeHTTP answers a request.
+func (sv *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if a, ok := assets[r.URL.Path]; ok {
+		serveAsset(w, r, a)
+		return
+	}
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if parts[0] != "games" || len(parts) > 3 || (len(parts) == 3 && parts[2] != "moves") {
+		writeError(w, http.StatusNotFound, errors.New("no such page"))
+		return
+	}
+	switch {
+	case len(parts) == 1 && r.Method == http.MethodPost:
+		sv.create(w, r)
+	case len(parts) == 1 && r.Method == http.MethodGet:
+		ids := sv.store.IDs()
+		sort.Strings(ids)
+		writeJSON(w, http.StatusOK, ids)
+	case len(parts) == 1:
+		writeError(w, http.StatusMethodNotAllowed, errors.New("use GET or POST"))
+	case !validID(parts[1]):
+		writeError(w, http.StatusNotFound, ErrNoGame)
+	case len(parts) == 2 && r.Method == http.MethodGet:
+		sv.do(w, parts[1], func(g *engine.Game) (interface{}, error) {
+			return NewState(parts[1], g), nil
+		})
+	case len(parts) == 2 && r.Method == http.MethodDelete:
+		if err := sv.store.Delete(parts[1]); err != nil {
+			writeError(w, http.StatusNotFound, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case len(parts) == 2:
+		writeError(w, http.StatusMethodNotAllowed, errors.New("use GET or DELETE"))
+	case r.Method == http.MethodGet:
+		sv.do(w, parts[1], func(g *engine.Game) (interface{}, error) {
+			moves := g.LegalMoves()
+			if moves == nil {
+				moves = []engine.Move{}
+			}
+			return moves, nil
+		})
+	case r.Method == http.MethodPost:
+		var m engine.Move
+		if err := json.NewDecoder(r.Body).Decode(&m); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		sv.do(w, parts[1], func(g *engine.Game) (interface{}, error) {
+			if err := g.Apply(m); err != nil {
+				return nil, err
+			}
+			return NewState(parts[1], g), nil
+		})
+	default:
+		writeError(w, http.StatusMethodNotAllowed, errors.New("use GET or POST"))
+	}
+}
+
+// do runs f on a game and sends what it returns, or the error if there is one.
+// An illegal move is sent as 422 Unprocessable Entity.
+func (sv *Server) do(w http.ResponseWriter, id string, f func(g *engine.Game) (interface{}, error)) {
+	var v interface{}
+	err := sv.store.Do(id, func(g *engine.Game) error {
+		var err error
+		v, err = f(g)
+		return err
+	})
+	switch err {
+	case nil:
+		writeJSON(w, http.StatusOK, v)
+	case ErrNoGame:
+		writeError(w, http.StatusNotFound, err)
+	case engine.ErrIllegal, engine.ErrNoPasses:
+		writeError(w, http.StatusUnprocessableEntity, err)
+	default:
+		writeError(w, http.StatusInternalServerError, err)
+	}
+}
+
+// create deals a new game from the options in a request.
+func (sv *Server) create(w http.ResponseWriter, r *http.Request) {
+	req := NewGame{Draw: 3, Build: "alternate"}
+	// an empty body takes all the defaults
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	o := engine.Options{Draw: req.Draw, Passes: req.Draw}
+	if req.Passes != nil {
+		o.Passes = *req.Passes
+	}
+	var ok bool
+	if o.Build, ok = builds[req.Build]; !ok {
+		writeError(w, http.StatusBadRequest, errors.New("build must be alternate, suit or color"))
+		return
+	}
+	if o.Draw < 1 || o.Draw > 3 || o.Passes < 0 {
+		writeError(w, http.StatusBadRequest, errors.New("draw must be 1, 2 or 3 and passes 0 or more"))
+		return
+	}
+	if req.Seed != "" {
+		commit, err := generic.ParseSeed(req.Seed)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		o.Source = commit.Source()
+	}
+	g := engine.NewGame(o)
+	st := NewState("", g)
+	id, err := sv.store.Add(g)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	st.ID = id
+	writeJSON(w, http.StatusCreated, st)
+}
@@ -0,0 +1,162 @@
+package server
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/tmasterson/cardgames/generic"
+	"github.com/tmasterson/cardgames/solitaire/klondike/engine"
+)
+
+// do sends a request to a server and decodes the JSON answer into v if it is not nil.
+func do(t *testing.T, h http.Handler, method, path, body string, v interface{}) int {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if v != nil {
+		if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+			t.Fatalf("%s %s: expected JSON but was %q", method, path, rec.Body.String())
+		}
+	}
+	return rec.Code
+}
+
+func TestCreate(t *testing.T) {
+	store, _ := NewStore("")
+	h := New(store)
+	var st State
+	if code := do(t, h, "POST", "/games", "", &st); code != http.StatusCreated {
+		t.Fatalf("Expected 201 but was %d", code)
+	}
+	if !validID(st.ID) || st.Draw != 3 || st.Passes != 3 || st.Build != "alternate" || st.Stock != 21 {
+		t.Errorf("Expected a default game but was %+v", st)
+	}
+	if len(st.Stacks) != engine.StackCount || len(st.Stacks[6]) != 7 || st.Stacks[6][0] != "??" || st.Stacks[6][6] == "??" {
+		t.Errorf("Expected the last stack to show only its top card but was %v", st.Stacks[6])
+	}
+	if code := do(t, h, "POST", "/games", `{"draw": 1, "passes": 0, "build": "suit"}`, &st); code != http.StatusCreated || st.Passes != 0 || st.Build != "suit" || st.Stock != 23 {
+		t.Errorf("Expected draw one with no pass limit built by suit but was %d %+v", code, st)
+	}
+	var ids []string
+	if do(t, h, "GET", "/games", "", &ids); len(ids) != 2 {
+		t.Errorf("Expected 2 games but was %v", ids)
+	}
+	for _, body := range []string{`{"draw": 4}`, `{"build": "up"}`, `{"passes": -1}`, `{"seed": "xyz"}`, `{`} {
+		var e map[string]string
+		if code := do(t, h, "POST", "/games", body, &e); code != http.StatusBadRequest || e["error"] == "" {
+			t.Errorf("Expected %s to be a bad request but was %d", body, code)
+		}
+	}
+}
+
+func TestSeed(t *testing.T) {
+	store, _ := NewStore("")
+	h := New(store)
+	commit, _ := generic.NewCommitment()
+	body := `{"seed": "` + commit.SeedString() + `"}`
+	var a, b State
+	do(t, h, "POST", "/games", body, &a)
+	do(t, h, "POST", "/games", body, &b)
+	if a.ID == b.ID || strings.Join(a.Stacks[6], " ") != strings.Join(b.Stacks[6], " ") {
+		t.Errorf("Expected the same deal for the same seed but was %v and %v", a.Stacks[6], b.Stacks[6])
+	}
+}
+
+func TestMoves(t *testing.T) {
+	store, _ := NewStore("")
+	h := New(store)
+	var st State
+	do(t, h, "POST", "/games", `{"passes": 0}`, &st)
+	path := "/games/" + st.ID
+	var moves []engine.Move
+	if code := do(t, h, "GET", path+"/moves", "", &moves); code != http.StatusOK || len(moves) == 0 || !moves[len(moves)-1].Deal {
+		t.Fatalf("Expected legal moves ending with the deal but was %d %v", code, moves)
+	}
+	if code := do(t, h, "POST", path+"/moves", `{"deal": true}`, &st); code != http.StatusOK || st.Stock != 18 || st.Moves != 1 {
+		t.Errorf("Expected a deal leaving 18 in the stock but was %d %+v", code, st)
+	}
+	var e map[string]string
+	if code := do(t, h, "POST", path+"/moves", `{"from": 0, "to": 0}`, &e); code != http.StatusUnprocessableEntity || e["error"] != engine.ErrIllegal.Error() {
+		t.Errorf("Expected an illegal move to be refused but was %d %v", code, e)
+	}
+	if code := do(t, h, "POST", path+"/moves", `nonsense`, &e); code != http.StatusBadRequest {
+		t.Errorf("Expected a bad move to be a bad request but was %d", code)
+	}
+	if code := do(t, h, "GET", path, "", &st); code != http.StatusOK || st.Moves != 1 {
+		t.Errorf("Expected the state with one move but was %d %+v", code, st)
+	}
+	if code := do(t, h, "DELETE", path, "", nil); code != http.StatusNoContent {
+		t.Errorf("Expected the game deleted but was %d", code)
+	}
+	if code := do(t, h, "GET", path, "", &e); code != http.StatusNotFound {
+		t.Errorf("Expected a deleted game not to be found but was %d", code)
+	}
+	for _, p := range []string{"/other", "/games/nothing", "/games/" + st.ID + "/other", "/games/../../etc"} {
+		if code := do(t, h, "GET", p, "", &e); code != http.StatusNotFound {
+			t.Errorf("Expected %s not to be found but was %d", p, code)
+		}
+	}
+	if code := do(t, h, "PUT", "/games", "", &e); code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected PUT not to be allowed but was %d", code)
+	}
+}
+
+func TestStoreFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cardserver")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	store, err := NewStore(dir)
+	if err != nil {
+		t.Fatalf("Expected a store but was %v", err)
+	}
+	h := New(store)
+	var st State
+	do(t, h, "POST", "/games", "", &st)
+	do(t, h, "POST", "/games/"+st.ID+"/moves", `{"deal": true}`, &st)
+	ioutil.WriteFile(dir+"/notes.json", []byte("not a game"), 0644)
+	store, err = NewStore(dir)
+	if err != nil {
+		t.Fatalf("Expected the store to be read again but was %v", err)
+	}
+	var again State
+	if code := do(t, New(store), "GET", "/games/"+st.ID, "", &again); code != http.StatusOK {
+		t.Fatalf("Expected the saved game but was %d", code)
+	}
+	if strings.Join(again.Stacks[engine.Waste], " ") != strings.Join(st.Stacks[engine.Waste], " ") || again.Moves != 1 {
+		t.Errorf("Expected the game as it was saved but was %+v", again)
+	}
+	if err := store.Delete(st.ID); err != nil {
+		t.Errorf("Expected the game deleted but was %v", err)
+	}
+	if _, err := os.Stat(dir + "/" + st.ID + ".json"); !os.IsNotExist(err) {
+		t.Errorf("Expected the game's file removed")
+	}
+}
+
+func TestWeb(t *testing.T) {
+	store, _ := NewStore("")
+	h := New(store)
+	for path, typ := range map[string]string{"/": "text/html", "/app.js": "application/javascript", "/style.css": "text/css"} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get("Content-Type"), typ) || rec.Body.Len() == 0 {
+			t.Errorf("Expected %s to be served as %s but was %d %s", path, typ, rec.Code, rec.Header().Get("Content-Type"))
+		}
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	if !strings.Contains(rec.Body.String(), `<script src="/app.js">`) {
+		t.Errorf("Expected the page to load app.js")
+	}
+	var e map[string]string
+	if code := do(t, h, "POST", "/", "", &e); code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected POST / not to be allowed but was %d", code)
+	}
+}
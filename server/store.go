@@ -0,0 +1,142 @@
+package server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/tmasterson/cardgames/solitaire/klondike/engine"
+)
+
+// ErrNoGame is returned for a game id that is not in the store.
+var ErrNoGame = errors.New("no such game")
+
+// Store holds the games being played.  They are kept in memory and, if a directory is given,
+// written to a file each named for the game's id whenever they change so they outlast the server.
+// A Store is safe to use from more than one goroutine.
+type Store struct {
+	mu    sync.Mutex
+	games map[string]*engine.Game
+	dir   string
+}
+
+// NewStore returns a store keeping its games in dir, reading any games already there.
+// Files in dir not named for a game id are left alone.
+// With no dir the games are only kept in memory.
+func NewStore(dir string) (*Store, error) {
+	st := &Store{games: make(map[string]*engine.Game), dir: dir}
+	if dir == "" {
+		return st, nil
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		id := strings.TrimSuffix(filepath.Base(file), ".json")
+		if !validID(id) {
+			continue
+		}
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		var v engine.View
+		if err := json.Unmarshal(data, &v); err != nil {
+			return nil, errors.New(file + ": " + err.Error())
+		}
+		st.games[id] = engine.Restore(v)
+	}
+	return st, nil
+}
+
+// newID returns a random id for a game.
+func newID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// ValidID tells if an id could have been made by the store, so it is safe to use as a file name.
+func validID(id string) bool {
+	if len(id) != 16 {
+		return false
+	}
+	_, err := hex.DecodeString(id)
+	return err == nil
+}
+
+// Add puts a new game in the store and returns its id.
+func (st *Store) Add(g *engine.Game) (string, error) {
+	id, err := newID()
+	if err != nil {
+		return "", err
+	}
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.games[id] = g
+	return id, st.save(id, g)
+}
+
+// Do runs f on a game with the store locked and saves the game afterwards if f returns no error.
+// Anything done with the game must be done inside f.
+func (st *Store) Do(id string, f func(g *engine.Game) error) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	g, ok := st.games[id]
+	if !ok {
+		return ErrNoGame
+	}
+	if err := f(g); err != nil {
+		return err
+	}
+	return st.save(id, g)
+}
+
+// IDs returns the ids of the games in the store.
+func (st *Store) IDs() []string {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	ids := make([]string, 0, len(st.games))
+	for id := range st.games {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// Delete takes a game out of the store.
+func (st *Store) Delete(id string) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if _, ok := st.games[id]; !ok {
+		return ErrNoGame
+	}
+	delete(st.games, id)
+	if st.dir == "" {
+		return nil
+	}
+	return os.Remove(filepath.Join(st.dir, id+".json"))
+}
+
+// save writes a game to its file if the store has a directory.
+func (st *Store) save(id string, g *engine.Game) error {
+	if st.dir == "" {
+		return nil
+	}
+	data, err := json.Marshal(g.View())
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(st.dir, id+".json"), data, 0644)
+}
@@ -0,0 +1,339 @@
+package server
+
+import (
+	"errors"
+	"io"
+	"net/http"
+)
+
+// asset is a file of the web page.
+type asset struct {
+	contentType string
+	body        string
+}
+
+// The web page for playing klondike in a browser.  It is kept in the program, rather than in
+// files beside it, so the server needs nothing else to run and works with no internet connection.
+// The page plays through the same requests as any other client.
+var assets = map[string]asset{
+	"/":          {"text/html; charset=utf-8", indexHTML},
+	"/style.css": {"text/css; charset=utf-8", styleCSS},
+	"/app.js":    {"application/javascript; charset=utf-8", appJS},
+}
+
+// serveAsset sends a file of the web page.
+func serveAsset(w http.ResponseWriter, r *http.Request, a asset) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		writeError(w, http.StatusMethodNotAllowed, errors.New("use GET"))
+		return
+	}
+	w.Header().Set("Content-Type", a.contentType)
+	w.Header().Set("Cache-Control", "no-cache")
+	io.WriteString(w, a.body)
+}
+
+const indexHTML = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Klondike</title>
+<link rel="stylesheet" href="/style.css">
+</head>
+<body>
+<header>
+<h1>Klondike</h1>
+<label>Draw <select id="draw"><option>1</option><option>2</option><option selected>3</option></select></label>
+<label>Build <select id="build"><option value="alternate">alternate colors</option><option value="suit">by suit</option><option value="color">by color</option></select></label>
+<button id="new">New game</button>
+</header>
+<div id="board"></div>
+<p id="status"></p>
+<p class="help">Drag cards to move them, or click a card and then where it goes.
+Double click a card to put it on the aces.  Click the stock to deal.</p>
+<script src="/app.js"></script>
+</body>
+</html>
+`
+
+const styleCSS = `body {
+	background: #0b5d1e;
+	color: #fff;
+	font-family: sans-serif;
+	margin: 1em;
+}
+header {
+	display: flex;
+	align-items: center;
+	gap: 1em;
+}
+h1 {
+	font-size: 1.5em;
+	margin: 0 1em 0 0;
+}
+.row {
+	display: grid;
+	grid-template-columns: repeat(7, 80px);
+	gap: 12px;
+	margin: 1em 0;
+}
+.pile {
+	position: relative;
+	width: 70px;
+	height: 96px;
+	border: 1px solid #3c8c4f;
+	border-radius: 6px;
+	color: #3c8c4f;
+	font-size: 2em;
+	text-align: center;
+	line-height: 96px;
+}
+.pile.fanned {
+	height: 400px;
+}
+.card {
+	position: absolute;
+	top: 0;
+	left: 0;
+	box-sizing: border-box;
+	width: 70px;
+	height: 96px;
+	padding: 4px;
+	border: 1px solid #333;
+	border-radius: 6px;
+	background: #fff;
+	color: #000;
+	font-size: 18px;
+	line-height: 1;
+	text-align: left;
+	cursor: pointer;
+	user-select: none;
+}
+.card.red {
+	color: #c00;
+}
+.card.back {
+	background: repeating-linear-gradient(45deg, #236, #236 4px, #347 4px, #347 8px);
+	cursor: default;
+}
+.card.selected {
+	background: #ffe680;
+}
+.help {
+	color: #cde;
+	font-size: 0.9em;
+}
+`
+
+const appJS = `// Klondike in a browser, played through the JSON requests of the card server.
+(function () {
+	"use strict";
+
+	var suits = {S: "♠", H: "♥", D: "♦", C: "♣"};
+	var aceStack = {S: 8, H: 9, D: 10, C: 11};
+	var WASTE = 7, FIRSTACE = 8, COLUMNS = 7;
+
+	var game = null;     // the state of the game from the server
+	var selected = null; // the cards picked by clicking, {from, count}
+	var board = document.getElementById("board");
+	var status = document.getElementById("status");
+
+	// send makes a request to the server and passes the answer to done.
+	function send(method, path, body, done) {
+		var opts = {method: method, headers: {"Content-Type": "application/json"}};
+		if (body) {
+			opts.body = JSON.stringify(body);
+		}
+		fetch(path, opts).then(function (r) {
+			return r.json().then(function (data) {
+				done(r.status, data);
+			});
+		}).catch(function () {
+			show("The server can not be reached.");
+		});
+	}
+
+	function show(msg) {
+		status.textContent = msg;
+	}
+
+	// setGame keeps a new state and draws it.
+	function setGame(st) {
+		game = st;
+		selected = null;
+		localStorage.setItem("klondike", st.id);
+		draw();
+		if (st.won) {
+			show("You won in " + st.moves + " moves!");
+		} else if (st.over) {
+			show("No passes left.  Game over after " + st.moves + " moves.");
+		} else {
+			show("Moves " + st.moves + ", pass " + (st.pass + 1) + (st.passes ? " of " + st.passes : "") + ", " + st.stock + " left to deal.");
+		}
+	}
+
+	function newGame() {
+		var body = {draw: Number(document.getElementById("draw").value), build: document.getElementById("build").value};
+		send("POST", "/games", body, function (code, data) {
+			if (code !== 201) {
+				show(data.error);
+				return;
+			}
+			setGame(data);
+		});
+	}
+
+	// move asks the server to move cards and shows why not if it can not be done.
+	function move(from, to, count) {
+		selected = null;
+		send("POST", "/games/" + game.id + "/moves", {from: from, to: to, count: count}, function (code, data) {
+			if (code !== 200) {
+				draw();
+				show("You can not move that there.");
+				return;
+			}
+			setGame(data);
+		});
+	}
+
+	function deal() {
+		send("POST", "/games/" + game.id + "/moves", {deal: true}, function (code, data) {
+			if (code !== 200) {
+				show(data.error);
+				return;
+			}
+			setGame(data);
+		});
+	}
+
+	// count returns how many cards move when a card is picked up.
+	function count(stack, index) {
+		if (stack === WASTE || stack >= FIRSTACE) {
+			return 1;
+		}
+		return game.stacks[stack].length - index;
+	}
+
+	function cardElement(name, stack, index) {
+		var el = document.createElement("div");
+		el.className = "card";
+		if (name === "??") {
+			el.className += " back";
+			return el;
+		}
+		var suit = name.charAt(1);
+		el.textContent = (name.charAt(0) === "T" ? "10" : name.charAt(0)) + suits[suit];
+		if (suit === "H" || suit === "D") {
+			el.className += " red";
+		}
+		if (selected && selected.from === stack && index >= game.stacks[stack].length - selected.count) {
+			el.className += " selected";
+		}
+		el.draggable = true;
+		el.addEventListener("dragstart", function (e) {
+			e.dataTransfer.setData("text/plain", JSON.stringify({from: stack, count: count(stack, index)}));
+		});
+		el.addEventListener("click", function (e) {
+			e.stopPropagation();
+			if (selected && selected.from !== stack) {
+				move(selected.from, stack, selected.count);
+				return;
+			}
+			selected = selected ? null : {from: stack, count: count(stack, index)};
+			draw();
+		});
+		el.addEventListener("dblclick", function (e) {
+			e.stopPropagation();
+			if (index === game.stacks[stack].length - 1) {
+				move(stack, aceStack[suit], 1);
+			}
+		});
+		return el;
+	}
+
+	// pileElement draws a stack of cards.  The tableau is fanned so every card can be seen.
+	function pileElement(stack) {
+		var el = document.createElement("div");
+		el.className = stack < COLUMNS ? "pile fanned" : "pile";
+		var cards = game.stacks[stack];
+		var first = 0;
+		if (stack === WASTE) {
+			first = Math.max(0, cards.length - game.draw);
+		} else if (stack >= FIRSTACE) {
+			first = Math.max(0, cards.length - 1);
+		}
+		if (stack >= FIRSTACE && cards.length === 0) {
+			el.textContent = suits["SHDC".charAt(stack - FIRSTACE)];
+		}
+		for (var i = first; i < cards.length; i++) {
+			var c = cardElement(cards[i], stack, i);
+			if (stack < COLUMNS) {
+				c.style.top = (i * 22) + "px";
+			} else if (stack === WASTE) {
+				c.style.left = ((i - first) * 18) + "px";
+			}
+			el.appendChild(c);
+		}
+		el.addEventListener("dragover", function (e) {
+			e.preventDefault();
+		});
+		el.addEventListener("drop", function (e) {
+			e.preventDefault();
+			var m = JSON.parse(e.dataTransfer.getData("text/plain"));
+			if (m.from !== stack) {
+				move(m.from, stack, m.count);
+			}
+		});
+		el.addEventListener("click", function () {
+			if (selected && selected.from !== stack) {
+				move(selected.from, stack, selected.count);
+			}
+		});
+		return el;
+	}
+
+	function draw() {
+		board.textContent = "";
+		var stock = document.createElement("div");
+		stock.className = "pile";
+		if (game.stock > 0) {
+			var back = document.createElement("div");
+			back.className = "card back";
+			stock.appendChild(back);
+		} else {
+			stock.textContent = "↻";
+		}
+		stock.title = "Deal";
+		stock.addEventListener("click", deal);
+		var top = document.createElement("div");
+		top.className = "row";
+		top.appendChild(stock);
+		top.appendChild(pileElement(WASTE));
+		top.appendChild(document.createElement("div"));
+		for (var i = FIRSTACE; i < FIRSTACE + 4; i++) {
+			top.appendChild(pileElement(i));
+		}
+		var tableau = document.createElement("div");
+		tableau.className = "row";
+		for (i = 0; i < COLUMNS; i++) {
+			tableau.appendChild(pileElement(i));
+		}
+		board.appendChild(top);
+		board.appendChild(tableau);
+	}
+
+	document.getElementById("new").addEventListener("click", newGame);
+	var id = localStorage.getItem("klondike");
+	if (!id) {
+		newGame();
+		return;
+	}
+	send("GET", "/games/" + id, null, function (code, data) {
+		if (code !== 200) {
+			newGame();
+			return;
+		}
+		setGame(data);
+	});
+}());
+`
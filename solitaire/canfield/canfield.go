@@ -0,0 +1,371 @@
+// This program allows a user to play the game canfield
+// It uses the ncurses tcell created by Garrett D'Amore which can be gotten by
+// go get -u github.com/gdamore/tcell
+//
+// Thirteen cards are dealt to a reserve, one to start the first foundation and four to the
+// tableau.  The rank of the card on the first foundation is the base every foundation starts
+// from and they go around the corner from king to ace.  The tableau builds down in alternate
+// colors and a space is filled from the reserve at once, or from any card once it is empty.
+// The stock is dealt three cards at a time and can be gone through as often as you like.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gdamore/tcell"
+	"github.com/tmasterson/cardgames/display"
+	"github.com/tmasterson/cardgames/generic"
+	"github.com/tmasterson/cardgames/solitaire"
+	"github.com/tmasterson/cardgames/stats"
+)
+
+// Move is a structure to track moves.
+// from:  Stack to move cards from
+// to:  Stack to move cards to
+// pass:  How many times the stock has been gone through
+// howmany:  Number of cards to move, 0 finds the card that fits
+type move struct {
+	from    int
+	to      int
+	pass    int
+	howmany int
+}
+
+// The stacks are the four tableau columns, the reserve, the waste and the four foundations.
+const (
+	columns     = 4
+	reserve     = 4
+	waste       = 5
+	firstAce    = 6
+	stackCount  = 10
+	reserveSize = 13
+)
+
+// Define the boxes for easier manipulation
+var aces [4]display.Box
+var reserveArea, wasteArea, playArea display.Box
+
+// Vcount is the number of cards dealt from the stock at a time.
+var vcount = 3
+
+// Daily is the deal of the day and the leaderboard it is recorded on.
+var daily stats.Daily
+
+// Moves is the number of moves made in the game, counted as cards are moved or dealt.
+var moves int
+
+// DrawScreen draws the screen putting all the boxes in place
+// s: The screen variable
+// style: The style for the screen
+//
+// Returns: Returns an error if one occurs otherwise nil
+func drawScreen(s tcell.Screen, style tcell.Style) error {
+	w, h := s.Size()
+	if w < 80 || h < 25 {
+		return errors.New("Screen size must be at least 80 by 25")
+	}
+	title := "Canfield"
+	display.PutString(s, w/2-len(title)/2, 0, style, title)
+	var err error
+	x := 0
+	for i := range aces {
+		aces[i], err = display.MakeBox(s, "Ace", x, 2, x+6, 4, style)
+		if err != nil {
+			return err
+		}
+		x = aces[i].RightX + 1
+	}
+	reserveArea, err = display.MakeBox(s, "Reserve", x+2, 2, x+12, 4, style)
+	if err != nil {
+		return err
+	}
+	wasteArea, err = display.MakeBox(s, "Waste", reserveArea.RightX+2, 2, reserveArea.RightX+10, 4, style)
+	if err != nil {
+		return err
+	}
+	playArea, err = display.MakeBox(s, "Tableau", 0, aces[0].BotY+2, 20, h-2, style)
+	if err != nil {
+		return err
+	}
+	x = playArea.RightX + 5
+	y := playArea.TopY
+	help := []string{
+		"Moves:",
+		"AD moves from stack 1 to stack 4 finding",
+		"   the face up card that fits.",
+		"A3D moves the top 3 cards of stack 1 to 4.",
+		"RA moves from the reserve to stack 1.",
+		"WA moves from the waste to stack 1.",
+		"A<Enter> moves from stack 1 to an ace stack.",
+		"Space deals from the stock.",
+		"Q quits.",
+	}
+	for _, line := range help {
+		display.PutString(s, x, y, style, line)
+		y++
+	}
+	for i := 0; i < columns; i++ {
+		display.PutString(s, playArea.LeftX+i*4+2, playArea.TopY+1, style, string(rune('A'+i)))
+	}
+	s.Show()
+	return nil
+}
+
+// Top returns the top card of a pile as a string or blanks if it is empty.
+func top(p solitaire.Pile) string {
+	if len(p.Cards) == 0 {
+		return "  "
+	}
+	c := p.Cards[len(p.Cards)-1]
+	return c.Rank + c.Suit
+}
+
+// ShowStacks prints the cards in each stack
+// s: Screen variable
+// stacks: A slice containing all the stacks or Piles of cards
+// style: The style for the cards
+func showStacks(s tcell.Screen, stacks []solitaire.Pile, style tcell.Style) {
+	rows := playArea.BotY - playArea.TopY - 2
+	for i := 0; i < columns; i++ {
+		pile := stacks[i]
+		var lines []string
+		for _, card := range pile.Cards {
+			lines = append(lines, card.Rank+card.Suit)
+		}
+		if len(lines) > rows {
+			lines = append([]string{".."}, lines[len(lines)-rows+1:]...)
+		}
+		for k := 0; k < rows; k++ {
+			str := "  "
+			if k < len(lines) {
+				str = lines[k]
+			}
+			display.PutString(s, playArea.LeftX+i*4+2, playArea.TopY+2+k, style, str)
+		}
+	}
+	for i := range aces {
+		display.PutString(s, aces[i].CardArea, aces[i].TopY+1, style, top(stacks[firstAce+i]))
+	}
+	display.PutString(s, reserveArea.CardArea, reserveArea.TopY+1, style, fmt.Sprintf("%s %02d", top(stacks[reserve]), len(stacks[reserve].Cards)))
+	str := "  "
+	if w := stacks[waste]; len(w.Cards) > 0 {
+		str = w.Cards[w.Firstfaceup].Rank + w.Cards[w.Firstfaceup].Suit
+	}
+	display.PutString(s, wasteArea.CardArea, wasteArea.TopY+1, style, str)
+	s.Show()
+}
+
+// Deal lays out a new game.
+func deal(deck *generic.Deck) []solitaire.Pile {
+	stacks := make([]solitaire.Pile, stackCount)
+	// Make aces low card instead of high card
+	for i := range deck.Cards {
+		if deck.Cards[i].Rvalue == 14 {
+			deck.Cards[i].Rvalue = 1
+		}
+	}
+	stacks[reserve].Cards = deck.Deal(reserveSize, 1)
+	stacks[reserve].Firstfaceup = reserveSize - 1
+	stacks[reserve].Ptype = 'R'
+	first := deck.Deal(1, 1)
+	for i := firstAce; i < stackCount; i++ {
+		stacks[i].Ptype = 'A'
+		stacks[i].Base = first[0].Rvalue
+		stacks[i].Wrap = true
+	}
+	stacks[firstAce].Cards = first
+	for i := 0; i < columns; i++ {
+		stacks[i].Cards = deck.Deal(1, 1)
+		stacks[i].Ptype = 'C'
+		stacks[i].Wrap = true
+	}
+	stacks[waste].Ptype = 'W'
+	return stacks
+}
+
+// Refill fills any empty tableau column from the reserve.
+func refill(stacks []solitaire.Pile) {
+	for i := 0; i < columns; i++ {
+		r := &stacks[reserve]
+		if len(stacks[i].Cards) == 0 && len(r.Cards) > 0 {
+			r.DoMove(&stacks[i], len(r.Cards)-1)
+		}
+	}
+}
+
+// ProcessKey handles the processing of key strokes
+//
+// ev:  The event that that contains the key.
+// stacks:  The card stacks.  Used to deal to the waste and find an ace stack for the Enter key.
+// deck:  The deck of cards used as the stock.
+// cm:  The move so far.
+//
+// returns: The move with the key added and a bool which is false if the player quit.
+func processKey(ev *tcell.EventKey, stacks []solitaire.Pile, deck *generic.Deck, cm move) (move, bool) {
+	switch ev.Key() {
+	case tcell.KeyEnter:
+		if cm.from != -1 {
+			cm.to = solitaire.Foundation(stacks, cm.from)
+			cm.howmany = 1
+			if cm.to == -1 {
+				cm = move{from: -1, to: -1, pass: cm.pass}
+			}
+		}
+	case tcell.KeyRune:
+		r := unicode.ToUpper(ev.Rune())
+		switch {
+		case r == 'Q':
+			return cm, false
+		case r == ' ':
+			if !deck.AllDealt || len(stacks[waste].Cards) > 0 {
+				cm.pass = solitaire.DealToWaste(&stacks[waste], deck, vcount, cm.pass)
+				moves++
+			}
+			cm = move{from: -1, to: -1, pass: cm.pass}
+		case r == 'R' && cm.from == -1:
+			cm.from = reserve
+		case r == 'W' && cm.from == -1:
+			cm.from = waste
+		case r >= 'A' && r < 'A'+columns:
+			if cm.from == -1 {
+				cm.from = int(r - 'A')
+			} else {
+				cm.to = int(r - 'A')
+			}
+		case r >= '0' && r <= '9' && cm.from != -1:
+			cm.howmany = cm.howmany*10 + int(r-'0')
+		default:
+			cm = move{from: -1, to: -1, pass: cm.pass}
+		}
+	}
+	return cm, true
+}
+
+// MoveCards makes the move once both ends are known and refills the tableau from the reserve.
+// Only the top card moves from the reserve and the waste.
+//
+// returns: The move reset for the next one, or unchanged if it is not complete.
+func moveCards(stacks []solitaire.Pile, cm move) move {
+	if cm.from < 0 || cm.to < 0 {
+		return cm
+	}
+	from := &stacks[cm.from]
+	to := &stacks[cm.to]
+	if cm.from != cm.to && len(from.Cards) > 0 {
+		index := -1
+		switch {
+		case to.Ptype == 'A' || from.Ptype != 'C':
+			index = len(from.Cards) - 1
+		case cm.howmany > 0 && cm.howmany <= len(from.Cards):
+			index = len(from.Cards) - cm.howmany
+		default:
+			index = from.FindMove(to)
+		}
+		if index >= 0 && from.CheckMove(to, index) {
+			from.DoMove(to, index)
+			refill(stacks)
+			moves++
+		}
+	}
+	return move{from: -1, to: -1, pass: cm.pass}
+}
+
+// Won tells if every card is on the aces.
+func won(stacks []solitaire.Pile) bool {
+	total := 0
+	for i := firstAce; i < stackCount; i++ {
+		total += len(stacks[i].Cards)
+	}
+	return total == 52
+}
+
+// PlayGame is the main function that handles all aspects of the game.
+//
+// s: Screen variable.
+// style: The style for the screen.
+//
+// returns: The number of cards on the aces, 52 if the game was won, and the number of moves made.
+func playGame(s tcell.Screen, style tcell.Style) (int, int) {
+	deck := generic.NewDeck()
+	deck.Source = daily.Source("canfield", fmt.Sprintf("draw %d", vcount), time.Now())
+	deck.Shuffle()
+	stacks := deal(&deck)
+	w, h := s.Size()
+	cardmove := move{from: -1, to: -1}
+	moves = 0
+	for {
+		showStacks(s, stacks, style)
+		status := fmt.Sprintf("Pass# %02d, Waste# %02d, Deck# %02d, Base %s", cardmove.pass+1, len(stacks[waste].Cards), len(deck.Cards)-deck.LastDealt, stacks[firstAce].Cards[0].Rank)
+		if cardmove.from >= 0 {
+			status += fmt.Sprintf(", Move from %c", "ABCDRW"[cardmove.from])
+		}
+		display.PutString(s, 0, h-1, style, strings.Repeat(" ", w-1))
+		display.PutString(s, 0, h-1, style, status)
+		s.Show()
+		if won(stacks) {
+			return 52, moves
+		}
+		ev := s.PollEvent()
+		switch ev := ev.(type) {
+		case *tcell.EventKey:
+			if ev.Key() == tcell.KeyCtrlL {
+				s.Sync()
+				continue
+			}
+			var ok bool
+			cardmove, ok = processKey(ev, stacks, &deck, cardmove)
+			if !ok {
+				total := 0
+				for i := firstAce; i < stackCount; i++ {
+					total += len(stacks[i].Cards)
+				}
+				return total, moves
+			}
+			cardmove = moveCards(stacks, cardmove)
+		}
+	}
+}
+
+func main() {
+	flag.IntVar(&vcount, "v", 3, "Number of cards dealt from the stock at a time")
+	daily.Flags(flag.CommandLine)
+	flag.Parse()
+	if vcount < 1 {
+		fmt.Fprintf(os.Stderr, "At least one card must be dealt at a time\n")
+		os.Exit(1)
+	}
+	tcell.SetEncodingFallback(tcell.EncodingFallbackASCII)
+	s, e := tcell.NewScreen()
+	if e != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", e)
+		os.Exit(1)
+	}
+	if e = s.Init(); e != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", e)
+		os.Exit(1)
+	}
+	s.Clear()
+	s.HideCursor()
+	if err := drawScreen(s, tcell.StyleDefault); err != nil {
+		s.Fini()
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	start := time.Now()
+	total, moves := playGame(s, tcell.StyleDefault)
+	s.Fini()
+	if total == 52 {
+		fmt.Println("Congratulations you won!")
+	} else {
+		fmt.Printf("You finished with %d cards on the aces.\n", total)
+	}
+	if err := daily.Record(os.Stdout, stats.Result{Game: "canfield", Won: total == 52, Moves: moves, Score: total, Elapsed: time.Since(start), Finished: time.Now()}); err != nil {
+		fmt.Fprintf(os.Stderr, "The result could not be saved: %v\n", err)
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell"
+	"github.com/tmasterson/cardgames/generic"
+)
+
+func key(r rune) *tcell.EventKey {
+	return tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone)
+}
+
+func TestDeal(t *testing.T) {
+	deck := generic.NewDeck()
+	deck.Shuffle()
+	stacks := deal(&deck)
+	if len(stacks[reserve].Cards) != reserveSize || !stacks[reserve].Cards[reserveSize-1].Faceup || stacks[reserve].Cards[0].Faceup {
+		t.Errorf("expected a reserve of %d with only the top face up", reserveSize)
+	}
+	base := stacks[firstAce].Cards[0].Rvalue
+	for i := firstAce; i < stackCount; i++ {
+		if stacks[i].Base != base || !stacks[i].Wrap {
+			t.Errorf("expected foundation %d based on %d but was %d", i, base, stacks[i].Base)
+		}
+	}
+	for i := 0; i < columns; i++ {
+		if len(stacks[i].Cards) != 1 || stacks[i].Ptype != 'C' {
+			t.Errorf("expected one card in column %d", i)
+		}
+	}
+	if deck.LastDealt != reserveSize+1+columns {
+		t.Errorf("expected %d cards dealt but was %d", reserveSize+1+columns, deck.LastDealt)
+	}
+}
+
+func TestRefill(t *testing.T) {
+	deck := generic.NewDeck()
+	stacks := deal(&deck)
+	next := stacks[reserve].Cards[reserveSize-2]
+	stacks[firstAce].Base = stacks[0].Cards[0].Rvalue
+	stacks[firstAce].Cards = nil
+	cm := move{from: -1, to: -1}
+	cm, _ = processKey(key('a'), stacks, &deck, cm)
+	cm, _ = processKey(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone), stacks, &deck, cm)
+	moveCards(stacks, cm)
+	if len(stacks[firstAce].Cards) != 1 {
+		t.Fatalf("expected the card to go to the foundation")
+	}
+	if len(stacks[0].Cards) != 1 || len(stacks[reserve].Cards) != reserveSize-1 {
+		t.Errorf("expected column A refilled from the reserve")
+	}
+	if !stacks[reserve].Cards[reserveSize-2].Faceup || stacks[reserve].Cards[reserveSize-2].Rank != next.Rank {
+		t.Errorf("expected the next reserve card turned up")
+	}
+}
+
+func TestStock(t *testing.T) {
+	deck := generic.NewDeck()
+	stacks := deal(&deck)
+	cm := move{from: -1, to: -1}
+	moves = 0
+	left := len(deck.Cards) - deck.LastDealt
+	for i := 0; i < (left+vcount-1)/vcount; i++ {
+		cm, _ = processKey(key(' '), stacks, &deck, cm)
+	}
+	if len(stacks[waste].Cards) != left || cm.pass != 0 {
+		t.Errorf("expected %d cards on the waste but was %d", left, len(stacks[waste].Cards))
+	}
+	cm, _ = processKey(key(' '), stacks, &deck, cm)
+	if cm.pass != 1 || len(stacks[waste].Cards) != vcount {
+		t.Errorf("expected the waste turned over for pass 2 but was %d", cm.pass)
+	}
+	if want := (left+vcount-1)/vcount + 1; moves != want {
+		t.Errorf("Expected every deal to be a move, %d, but was %d", want, moves)
+	}
+	if _, ok := processKey(key('q'), stacks, &deck, cm); ok {
+		t.Errorf("expected q to quit")
+	}
+}
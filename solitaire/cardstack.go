@@ -30,10 +30,22 @@ type CheckMover interface {
 
 // Pile is the base for all card stacks.
 // Could possibly be made into a generic hand and moved to the generic package
+//
+// Ptype tells what kind of pile it is and so what may be moved onto it:
+// 'T' a tableau built down in alternate colors, 'S' a tableau built down by suit,
+// 'C' a tableau built down in alternate colors taking any card when empty as in canfield,
+// 'F' a tableau built down by suit taking any card when empty as in forty thieves,
+// 'M' a tableau built down in the same color taking any card when empty as in whitehead, where
+// only cards of one suit in order move together,
+// 'W' the waste, 'A' the aces and 'G' a waste built one rank up or down as in golf.
+// Wrap lets a pile go around the corner between king and ace.
+// Base is the rank an 'A' pile starts with, 0 means an ace.
 type Pile struct {
 	Cards       []generic.Card
 	Firstfaceup int
 	Ptype       rune
+	Wrap        bool
+	Base        int
 //	mux         sync.Mutex // set up locking for safety
 }
 
@@ -83,6 +95,9 @@ func (p *Pile) CheckMove(to *Pile, index int) bool {
 	if p.Ptype == 'A' { // can't move from aces
 		return false
 	}
+	if p.Ptype == 'M' && !p.Run(index, true) { // whitehead moves only runs of one suit
+		return false
+	}
 	card1 := p.Cards[index]
 	// if to is empty generate a dummy card for checking legal moves
 	var card2 generic.Card
@@ -99,13 +114,141 @@ func (p *Pile) CheckMove(to *Pile, index int) bool {
 		if card1.Rvalue == 13 && card2.Rvalue == 0 && card1.Faceup {
 			return true
 		}
+	case 'S': // tableau built down by suit
+		if card1.Rvalue == card2.Rvalue-1 && card1.Suit == card2.Suit && card1.Faceup && card2.Faceup {
+			return true
+		}
+		if card1.Rvalue == 13 && card2.Rvalue == 0 && card1.Faceup {
+			return true
+		}
+	case 'M': // tableau built down in the same color, anything on an empty pile
+		if card2.Rvalue == 0 {
+			return card1.Faceup
+		}
+		if card1.Rvalue == card2.Rvalue-1 && card1.Color == card2.Color && card1.Faceup && card2.Faceup {
+			return true
+		}
+	case 'C': // down in alternate colors, anything on an empty pile
+		if card2.Rvalue == 0 {
+			return card1.Faceup
+		}
+		if (card1.Rvalue == card2.Rvalue-1 || (to.Wrap && card1.Rvalue == 13 && card2.Rvalue == 1)) && card1.Color != card2.Color && card1.Faceup && card2.Faceup {
+			return true
+		}
+	case 'F': // down by suit, anything on an empty pile
+		if card2.Rvalue == 0 {
+			return card1.Faceup
+		}
+		if card1.Rvalue == card2.Rvalue-1 && card1.Suit == card2.Suit && card1.Faceup && card2.Faceup {
+			return true
+		}
+	case 'G': // one rank up or down regardless of suit, anything on an empty pile
+		if card2.Rvalue == 0 || Adjacent(card1, card2, to.Wrap) {
+			return card1.Faceup
+		}
 	case 'A':
+		base := to.Base
+		if base == 0 {
+			base = 1
+		}
 		switch {
-		case card1.Rvalue == 1 && card2.Rvalue == 0:
-			return true
+		case card2.Rvalue == 0:
+			return card1.Rvalue == base
+		case len(to.Cards) == 13: // a full suit
+			return false
 		case card1.Rvalue-1 == card2.Rvalue && card1.Suit == card2.Suit:
 			return true
+		case to.Wrap && card1.Rvalue == 1 && card2.Rvalue == 13 && card1.Suit == card2.Suit:
+			return true
 		}
 	}
 	return false
 }
+
+// Adjacent  Tells if two cards are one rank apart either up or down.
+// With wrap a king and an ace are also adjacent.
+func Adjacent(a, b generic.Card, wrap bool) bool {
+	d := a.Rvalue - b.Rvalue
+	if d == 1 || d == -1 {
+		return true
+	}
+	return wrap && (d == 12 || d == -12)
+}
+
+// Run  Tells if the cards from index to the top of a Pile are face up and in order for moving
+// together, down by one rank each and by suit if suit is true or in alternate colors if not.
+func (p *Pile) Run(index int, suit bool) bool {
+	if index < 0 || index >= len(p.Cards) {
+		return false
+	}
+	for i := index; i < len(p.Cards); i++ {
+		c := p.Cards[i]
+		if !c.Faceup {
+			return false
+		}
+		if i == index {
+			continue
+		}
+		b := p.Cards[i-1]
+		if c.Rvalue != b.Rvalue-1 || (suit && c.Suit != b.Suit) || (!suit && c.Color == b.Color) {
+			return false
+		}
+	}
+	return true
+}
+
+// FindMove  Finds the first face up card in a Pile that can be moved onto another.
+// to is the Pile you want to move cards onto.
+// Returns the index of the card or -1 if nothing can move.
+func (p *Pile) FindMove(to *Pile) int {
+	for i := p.Firstfaceup; i < len(p.Cards); i++ {
+		if p.Cards[i].Faceup && p.CheckMove(to, i) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Foundation  Finds an ace pile that the top card of a Pile can be moved to.
+// piles holds all the piles in the game and from is the index of the Pile to move from.
+// Returns the index of the first ace pile that will take the card or -1 if there is none.
+func Foundation(piles []Pile, from int) int {
+	p := &piles[from]
+	if len(p.Cards) == 0 {
+		return -1
+	}
+	for i := range piles {
+		if piles[i].Ptype == 'A' && i != from && p.CheckMove(&piles[i], len(p.Cards)-1) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Safe  Tells if a card can go to the aces without any chance of it being needed in the tableau.
+// That is when every card that could be built on it in the tableau is already on the aces.
+// piles holds all the piles in the game, c is the card and build is the Ptype of the tableau.
+func Safe(piles []Pile, c generic.Card, build rune) bool {
+	// building by suit only the same suit goes on it and that is already on the aces
+	if c.Rvalue <= 2 || build == 'S' || build == 'F' {
+		return true
+	}
+	suits := map[string]string{"S": "black", "C": "black", "H": "red", "D": "red"}
+	height := make(map[string]int)
+	for _, p := range piles {
+		if p.Ptype == 'A' && len(p.Cards) > 0 {
+			top := p.Cards[len(p.Cards)-1]
+			height[top.Suit] = top.Rvalue
+		}
+	}
+	for suit, color := range suits {
+		if suit == c.Suit {
+			continue
+		}
+		// whitehead builds on the same color, otherwise it is the opposite color
+		if (color == c.Color) == (build == 'M') && height[suit] < c.Rvalue-1 {
+			return false
+		}
+	}
+	return true
+}
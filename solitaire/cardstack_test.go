@@ -174,3 +174,252 @@ func TestDoMove(t *testing.T) {
 		t.Errorf("First faceup should be 1 but is %d", p2.Firstfaceup)
 	}
 }
+
+func TestCheckMoveSuit(t *testing.T) {
+	var p1, p2 Pile
+	p1.Ptype = 'S'
+	p2.Ptype = 'S'
+	p1.Cards = append(p1.Cards, generic.NewCard("8", "S", "black", 8, 16, true))
+	p2.Cards = append(p2.Cards, generic.NewCard("7", "H", "red", 7, 8, true))
+	if p2.CheckMove(&p1, 0) {
+		t.Errorf("Should not be able to move %+v to %+v", p2.Cards[0], p1.Cards[0])
+	}
+	p2.Cards[0] = generic.NewCard("7", "S", "black", 7, 16, true)
+	if !p2.CheckMove(&p1, 0) {
+		t.Errorf("Should have been able to move %+v to %+v", p2.Cards[0], p1.Cards[0])
+	}
+	p1.Cards = p1.Cards[:0]
+	if p2.CheckMove(&p1, 0) {
+		t.Errorf("Only a king should move to an empty pile")
+	}
+	p2.Cards[0] = generic.NewCard("K", "S", "black", 13, 16, true)
+	if !p2.CheckMove(&p1, 0) {
+		t.Errorf("Should have been able to move a king to an empty pile")
+	}
+}
+
+func TestFindMove(t *testing.T) {
+	var p1, p2 Pile
+	p1.Ptype = 'T'
+	p2.Ptype = 'T'
+	p1.Cards = append(p1.Cards, generic.NewCard("9", "H", "red", 9, 8, false))
+	p1.Cards = append(p1.Cards, generic.NewCard("8", "S", "black", 8, 16, true))
+	p1.Cards = append(p1.Cards, generic.NewCard("2", "D", "red", 2, 4, true))
+	p1.Firstfaceup = 1
+	p2.Cards = append(p2.Cards, generic.NewCard("9", "D", "red", 9, 4, true))
+	if i := p1.FindMove(&p2); i != 1 {
+		t.Errorf("expected 1 but was %d", i)
+	}
+	p2.Cards[0] = generic.NewCard("T", "D", "red", 10, 4, true)
+	if i := p1.FindMove(&p2); i != -1 {
+		t.Errorf("expected -1 but was %d", i)
+	}
+}
+
+func TestFoundation(t *testing.T) {
+	piles := make([]Pile, 3)
+	piles[0].Ptype = 'T'
+	piles[1].Ptype = 'A'
+	piles[2].Ptype = 'A'
+	piles[0].Cards = append(piles[0].Cards, generic.NewCard("A", "S", "black", 1, 16, true))
+	piles[1].Cards = append(piles[1].Cards, generic.NewCard("A", "H", "red", 1, 8, true))
+	if i := Foundation(piles, 0); i != 2 {
+		t.Errorf("expected 2 but was %d", i)
+	}
+	piles[0].Cards[0] = generic.NewCard("2", "H", "red", 2, 8, true)
+	if i := Foundation(piles, 0); i != 1 {
+		t.Errorf("expected 1 but was %d", i)
+	}
+	piles[0].Cards = piles[0].Cards[:0]
+	if i := Foundation(piles, 0); i != -1 {
+		t.Errorf("expected -1 but was %d", i)
+	}
+}
+
+func TestCheckMoveGolf(t *testing.T) {
+	var p1, p2 Pile
+	p2.Ptype = 'G'
+	p1.Cards = append(p1.Cards, generic.NewCard("K", "S", "black", 13, 16, true))
+	if !p1.CheckMove(&p2, 0) {
+		t.Errorf("Should be able to move anything onto an empty golf pile")
+	}
+	p2.Cards = append(p2.Cards, generic.NewCard("A", "H", "red", 1, 8, true))
+	if p1.CheckMove(&p2, 0) {
+		t.Errorf("Should not be able to move %+v to %+v without wrap", p1.Cards[0], p2.Cards[0])
+	}
+	p2.Wrap = true
+	if !p1.CheckMove(&p2, 0) {
+		t.Errorf("Should be able to move %+v to %+v with wrap", p1.Cards[0], p2.Cards[0])
+	}
+	p2.Cards = append(p2.Cards, generic.NewCard("Q", "S", "black", 12, 16, true))
+	if !p1.CheckMove(&p2, 0) {
+		t.Errorf("Should be able to move %+v to %+v", p1.Cards[0], p2.Cards[1])
+	}
+	p2.Cards[1].Rvalue = 11
+	if p1.CheckMove(&p2, 0) {
+		t.Errorf("Should not be able to move %+v to %+v", p1.Cards[0], p2.Cards[1])
+	}
+}
+
+func TestAdjacent(t *testing.T) {
+	a := generic.NewCard("A", "S", "black", 1, 16, true)
+	two := generic.NewCard("2", "H", "red", 2, 8, true)
+	k := generic.NewCard("K", "C", "black", 13, 2, true)
+	if !Adjacent(a, two, false) || !Adjacent(two, a, false) || Adjacent(a, a, true) {
+		t.Errorf("Expected A and 2 adjacent and A not adjacent to itself")
+	}
+	if Adjacent(a, k, false) || !Adjacent(k, a, true) || Adjacent(two, k, true) {
+		t.Errorf("Expected A and K adjacent only with wrap")
+	}
+}
+
+func TestCheckMoveBase(t *testing.T) {
+	var p1, p2 Pile
+	p2.Ptype = 'A'
+	p2.Base = 12
+	p2.Wrap = true
+	p1.Cards = append(p1.Cards, generic.NewCard("A", "S", "black", 1, 16, true))
+	if p1.CheckMove(&p2, 0) {
+		t.Errorf("Should not be able to start a foundation based on queens with %+v", p1.Cards[0])
+	}
+	p1.Cards = append(p1.Cards, generic.NewCard("K", "S", "black", 13, 16, true))
+	p1.Cards = append(p1.Cards, generic.NewCard("Q", "S", "black", 12, 16, true))
+	p1.DoMove(&p2, 2)
+	if p1.CheckMove(&p2, 1) == false {
+		t.Errorf("Should be able to move %+v to %+v", p1.Cards[1], p2.Cards)
+	}
+	p1.DoMove(&p2, 1)
+	if !p1.CheckMove(&p2, 0) {
+		t.Errorf("Should be able to wrap %+v onto %+v", p1.Cards[0], p2.Cards)
+	}
+	p2.Wrap = false
+	if p1.CheckMove(&p2, 0) {
+		t.Errorf("Should not be able to wrap %+v onto %+v", p1.Cards[0], p2.Cards)
+	}
+}
+
+func TestCheckMoveCanfield(t *testing.T) {
+	var p1, p2 Pile
+	p2.Ptype = 'C'
+	p1.Cards = append(p1.Cards, generic.NewCard("5", "S", "black", 5, 16, true))
+	if !p1.CheckMove(&p2, 0) {
+		t.Errorf("Should be able to move anything onto an empty canfield tableau")
+	}
+	p2.Cards = append(p2.Cards, generic.NewCard("A", "H", "red", 1, 8, true))
+	p1.Cards[0] = generic.NewCard("K", "S", "black", 13, 16, true)
+	if p1.CheckMove(&p2, 0) {
+		t.Errorf("Should not be able to move %+v to %+v without wrap", p1.Cards[0], p2.Cards[0])
+	}
+	p2.Wrap = true
+	if !p1.CheckMove(&p2, 0) {
+		t.Errorf("Should be able to move %+v to %+v with wrap", p1.Cards[0], p2.Cards[0])
+	}
+	p2.Cards[0].Color = "black"
+	if p1.CheckMove(&p2, 0) {
+		t.Errorf("Should not be able to move %+v to %+v of the same color", p1.Cards[0], p2.Cards[0])
+	}
+}
+
+func TestRun(t *testing.T) {
+	var p Pile
+	p.Cards = append(p.Cards, generic.NewCard("9", "S", "black", 9, 16, false))
+	p.Cards = append(p.Cards, generic.NewCard("8", "H", "red", 8, 8, true))
+	p.Cards = append(p.Cards, generic.NewCard("7", "H", "red", 7, 8, true))
+	p.Cards = append(p.Cards, generic.NewCard("6", "S", "black", 6, 16, true))
+	if p.Run(0, false) || p.Run(4, false) {
+		t.Errorf("A face down card or no cards is not a run")
+	}
+	if !p.Run(2, false) || p.Run(1, false) {
+		t.Errorf("Expected 7H 6S to be a run in alternate colors but not 8H 7H")
+	}
+	if p.Run(2, true) {
+		t.Errorf("Expected 7H 6S not to be a run by suit")
+	}
+	p.Cards[3] = generic.NewCard("6", "H", "red", 6, 8, true)
+	if !p.Run(1, true) {
+		t.Errorf("Expected 8H 7H 6H to be a run by suit")
+	}
+	if !p.Run(3, true) {
+		t.Errorf("A single card is always a run")
+	}
+}
+
+func TestCheckMoveThieves(t *testing.T) {
+	var p1, p2 Pile
+	p2.Ptype = 'F'
+	p1.Cards = append(p1.Cards, generic.NewCard("5", "S", "black", 5, 16, true))
+	if !p1.CheckMove(&p2, 0) {
+		t.Errorf("Should be able to move anything onto an empty forty thieves tableau")
+	}
+	p2.Cards = append(p2.Cards, generic.NewCard("6", "C", "black", 6, 2, true))
+	if p1.CheckMove(&p2, 0) {
+		t.Errorf("Should not be able to move %+v to %+v", p1.Cards[0], p2.Cards[0])
+	}
+	p2.Cards[0].Suit = "S"
+	if !p1.CheckMove(&p2, 0) {
+		t.Errorf("Should be able to move %+v to %+v", p1.Cards[0], p2.Cards[0])
+	}
+}
+
+func TestCheckMoveColor(t *testing.T) {
+	var p1, p2 Pile
+	p2.Ptype = 'M'
+	p1.Cards = append(p1.Cards, generic.NewCard("7", "H", "red", 7, 8, true))
+	p2.Cards = append(p2.Cards, generic.NewCard("8", "D", "red", 8, 4, true))
+	if !p1.CheckMove(&p2, 0) {
+		t.Errorf("Should be able to move %+v to %+v", p1.Cards[0], p2.Cards[0])
+	}
+	p2.Cards[0] = generic.NewCard("8", "S", "black", 8, 16, true)
+	if p1.CheckMove(&p2, 0) {
+		t.Errorf("Should not be able to move %+v to %+v", p1.Cards[0], p2.Cards[0])
+	}
+	p2.Cards = p2.Cards[:0]
+	if !p1.CheckMove(&p2, 0) {
+		t.Errorf("Any card can move to an empty pile")
+	}
+	p1.Ptype = 'M'
+	p1.Cards = []generic.Card{generic.NewCard("8", "D", "red", 8, 4, true), generic.NewCard("7", "H", "red", 7, 8, true)}
+	if p1.CheckMove(&p2, 0) {
+		t.Errorf("Should not be able to move %+v together as they are not the same suit", p1.Cards)
+	}
+	p1.Cards[1] = generic.NewCard("7", "D", "red", 7, 4, true)
+	if !p1.CheckMove(&p2, 0) {
+		t.Errorf("Should be able to move %+v together", p1.Cards)
+	}
+}
+
+func TestSafe(t *testing.T) {
+	piles := make([]Pile, 4)
+	for i := range piles {
+		piles[i].Ptype = 'A'
+	}
+	piles[0].Cards = []generic.Card{generic.NewCard("4", "S", "black", 4, 16, true)}
+	piles[1].Cards = []generic.Card{generic.NewCard("3", "H", "red", 3, 8, true)}
+	piles[2].Cards = []generic.Card{generic.NewCard("2", "D", "red", 2, 4, true)}
+	two := generic.NewCard("2", "C", "black", 2, 2, true)
+	if !Safe(piles, two, 'T') {
+		t.Errorf("A two is always safe")
+	}
+	fourH := generic.NewCard("4", "H", "red", 4, 8, true)
+	if Safe(piles, fourH, 'T') {
+		t.Errorf("4H is not safe with no clubs on the aces")
+	}
+	piles[3].Cards = []generic.Card{generic.NewCard("3", "C", "black", 3, 2, true)}
+	if !Safe(piles, fourH, 'T') {
+		t.Errorf("4H is safe with the black threes on the aces")
+	}
+	fiveS := generic.NewCard("5", "S", "black", 5, 16, true)
+	if Safe(piles, fiveS, 'T') {
+		t.Errorf("5S is not safe with 2D on the aces")
+	}
+	if !Safe(piles, fiveS, 'S') {
+		t.Errorf("Any card is safe when building by suit")
+	}
+	if Safe(piles, fiveS, 'M') {
+		t.Errorf("5S is not safe in the same color with 3C on the aces")
+	}
+	piles[3].Cards[0] = generic.NewCard("4", "C", "black", 4, 2, true)
+	if !Safe(piles, fiveS, 'M') {
+		t.Errorf("5S is safe in the same color with 4C on the aces")
+	}
+}
@@ -0,0 +1,351 @@
+// This program allows a user to play the game forty thieves and its variants lucas and josephine
+// It uses the ncurses tcell created by Garrett D'Amore which can be gotten by
+// go get -u github.com/gdamore/tcell
+//
+// Two decks are used.  Forty cards are dealt face up to ten columns which are built down by suit
+// and an empty column takes any card.  Only one card is moved at a time and the stock is dealt
+// one card at a time with a single pass.  All eight aces go to the foundations.
+// Lucas starts with the aces on the foundations and thirteen columns of three.
+// Josephine lets a run in suit be moved together.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gdamore/tcell"
+	"github.com/tmasterson/cardgames/display"
+	"github.com/tmasterson/cardgames/generic"
+	"github.com/tmasterson/cardgames/solitaire"
+	"github.com/tmasterson/cardgames/stats"
+)
+
+// Move is a structure to track moves.
+// from:  Stack to move cards from
+// to:  Stack to move cards to
+type move struct {
+	from int
+	to   int
+}
+
+// The number of foundations and cards in the two decks.
+const (
+	foundations = 8
+	cardCount   = 104
+)
+
+// Define the boxes for easier manipulation
+var aces [foundations]display.Box
+var wasteArea, playArea display.Box
+
+// Variant is the game being played, thieves, lucas or josephine.
+var variant = "thieves"
+
+// The stacks are the columns, the waste and then the foundations.
+// Columns is set by the variant.
+var columns = 10
+
+// Moves is the number of moves made in the game, counted as cards are moved or dealt.
+var moves int
+
+// Waste returns the index of the waste stack.
+func waste() int {
+	return columns
+}
+
+// FirstAce returns the index of the first foundation.
+func firstAce() int {
+	return columns + 1
+}
+
+// DrawScreen draws the screen putting all the boxes in place
+// s: The screen variable
+// style: The style for the screen
+//
+// Returns: Returns an error if one occurs otherwise nil
+func drawScreen(s tcell.Screen, style tcell.Style) error {
+	w, h := s.Size()
+	if w < 80 || h < 25 {
+		return errors.New("Screen size must be at least 80 by 25")
+	}
+	titles := map[string]string{"thieves": "Forty Thieves", "lucas": "Lucas", "josephine": "Josephine"}
+	title := titles[variant]
+	display.PutString(s, w/2-len(title)/2, 0, style, title)
+	var err error
+	x := 0
+	for i := range aces {
+		aces[i], err = display.MakeBox(s, "Ace", x, 2, x+6, 4, style)
+		if err != nil {
+			return err
+		}
+		x = aces[i].RightX + 1
+	}
+	wasteArea, err = display.MakeBox(s, "Waste", x+2, 2, x+10, 4, style)
+	if err != nil {
+		return err
+	}
+	playArea, err = display.MakeBox(s, "Tableau", 0, aces[0].BotY+2, columns*4+2, h-3, style)
+	if err != nil {
+		return err
+	}
+	help := "AB moves from stack 1 to 2, WA from the waste, A<Enter> to the aces, space deals, Q quits."
+	display.PutString(s, 0, h-2, style, help)
+	for i := 0; i < columns; i++ {
+		display.PutString(s, playArea.LeftX+i*4+2, playArea.TopY+1, style, string(rune('A'+i)))
+	}
+	s.Show()
+	return nil
+}
+
+// ShowStacks prints the cards in each stack
+// If a column is too long for the screen the cards nearest the bottom are replaced with ..
+// s: Screen variable
+// stacks: A slice containing all the stacks or Piles of cards
+// style: The style for the cards
+func showStacks(s tcell.Screen, stacks []solitaire.Pile, style tcell.Style) {
+	rows := playArea.BotY - playArea.TopY - 2
+	for i := 0; i < columns; i++ {
+		var lines []string
+		for _, card := range stacks[i].Cards {
+			lines = append(lines, card.Rank+card.Suit)
+		}
+		if len(lines) > rows {
+			lines = append([]string{".."}, lines[len(lines)-rows+1:]...)
+		}
+		for k := 0; k < rows; k++ {
+			str := "  "
+			if k < len(lines) {
+				str = lines[k]
+			}
+			display.PutString(s, playArea.LeftX+i*4+2, playArea.TopY+2+k, style, str)
+		}
+	}
+	for i := range aces {
+		str := "  "
+		if p := stacks[firstAce()+i]; len(p.Cards) > 0 {
+			str = p.Cards[len(p.Cards)-1].Rank + p.Cards[len(p.Cards)-1].Suit
+		}
+		display.PutString(s, aces[i].CardArea, aces[i].TopY+1, style, str)
+	}
+	str := "  "
+	if p := stacks[waste()]; len(p.Cards) > 0 {
+		str = p.Cards[len(p.Cards)-1].Rank + p.Cards[len(p.Cards)-1].Suit
+	}
+	display.PutString(s, wasteArea.CardArea, wasteArea.TopY+1, style, str)
+	s.Show()
+}
+
+// Deal lays out a new game for the variant.
+// For lucas the aces are taken out of the deck and put on the foundations first.
+func deal(deck *generic.Deck) []solitaire.Pile {
+	depth := 4
+	columns = 10
+	if variant == "lucas" {
+		depth = 3
+		columns = 13
+	}
+	stacks := make([]solitaire.Pile, columns+1+foundations)
+	// Make aces low card instead of high card
+	for i := range deck.Cards {
+		if deck.Cards[i].Rvalue == 14 {
+			deck.Cards[i].Rvalue = 1
+		}
+	}
+	for i := firstAce(); i < len(stacks); i++ {
+		stacks[i].Ptype = 'A'
+	}
+	if variant == "lucas" {
+		var rest []generic.Card
+		ace := firstAce()
+		for _, c := range deck.Cards {
+			if c.Rvalue == 1 {
+				c.Faceup = true
+				stacks[ace].Cards = append(stacks[ace].Cards, c)
+				ace++
+			} else {
+				rest = append(rest, c)
+			}
+		}
+		deck.Cards = rest
+	}
+	for i := 0; i < columns; i++ {
+		stacks[i].Cards = deck.Deal(depth, depth)
+		stacks[i].Ptype = 'F'
+	}
+	stacks[waste()].Ptype = 'W'
+	return stacks
+}
+
+// ProcessKey handles the processing of key strokes
+//
+// ev:  The event that that contains the key.
+// stacks:  The card stacks.  Used to deal to the waste and find an ace stack for the Enter key.
+// deck:  The deck of cards used as the stock.
+// cm:  The move so far.
+//
+// returns: The move with the key added and a bool which is false if the player quit.
+func processKey(ev *tcell.EventKey, stacks []solitaire.Pile, deck *generic.Deck, cm move) (move, bool) {
+	switch ev.Key() {
+	case tcell.KeyEnter:
+		if cm.from != -1 {
+			cm.to = solitaire.Foundation(stacks, cm.from)
+			if cm.to == -1 {
+				cm = move{from: -1, to: -1}
+			}
+		}
+	case tcell.KeyRune:
+		r := unicode.ToUpper(ev.Rune())
+		switch {
+		case r == 'Q':
+			return cm, false
+		case r == ' ':
+			// a single pass through the stock
+			if !deck.AllDealt {
+				solitaire.DealToWaste(&stacks[waste()], deck, 1, 0)
+				moves++
+			}
+			cm = move{from: -1, to: -1}
+		case r == 'W' && cm.from == -1:
+			cm.from = waste()
+		case r >= 'A' && r < 'A'+rune(columns):
+			if cm.from == -1 {
+				cm.from = int(r - 'A')
+			} else {
+				cm.to = int(r - 'A')
+			}
+		default:
+			cm = move{from: -1, to: -1}
+		}
+	}
+	return cm, true
+}
+
+// MoveCards makes the move once both ends are known.
+// Only the top card moves unless playing josephine where the longest run in suit that fits is moved.
+//
+// returns: The move reset for the next one, or unchanged if it is not complete.
+func moveCards(stacks []solitaire.Pile, cm move) move {
+	if cm.from < 0 || cm.to < 0 {
+		return cm
+	}
+	from := &stacks[cm.from]
+	to := &stacks[cm.to]
+	if cm.from != cm.to && len(from.Cards) > 0 {
+		index := len(from.Cards) - 1
+		if variant == "josephine" && to.Ptype == 'F' && from.Ptype == 'F' {
+			for i := 0; i < len(from.Cards); i++ {
+				if from.Run(i, true) && from.CheckMove(to, i) {
+					index = i
+					break
+				}
+			}
+		}
+		if from.CheckMove(to, index) {
+			from.DoMove(to, index)
+			moves++
+		}
+	}
+	return move{from: -1, to: -1}
+}
+
+// Won tells if every card is on the foundations.
+func won(stacks []solitaire.Pile) bool {
+	total := 0
+	for i := firstAce(); i < len(stacks); i++ {
+		total += len(stacks[i].Cards)
+	}
+	return total == cardCount
+}
+
+// PlayGame is the main function that handles all aspects of the game.
+//
+// s: Screen variable.
+// stacks: The dealt game.
+// deck: The deck used as the stock.
+// style: The style for the screen.
+//
+// returns: true if the game was won and the number of moves made.
+func playGame(s tcell.Screen, stacks []solitaire.Pile, deck *generic.Deck, style tcell.Style) (bool, int) {
+	w, h := s.Size()
+	cardmove := move{from: -1, to: -1}
+	moves = 0
+	for {
+		showStacks(s, stacks, style)
+		status := fmt.Sprintf("Waste# %02d, Deck# %02d", len(stacks[waste()].Cards), len(deck.Cards)-deck.LastDealt)
+		if cardmove.from >= 0 {
+			name := string(rune('A' + cardmove.from))
+			if cardmove.from == waste() {
+				name = "W"
+			}
+			status += ", Move from " + name
+		}
+		display.PutString(s, 0, h-1, style, strings.Repeat(" ", w-1))
+		display.PutString(s, 0, h-1, style, status)
+		s.Show()
+		if won(stacks) {
+			return true, moves
+		}
+		ev := s.PollEvent()
+		switch ev := ev.(type) {
+		case *tcell.EventKey:
+			if ev.Key() == tcell.KeyCtrlL {
+				s.Sync()
+				continue
+			}
+			var ok bool
+			cardmove, ok = processKey(ev, stacks, deck, cardmove)
+			if !ok {
+				return false, moves
+			}
+			cardmove = moveCards(stacks, cardmove)
+		}
+	}
+}
+
+func main() {
+	flag.StringVar(&variant, "game", "thieves", "The game to play: thieves, lucas or josephine")
+	var daily stats.Daily
+	daily.Flags(flag.CommandLine)
+	flag.Parse()
+	if variant != "thieves" && variant != "lucas" && variant != "josephine" {
+		fmt.Fprintf(os.Stderr, "Unknown game %s, use thieves, lucas or josephine\n", variant)
+		os.Exit(1)
+	}
+	deck := generic.NewDecks(2)
+	deck.Source = daily.Source(variant, "", time.Now())
+	deck.Shuffle()
+	stacks := deal(&deck)
+	tcell.SetEncodingFallback(tcell.EncodingFallbackASCII)
+	s, e := tcell.NewScreen()
+	if e != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", e)
+		os.Exit(1)
+	}
+	if e = s.Init(); e != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", e)
+		os.Exit(1)
+	}
+	s.Clear()
+	s.HideCursor()
+	if err := drawScreen(s, tcell.StyleDefault); err != nil {
+		s.Fini()
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	start := time.Now()
+	won, moves := playGame(s, stacks, &deck, tcell.StyleDefault)
+	s.Fini()
+	if won {
+		fmt.Println("Congratulations you won!")
+	} else {
+		fmt.Println("You either quit or lost. Better luck next time.")
+	}
+	if err := daily.Record(os.Stdout, stats.Result{Game: variant, Won: won, Moves: moves, Elapsed: time.Since(start), Finished: time.Now()}); err != nil {
+		fmt.Fprintf(os.Stderr, "The result could not be saved: %v\n", err)
+	}
+}
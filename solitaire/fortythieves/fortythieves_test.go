@@ -0,0 +1,97 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell"
+	"github.com/tmasterson/cardgames/generic"
+)
+
+func key(r rune) *tcell.EventKey {
+	return tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone)
+}
+
+func TestDeal(t *testing.T) {
+	deck := generic.NewDecks(2)
+	deck.Shuffle()
+	stacks := deal(&deck)
+	if columns != 10 || len(stacks) != 19 || deck.LastDealt != 40 {
+		t.Errorf("expected 10 columns of 4 but was %d columns and %d cards", columns, deck.LastDealt)
+	}
+	variant = "lucas"
+	defer func() { variant = "thieves" }()
+	deck = generic.NewDecks(2)
+	deck.Shuffle()
+	stacks = deal(&deck)
+	if columns != 13 || len(deck.Cards) != cardCount-foundations || deck.LastDealt != 39 {
+		t.Errorf("expected 13 columns of 3 without the aces but was %d columns and %d cards", columns, len(deck.Cards))
+	}
+	for i := firstAce(); i < len(stacks); i++ {
+		if len(stacks[i].Cards) != 1 || stacks[i].Cards[0].Rvalue != 1 {
+			t.Errorf("expected an ace on foundation %d", i)
+		}
+	}
+}
+
+func TestSecondAce(t *testing.T) {
+	deck := generic.NewDecks(2)
+	stacks := deal(&deck)
+	ace := generic.NewCard("A", "S", "black", 1, 16, true)
+	stacks[0].Cards = append(stacks[0].Cards, ace)
+	stacks[1].Cards = append(stacks[1].Cards, ace)
+	for _, r := range "ab" {
+		cm := move{from: -1, to: -1}
+		cm, _ = processKey(key(r), stacks, &deck, cm)
+		cm, _ = processKey(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone), stacks, &deck, cm)
+		moveCards(stacks, cm)
+	}
+	if len(stacks[firstAce()].Cards) != 1 || len(stacks[firstAce()+1].Cards) != 1 {
+		t.Errorf("expected both aces of spades on their own foundations")
+	}
+}
+
+func TestMoveOne(t *testing.T) {
+	deck := generic.NewDecks(2)
+	stacks := deal(&deck)
+	run := []generic.Card{
+		generic.NewCard("8", "H", "red", 8, 8, true),
+		generic.NewCard("7", "H", "red", 7, 8, true),
+	}
+	stacks[0].Cards = append([]generic.Card(nil), run...)
+	stacks[1].Cards = []generic.Card{generic.NewCard("9", "H", "red", 9, 8, true)}
+	moves = 0
+	moveCards(stacks, move{from: 0, to: 1})
+	if len(stacks[1].Cards) != 1 {
+		t.Errorf("expected only one card to move in forty thieves")
+	}
+	stacks[2].Cards = nil
+	moveCards(stacks, move{from: 0, to: 2})
+	if len(stacks[2].Cards) != 1 || stacks[2].Cards[0].Rank != "7" {
+		t.Errorf("expected the 7H to move to the empty column")
+	}
+	variant = "josephine"
+	defer func() { variant = "thieves" }()
+	stacks[0].Cards = append([]generic.Card(nil), run...)
+	moveCards(stacks, move{from: 0, to: 1})
+	if len(stacks[1].Cards) != 3 || len(stacks[0].Cards) != 0 {
+		t.Errorf("expected the run to move in josephine but was %d", len(stacks[1].Cards))
+	}
+	if moves != 2 {
+		t.Errorf("Expected the 7H and the run to be 2 moves but was %d", moves)
+	}
+}
+
+func TestStock(t *testing.T) {
+	deck := generic.NewDecks(2)
+	stacks := deal(&deck)
+	cm := move{from: -1, to: -1}
+	for i := 0; i < cardCount; i++ {
+		cm, _ = processKey(key(' '), stacks, &deck, cm)
+	}
+	if len(stacks[waste()].Cards) != cardCount-40 {
+		t.Errorf("expected a single pass through the stock but was %d", len(stacks[waste()].Cards))
+	}
+	if _, ok := processKey(key('q'), stacks, &deck, cm); ok {
+		t.Errorf("expected q to quit")
+	}
+}
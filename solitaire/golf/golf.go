@@ -0,0 +1,280 @@
+// This program allows a user to play the game golf
+// It uses the ncurses tcell created by Garrett D'Amore which can be gotten by
+// go get -u github.com/gdamore/tcell
+//
+// 35 cards are dealt face up into seven columns of five.  The top card of any column can be
+// played onto the waste if it is one rank above or below the top of the waste.  When nothing
+// can be played a card is dealt from the stock.  The score is the number of cards left in the
+// columns so like real golf the lower the better.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gdamore/tcell"
+	"github.com/tmasterson/cardgames/display"
+	"github.com/tmasterson/cardgames/generic"
+	"github.com/tmasterson/cardgames/solitaire"
+	"github.com/tmasterson/cardgames/stats"
+)
+
+// The number of columns and how many cards are in each.
+const (
+	columns = 7
+	depth   = 5
+)
+
+// Game holds everything about a game of golf.
+// The stock is only gone through once.
+type game struct {
+	columns []solitaire.Pile
+	waste   solitaire.Pile
+	deck    generic.Deck
+	moves   int
+	msg     string
+}
+
+// Define the boxes for easier manipulation
+var playArea, wasteArea display.Box
+
+// NewGame deals the columns and turns the first card of the stock onto the waste.
+// wrap: Allows kings and aces to be played on each other
+func newGame(deck generic.Deck, wrap bool) *game {
+	// Make aces low card instead of high card
+	for i := range deck.Cards {
+		if deck.Cards[i].Rvalue == 14 {
+			deck.Cards[i].Rvalue = 1
+		}
+	}
+	g := &game{deck: deck}
+	g.columns = make([]solitaire.Pile, columns)
+	for i := range g.columns {
+		g.columns[i].Cards = g.deck.Deal(depth, depth)
+		g.columns[i].Ptype = 'T'
+	}
+	g.waste.Ptype = 'G'
+	g.waste.Wrap = wrap
+	solitaire.DealToWaste(&g.waste, &g.deck, 1, 0)
+	return g
+}
+
+// Play moves the top card of a column onto the waste.
+func (g *game) play(col int) error {
+	from := &g.columns[col]
+	if len(from.Cards) == 0 {
+		return fmt.Errorf("column %c is empty", 'A'+col)
+	}
+	index := len(from.Cards) - 1
+	if !from.CheckMove(&g.waste, index) {
+		c := from.Cards[index]
+		return fmt.Errorf("%s can not be played on the waste", c.Rank+c.Suit)
+	}
+	from.DoMove(&g.waste, index)
+	g.waste.Firstfaceup = len(g.waste.Cards) - 1
+	g.moves++
+	return nil
+}
+
+// Deal turns the next card of the stock onto the waste.
+func (g *game) deal() error {
+	if g.deck.AllDealt {
+		return errors.New("the stock is empty")
+	}
+	solitaire.DealToWaste(&g.waste, &g.deck, 1, 0)
+	g.moves++
+	return nil
+}
+
+// Left returns the number of cards still in the columns.
+func (g *game) left() int {
+	n := 0
+	for _, p := range g.columns {
+		n += len(p.Cards)
+	}
+	return n
+}
+
+// HasMove tells if a card can be played or dealt.
+func (g *game) hasMove() bool {
+	if !g.deck.AllDealt {
+		return true
+	}
+	for i := range g.columns {
+		p := &g.columns[i]
+		if len(p.Cards) > 0 && p.CheckMove(&g.waste, len(p.Cards)-1) {
+			return true
+		}
+	}
+	return false
+}
+
+// DrawScreen draws the screen putting all the boxes in place
+// s: The screen variable
+// style: The style for the screen
+//
+// Returns: Returns an error if one occurs otherwise nil
+func drawScreen(s tcell.Screen, style tcell.Style) error {
+	w, h := s.Size()
+	if w < 80 || h < 25 {
+		return errors.New("Screen size must be at least 80 by 25")
+	}
+	title := "Golf"
+	display.PutString(s, w/2-len(title)/2, 0, style, title)
+	var err error
+	wasteArea, err = display.MakeBox(s, "Waste", 0, 2, 10, 4, style)
+	if err != nil {
+		return err
+	}
+	playArea, err = display.MakeBox(s, "Columns", 0, wasteArea.BotY+2, 30, wasteArea.BotY+depth+5, style)
+	if err != nil {
+		return err
+	}
+	x := playArea.RightX + 5
+	y := playArea.TopY
+	help := []string{
+		"A to G plays the top card of a column",
+		"   onto the waste.",
+		"Space deals a card from the stock.",
+		"Q quits.",
+		"",
+		"Cards are played one rank above or",
+		"below the top of the waste.",
+	}
+	for _, line := range help {
+		display.PutString(s, x, y, style, line)
+		y++
+	}
+	for i := 0; i < columns; i++ {
+		display.PutString(s, playArea.LeftX+i*4+2, playArea.TopY+1, style, string(rune('A'+i)))
+	}
+	s.Show()
+	return nil
+}
+
+// ShowGame prints the columns, the waste and the status line.
+// s: Screen variable
+// g: The game
+// style: The style for the cards
+func showGame(s tcell.Screen, g *game, style tcell.Style) {
+	w, h := s.Size()
+	for i, p := range g.columns {
+		for k := 0; k < depth; k++ {
+			str := "  "
+			if k < len(p.Cards) {
+				str = p.Cards[k].Rank + p.Cards[k].Suit
+			}
+			display.PutString(s, playArea.LeftX+i*4+2, playArea.TopY+2+k, style, str)
+		}
+	}
+	top := g.waste.Cards[len(g.waste.Cards)-1]
+	display.PutString(s, wasteArea.CardArea, wasteArea.TopY+1, style, top.Rank+top.Suit)
+	display.PutString(s, 0, h-2, style, strings.Repeat(" ", w-1))
+	display.PutString(s, 0, h-2, style, g.msg)
+	display.PutString(s, 0, h-1, style, strings.Repeat(" ", w-1))
+	display.PutString(s, 0, h-1, style, fmt.Sprintf("Stock# %02d, Cards left# %02d", len(g.deck.Cards)-g.deck.LastDealt, g.left()))
+	s.Show()
+}
+
+// ProcessKey handles the processing of key strokes
+//
+// ev:  The event that that contains the key.
+// g:  The game the key acts on.
+//
+// returns: false if the player wants to quit
+func processKey(ev *tcell.EventKey, g *game) bool {
+	if ev.Key() != tcell.KeyRune {
+		return true
+	}
+	var err error
+	g.msg = ""
+	r := unicode.ToUpper(ev.Rune())
+	switch {
+	case r == 'Q':
+		return false
+	case r == ' ':
+		err = g.deal()
+	case r >= 'A' && r < 'A'+columns:
+		err = g.play(int(r - 'A'))
+	}
+	if err != nil {
+		g.msg = err.Error() + "."
+	}
+	return true
+}
+
+// PlayGame is the main function that handles all aspects of the game.
+//
+// s: Screen variable.
+// g: The game to play.
+// style: The style for the screen.
+//
+// returns: The cards left in the columns.
+func playGame(s tcell.Screen, g *game, style tcell.Style) int {
+	for {
+		if g.left() == 0 {
+			return 0
+		}
+		if !g.hasMove() {
+			g.msg = "There are no moves left. Press any key."
+			showGame(s, g, style)
+			s.PollEvent()
+			return g.left()
+		}
+		showGame(s, g, style)
+		ev := s.PollEvent()
+		switch ev := ev.(type) {
+		case *tcell.EventKey:
+			if ev.Key() == tcell.KeyCtrlL {
+				s.Sync()
+			} else if !processKey(ev, g) {
+				return g.left()
+			}
+		}
+	}
+}
+
+func main() {
+	wrap := flag.Bool("wrap", false, "Allow kings and aces to be played on each other")
+	var daily stats.Daily
+	daily.Flags(flag.CommandLine)
+	flag.Parse()
+	deck := generic.NewDeck()
+	deck.Source = daily.Source("golf", fmt.Sprintf("wrap %v", *wrap), time.Now())
+	deck.Shuffle()
+	g := newGame(deck, *wrap)
+	tcell.SetEncodingFallback(tcell.EncodingFallbackASCII)
+	s, e := tcell.NewScreen()
+	if e != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", e)
+		os.Exit(1)
+	}
+	if e = s.Init(); e != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", e)
+		os.Exit(1)
+	}
+	s.Clear()
+	s.HideCursor()
+	if err := drawScreen(s, tcell.StyleDefault); err != nil {
+		s.Fini()
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	start := time.Now()
+	left := playGame(s, g, tcell.StyleDefault)
+	s.Fini()
+	if left == 0 {
+		fmt.Println("Congratulations you cleared the columns!")
+	} else {
+		fmt.Printf("You finished with %d cards left.\n", left)
+	}
+	// the leaderboard puts higher scores first so each card left counts against the player
+	if err := daily.Record(os.Stdout, stats.Result{Game: "golf", Won: left == 0, Moves: g.moves, Score: -left, Elapsed: time.Since(start), Finished: time.Now()}); err != nil {
+		fmt.Fprintf(os.Stderr, "The result could not be saved: %v\n", err)
+	}
+}
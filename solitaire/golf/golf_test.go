@@ -0,0 +1,53 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell"
+	"github.com/tmasterson/cardgames/generic"
+)
+
+func key(r rune) *tcell.EventKey {
+	return tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone)
+}
+
+func TestNewGame(t *testing.T) {
+	g := newGame(generic.NewDeck(), false)
+	if g.left() != 35 || len(g.waste.Cards) != 1 || g.deck.LastDealt != 36 {
+		t.Errorf("Expected 35 cards in the columns and 1 on the waste but was %d %d", g.left(), len(g.waste.Cards))
+	}
+	if g.waste.Ptype != 'G' || g.waste.Wrap {
+		t.Errorf("Expected a golf waste without wrap")
+	}
+}
+
+func TestPlay(t *testing.T) {
+	g := newGame(generic.NewDeck(), false)
+	g.waste.Cards[0] = generic.NewCard("5", "H", "red", 5, 8, true)
+	g.columns[0].Cards[depth-1] = generic.NewCard("6", "S", "black", 6, 16, true)
+	g.columns[1].Cards[depth-1] = generic.NewCard("9", "S", "black", 9, 16, true)
+	processKey(key('b'), g)
+	if g.msg == "" || len(g.columns[1].Cards) != depth {
+		t.Errorf("Expected 9S to be refused")
+	}
+	processKey(key('a'), g)
+	if g.msg != "" || len(g.columns[0].Cards) != depth-1 || g.left() != 34 {
+		t.Errorf("Expected 6S to be played but was %q", g.msg)
+	}
+	processKey(key(' '), g)
+	if len(g.waste.Cards) != 3 || g.waste.Cards[1].Faceup || !g.waste.Cards[2].Faceup {
+		t.Errorf("Expected a new card face up on the waste over 6S")
+	}
+	if g.moves != 2 {
+		t.Errorf("Expected 2 moves but was %d", g.moves)
+	}
+	for !g.deck.AllDealt {
+		g.deal()
+	}
+	if g.deal() == nil {
+		t.Errorf("Expected the stock to be empty")
+	}
+	if processKey(key('q'), g) {
+		t.Errorf("Expected q to quit")
+	}
+}
@@ -0,0 +1,300 @@
+// Package engine holds the rules and state of a game of klondike apart from any way of showing it.
+// The screen and text front ends in the klondike command are clients of it, and so can be anything
+// else that wants to play or look at a game, such as a test, a solver or a server.
+//
+// The stacks are numbered 0 to 6 for the tableau, 7 for the waste and 8 to 11 for the aces of
+// spades, hearts, diamonds and clubs.  Aces are low with a rank value of 1.
+package engine
+
+import (
+	"errors"
+
+	"github.com/tmasterson/cardgames/generic"
+	"github.com/tmasterson/cardgames/solitaire"
+)
+
+// The stacks in a game.
+const (
+	Columns    = 7
+	Waste      = 7
+	FirstAce   = 8
+	StackCount = 12
+)
+
+// The errors returned by Apply.
+var (
+	ErrIllegal  = errors.New("that move is not allowed")
+	ErrNoPasses = errors.New("there are no passes left")
+)
+
+// Options are the choices made when a game is dealt.
+// Draw: The number of cards dealt to the waste at a time, 1 to 3
+// Passes: The number of times the deck can be gone through, 0 for no limit
+// Build: The Ptype of the tableau, 'T' for alternate colors, 'S' for by suit and 'M' for the same color
+// as in whitehead, which deals every card face up
+// Source: The source used to shuffle, nil uses the default
+type Options struct {
+	Draw   int            `json:"draw"`
+	Passes int            `json:"passes"`
+	Build  rune           `json:"build"`
+	Source generic.Source `json:"-"`
+}
+
+// DefaultOptions are three card, three pass klondike built down in alternate colors.
+var DefaultOptions = Options{Draw: 3, Passes: 3, Build: 'T'}
+
+// Move is a move in a game.
+// From, To: The stacks to move from and to
+// Count: The number of cards to move from the top of a tableau stack, 0 moves all the face up cards.
+// Only the top card moves from the waste and to the aces.
+// Deal: Deal from the stock to the waste instead of moving cards
+type Move struct {
+	From  int  `json:"from"`
+	To    int  `json:"to"`
+	Count int  `json:"count,omitempty"`
+	Deal  bool `json:"deal,omitempty"`
+}
+
+// Game is a game of klondike.
+type Game struct {
+	opts   Options
+	stacks []solitaire.Pile
+	deck   generic.Deck
+	pass   int
+	moves  int
+}
+
+// View is a copy of a game for showing it.  Changing it does not change the game.
+// Stacks: The stacks as numbered above
+// Stock: The cards left to deal in the order they will be dealt
+// Pass: How many times the deck has been gone through
+// Moves: The number of moves made including deals
+type View struct {
+	Options
+	Stacks []solitaire.Pile `json:"stacks"`
+	Stock  []generic.Card   `json:"stock"`
+	Pass   int              `json:"pass"`
+	Moves  int              `json:"moves"`
+}
+
+// NewGame shuffles a deck and deals a new game.
+func NewGame(o Options) *Game {
+	deck := generic.NewDeck()
+	deck.Source = o.Source
+	deck.Shuffle()
+	// Make aces low card instead of high card
+	for i := range deck.Cards {
+		if deck.Cards[i].Rvalue == 14 {
+			deck.Cards[i].Rvalue = 1
+		}
+	}
+	stacks := make([]solitaire.Pile, StackCount)
+	for i := 0; i < Columns; i++ {
+		up := 1
+		if o.Build == 'M' {
+			up = i + 1
+		}
+		stacks[i].Cards = deck.Deal(i+1, up)
+		stacks[i].Firstfaceup = len(stacks[i].Cards) - up
+	}
+	stacks[Waste].Cards = deck.Deal(o.Draw, 1)
+	stacks[Waste].Firstfaceup = len(stacks[Waste].Cards) - 1
+	return NewGameFrom(o, stacks, deck)
+}
+
+// NewGameFrom starts a game from stacks and a stock already laid out, such as a position being
+// studied or tested.  The Ptypes of the stacks are set from the options.
+func NewGameFrom(o Options, stacks []solitaire.Pile, deck generic.Deck) *Game {
+	if o.Build == 0 {
+		o.Build = 'T'
+	}
+	for i := range stacks {
+		switch {
+		case i < Columns:
+			stacks[i].Ptype = o.Build
+		case i == Waste:
+			stacks[i].Ptype = 'W'
+		default:
+			stacks[i].Ptype = 'A'
+		}
+	}
+	return &Game{opts: o, stacks: stacks, deck: deck}
+}
+
+// aceStacks gives the stack for the aces of each suit.
+var aceStacks = map[string]int{"S": 8, "H": 9, "D": 10, "C": 11}
+
+// Restore starts a game again from a view of it, such as one saved to a file.
+func Restore(v View) *Game {
+	deck := generic.Deck{Cards: append([]generic.Card(nil), v.Stock...)}
+	deck.AllDealt = len(deck.Cards) == 0
+	g := NewGameFrom(v.Options, copyStacks(v.Stacks), deck)
+	g.pass = v.Pass
+	g.moves = v.Moves
+	return g
+}
+
+// AceStack returns the ace stack for a suit.
+func AceStack(suit string) int {
+	return aceStacks[suit]
+}
+
+// View returns a copy of the game.
+func (g *Game) View() View {
+	v := View{Options: g.opts, Pass: g.pass, Moves: g.moves}
+	v.Stacks = copyStacks(g.stacks)
+	if !g.deck.AllDealt {
+		v.Stock = append([]generic.Card(nil), g.deck.Cards[g.deck.LastDealt:]...)
+	}
+	return v
+}
+
+// CopyStacks returns a copy of the stacks that shares no cards with them.
+func copyStacks(stacks []solitaire.Pile) []solitaire.Pile {
+	c := append([]solitaire.Pile(nil), stacks...)
+	for i := range c {
+		c[i].Cards = append([]generic.Card(nil), stacks[i].Cards...)
+	}
+	return c
+}
+
+// Over tells if every pass through the deck has been used.
+func (g *Game) Over() bool {
+	return g.opts.Passes > 0 && g.pass >= g.opts.Passes
+}
+
+// IsWon tells if all the cards are on the aces.
+func (g *Game) IsWon() bool {
+	total := 0
+	for i := FirstAce; i < StackCount; i++ {
+		total += len(g.stacks[i].Cards)
+	}
+	return total == 52
+}
+
+// index returns where the cards to move start in the from stack or -1 if there are none.
+func (g *Game) index(m Move) int {
+	from := g.stacks[m.From]
+	n := len(from.Cards)
+	switch {
+	case n == 0:
+		return -1
+	case m.From == Waste || m.To >= FirstAce:
+		return n - 1
+	case m.Count < 1:
+		return from.Firstfaceup
+	case m.Count > n:
+		return -1
+	}
+	return n - m.Count
+}
+
+// Legal tells if a move can be made.  A card only goes to the ace stack of its suit and a deal
+// needs cards in the stock or waste.
+func (g *Game) Legal(m Move) bool {
+	if m.Deal {
+		return !g.Over() && (!g.deck.AllDealt || len(g.stacks[Waste].Cards) > 0)
+	}
+	if m.From < 0 || m.From > Waste || m.To < 0 || m.To >= StackCount || m.To == Waste || m.From == m.To {
+		return false
+	}
+	i := g.index(m)
+	if i < 0 {
+		return false
+	}
+	from := &g.stacks[m.From]
+	if m.To >= FirstAce && m.To != AceStack(from.Cards[i].Suit) {
+		return false
+	}
+	return from.CheckMove(&g.stacks[m.To], i)
+}
+
+// Apply makes a move.
+//
+// returns: ErrNoPasses for a deal once the passes are used, or ErrIllegal for any other move that
+// can not be made, in which case the game is unchanged.
+func (g *Game) Apply(m Move) error {
+	if m.Deal {
+		if g.Over() {
+			return ErrNoPasses
+		}
+		if !g.Legal(m) {
+			return ErrIllegal
+		}
+		g.pass = solitaire.DealToWaste(&g.stacks[Waste], &g.deck, g.opts.Draw, g.pass)
+		g.moves++
+		return nil
+	}
+	if !g.Legal(m) {
+		return ErrIllegal
+	}
+	g.stacks[m.From].DoMove(&g.stacks[m.To], g.index(m))
+	g.moves++
+	return nil
+}
+
+// LegalMoves returns every move that can be made, the deal last when there is anything to deal.
+// Tableau moves are given with the count of cards that move.
+func (g *Game) LegalMoves() []Move {
+	var moves []Move
+	for from := 0; from <= Waste; from++ {
+		p := g.stacks[from]
+		for to := 0; to < StackCount; to++ {
+			if to == from || to == Waste {
+				continue
+			}
+			if from == Waste || to >= FirstAce {
+				if m := (Move{From: from, To: to}); g.Legal(m) {
+					moves = append(moves, m)
+				}
+				continue
+			}
+			for i := p.Firstfaceup; i < len(p.Cards); i++ {
+				if m := (Move{From: from, To: to, Count: len(p.Cards) - i}); g.Legal(m) {
+					moves = append(moves, m)
+				}
+			}
+		}
+	}
+	if m := (Move{Deal: true}); g.Legal(m) {
+		moves = append(moves, m)
+	}
+	return moves
+}
+
+// AutoMove finds a card on top of the waste or tableau that can go to its ace stack.
+// With safe only cards that can not be needed in the tableau are found.
+//
+// returns: The move and true if one was found.
+func (g *Game) AutoMove(safe bool) (Move, bool) {
+	for i := 0; i <= Waste; i++ {
+		p := &g.stacks[i]
+		if len(p.Cards) == 0 {
+			continue
+		}
+		c := p.Cards[len(p.Cards)-1]
+		m := Move{From: i, To: AceStack(c.Suit), Count: 1}
+		if !c.Faceup || !g.Legal(m) {
+			continue
+		}
+		if safe && !solitaire.Safe(g.stacks, c, g.opts.Build) {
+			continue
+		}
+		return m, true
+	}
+	return Move{From: -1, To: -1}, false
+}
+
+// CanFinish tells if the game can be finished for the player.
+// That is when the stock and waste are empty and every tableau card is face up.
+func (g *Game) CanFinish() bool {
+	if !g.deck.AllDealt || len(g.stacks[Waste].Cards) > 0 {
+		return false
+	}
+	for i := 0; i < Columns; i++ {
+		if g.stacks[i].Firstfaceup > 0 {
+			return false
+		}
+	}
+	return true
+}
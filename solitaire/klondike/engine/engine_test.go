@@ -0,0 +1,252 @@
+package engine
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/tmasterson/cardgames/generic"
+	"github.com/tmasterson/cardgames/solitaire"
+)
+
+// spades returns a game with one face up card in each tableau stack and nothing else.
+func spades() *Game {
+	stacks := make([]solitaire.Pile, StackCount)
+	stacks[0].Cards = []generic.Card{generic.NewCard("Q", "S", "black", 12, 16, true)}
+	stacks[1].Cards = []generic.Card{generic.NewCard("4", "S", "black", 4, 16, true)}
+	stacks[2].Cards = []generic.Card{generic.NewCard("T", "S", "black", 10, 16, true)}
+	stacks[3].Cards = []generic.Card{generic.NewCard("8", "S", "black", 8, 16, true)}
+	stacks[4].Cards = []generic.Card{generic.NewCard("9", "S", "black", 9, 16, true)}
+	stacks[5].Cards = []generic.Card{generic.NewCard("7", "S", "black", 7, 16, true)}
+	stacks[6].Cards = []generic.Card{generic.NewCard("3", "D", "red", 3, 4, true)}
+	deck := generic.NewDeck()
+	deck.Deal(52, 0)
+	return NewGameFrom(DefaultOptions, stacks, deck)
+}
+
+func TestNewGame(t *testing.T) {
+	g := NewGame(DefaultOptions)
+	v := g.View()
+	for i := 0; i < Columns; i++ {
+		p := v.Stacks[i]
+		if len(p.Cards) != i+1 || p.Firstfaceup != i || !p.Cards[i].Faceup || p.Ptype != 'T' {
+			t.Errorf("Expected stack %d to have %d cards with the top face up", i, i+1)
+		}
+	}
+	if len(v.Stacks[Waste].Cards) != 3 || len(v.Stock) != 21 {
+		t.Errorf("Expected 3 on the waste and 21 in the stock but was %d and %d", len(v.Stacks[Waste].Cards), len(v.Stock))
+	}
+	for _, c := range v.Stock {
+		if c.Rank == "A" && c.Rvalue != 1 {
+			t.Errorf("Expected aces to be low")
+		}
+	}
+	if g.IsWon() || g.Over() || v.Pass != 0 || v.Moves != 0 {
+		t.Errorf("Expected a new game to be starting")
+	}
+}
+
+func TestNewGameWhitehead(t *testing.T) {
+	v := NewGame(Options{Draw: 1, Passes: 1, Build: 'M'}).View()
+	for i := 0; i < Columns; i++ {
+		p := v.Stacks[i]
+		if len(p.Cards) != i+1 || p.Firstfaceup != 0 || !p.Cards[0].Faceup || p.Ptype != 'M' {
+			t.Errorf("Expected stack %d to have %d cards all face up but was %+v", i, i+1, p)
+		}
+	}
+}
+
+func TestView(t *testing.T) {
+	g := spades()
+	v := g.View()
+	v.Stacks[0].Cards[0].Rank = "K"
+	v.Stacks[1].Cards = nil
+	if g.View().Stacks[0].Cards[0].Rank != "Q" || len(g.View().Stacks[1].Cards) != 1 {
+		t.Errorf("Expected changing a view not to change the game")
+	}
+}
+
+func TestApplyDeal(t *testing.T) {
+	deck := generic.NewDeck()
+	stacks := make([]solitaire.Pile, StackCount)
+	stacks[Waste].Cards = deck.Deal(3, 1)
+	stacks[Waste].Firstfaceup = 2
+	g := NewGameFrom(Options{Draw: 3, Passes: 1}, stacks, deck)
+	if err := g.Apply(Move{Deal: true}); err != nil || len(g.View().Stacks[Waste].Cards) != 6 {
+		t.Errorf("Should have 6 cards on the waste but have %d", len(g.View().Stacks[Waste].Cards))
+	}
+	g.deck.AllDealt = true
+	if err := g.Apply(Move{Deal: true}); err != nil {
+		t.Errorf("Expected the waste turned over but was %v", err)
+	}
+	v := g.View()
+	if len(v.Stacks[Waste].Cards) != 3 || v.Pass != 1 || len(v.Stock) != 3 || v.Moves != 2 {
+		t.Errorf("Expected pass 1 with 3 on the waste and 3 in the stock but was %d, %d, %d", v.Pass, len(v.Stacks[Waste].Cards), len(v.Stock))
+	}
+	if !g.Over() {
+		t.Errorf("Expected the game over after its only pass")
+	}
+	if err := g.Apply(Move{Deal: true}); err != ErrNoPasses {
+		t.Errorf("Expected no passes left but was %v", err)
+	}
+}
+
+func TestApply(t *testing.T) {
+	g := spades()
+	for _, m := range []Move{{From: 1, To: 1}, {From: 1, To: -1}, {From: 8, To: 1}, {From: 1, To: Waste}, {From: 0, To: 1}} {
+		if err := g.Apply(m); err != ErrIllegal {
+			t.Errorf("Expected %v to be illegal but was %v", m, err)
+		}
+	}
+	if g.View().Moves != 0 {
+		t.Errorf("Expected illegal moves not to be counted")
+	}
+	if err := g.Apply(Move{From: 6, To: 1}); err != nil {
+		t.Errorf("Expected the 3D on the 4S but was %v", err)
+	}
+	stacks := g.stacks
+	if len(stacks[1].Cards) != 2 || len(stacks[6].Cards) != 0 {
+		t.Errorf("Expected 2, 0 but was %d, %d", len(stacks[1].Cards), len(stacks[6].Cards))
+	}
+	stacks[6].Cards = []generic.Card{
+		generic.NewCard("J", "D", "red", 11, 4, false),
+		generic.NewCard("9", "C", "black", 9, 2, false),
+		generic.NewCard("7", "C", "black", 7, 2, true),
+		generic.NewCard("6", "D", "red", 6, 4, true),
+		generic.NewCard("5", "S", "black", 5, 16, true),
+	}
+	stacks[6].Firstfaceup = 2
+	if err := g.Apply(Move{From: 6, To: 5, Count: 2}); err != nil {
+		t.Errorf("Expected the 6D and 5S on the 7S but was %v", err)
+	}
+	if len(stacks[5].Cards) != 3 || len(stacks[6].Cards) != 3 || !stacks[6].Cards[2].Faceup {
+		t.Errorf("Expected 3, 3 with the 7C face up but was %d, %d", len(stacks[5].Cards), len(stacks[6].Cards))
+	}
+	if err := g.Apply(Move{From: 6, To: 4, Count: 9}); err != ErrIllegal {
+		t.Errorf("Expected too many cards to be illegal but was %v", err)
+	}
+	stacks[4].Cards = append(stacks[4].Cards, generic.NewCard("8", "D", "red", 8, 4, true))
+	stacks[4].Firstfaceup = 1
+	if err := g.Apply(Move{From: 6, To: 4, Count: 1}); err != nil {
+		t.Errorf("Expected the 7C on the 8D but was %v", err)
+	}
+	if len(stacks[4].Cards) != 3 || len(stacks[6].Cards) != 2 || !stacks[6].Cards[1].Faceup {
+		t.Errorf("Expected 3, 2 with the 9C turned up but was %d, %d", len(stacks[4].Cards), len(stacks[6].Cards))
+	}
+	if g.View().Moves != 3 {
+		t.Errorf("Expected 3 moves but was %d", g.View().Moves)
+	}
+}
+
+func TestLegalMoves(t *testing.T) {
+	g := spades()
+	moves := g.LegalMoves()
+	want := []Move{{From: 6, To: 1, Count: 1}}
+	if len(moves) != len(want) {
+		t.Fatalf("Expected %v but was %v", want, moves)
+	}
+	for i := range want {
+		if moves[i] != want[i] {
+			t.Errorf("Expected %v but was %v", want[i], moves[i])
+		}
+	}
+	for _, m := range moves {
+		if !g.Legal(m) {
+			t.Errorf("Expected %v to be legal", m)
+		}
+	}
+	if err := g.Apply(Move{Deal: true}); err != ErrIllegal || g.View().Pass != 0 {
+		t.Errorf("Expected no deal with the stock and waste empty but was %v", err)
+	}
+	g.deck = generic.NewDeck()
+	if moves := g.LegalMoves(); moves[len(moves)-1] != (Move{Deal: true}) {
+		t.Errorf("Expected the deal last with cards in the stock but was %v", moves)
+	}
+}
+
+func TestAutoMove(t *testing.T) {
+	stacks := make([]solitaire.Pile, StackCount)
+	g := NewGameFrom(DefaultOptions, stacks, generic.NewDeck())
+	if _, ok := g.AutoMove(true); ok {
+		t.Errorf("Expected no move with empty stacks")
+	}
+	stacks[8].Cards = []generic.Card{generic.NewCard("A", "S", "black", 1, 16, true), generic.NewCard("2", "S", "black", 2, 16, true)}
+	stacks[2].Cards = []generic.Card{generic.NewCard("3", "S", "black", 3, 16, true)}
+	stacks[5].Cards = []generic.Card{generic.NewCard("A", "H", "red", 1, 8, true)}
+	m, ok := g.AutoMove(true)
+	if !ok || m.From != 5 || m.To != 9 {
+		t.Errorf("Expected the AH to move to stack 9 but was %v", m)
+	}
+	for to := FirstAce; to < StackCount; to++ {
+		if to != 9 && g.Legal(Move{From: 5, To: to}) {
+			t.Errorf("Expected the AH not to go on stack %d", to)
+		}
+	}
+	g.Apply(m)
+	if _, ok := g.AutoMove(true); ok {
+		t.Errorf("Expected 3S not to be safe with no red twos on the aces")
+	}
+	m, ok = g.AutoMove(false)
+	if !ok || m.From != 2 || m.To != 8 {
+		t.Errorf("Expected the 3S to move to stack 8 but was %v", m)
+	}
+}
+
+func TestCanFinish(t *testing.T) {
+	deck := generic.NewDeck()
+	stacks := make([]solitaire.Pile, StackCount)
+	stacks[0].Cards = deck.Deal(2, 1)
+	stacks[0].Firstfaceup = 1
+	g := NewGameFrom(DefaultOptions, stacks, deck)
+	if g.CanFinish() {
+		t.Errorf("Expected not to finish with cards in the deck")
+	}
+	g.deck.Deal(50, 0)
+	if g.CanFinish() {
+		t.Errorf("Expected not to finish with a face down card")
+	}
+	stacks[0].Firstfaceup = 0
+	if !g.CanFinish() {
+		t.Errorf("Expected to finish")
+	}
+	stacks[Waste].Cards = deck.Cards[:1]
+	if g.CanFinish() {
+		t.Errorf("Expected not to finish with cards on the waste")
+	}
+}
+
+func TestIsWon(t *testing.T) {
+	deck := generic.NewDeck()
+	stacks := make([]solitaire.Pile, StackCount)
+	for i := FirstAce; i < StackCount; i++ {
+		stacks[i].Cards = deck.Deal(13, 13)
+	}
+	g := NewGameFrom(DefaultOptions, stacks, deck)
+	if !g.IsWon() {
+		t.Errorf("Expected the game won with every card on the aces")
+	}
+}
+
+func TestRestore(t *testing.T) {
+	g := NewGame(Options{Draw: 1, Passes: 0, Build: 'S'})
+	for i := 0; i < 30; i++ {
+		g.Apply(Move{Deal: true})
+	}
+	data, err := json.Marshal(g.View())
+	if err != nil {
+		t.Fatalf("Expected the view to marshal but was %v", err)
+	}
+	var v View
+	if err := json.Unmarshal(data, &v); err != nil {
+		t.Fatalf("Expected the view to unmarshal but was %v", err)
+	}
+	r := Restore(v)
+	if !reflect.DeepEqual(r.View(), g.View()) {
+		t.Errorf("Expected the restored game to look the same")
+	}
+	g.Apply(Move{Deal: true})
+	r.Apply(Move{Deal: true})
+	if !reflect.DeepEqual(r.View(), g.View()) || r.View().Pass != 1 {
+		t.Errorf("Expected the restored game to deal the same and turn the waste over")
+	}
+}
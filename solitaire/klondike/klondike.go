@@ -2,7 +2,11 @@
 // It uses the ncurses tcell created by Garrett D'Amore which can be gotten by
 // go get -u github.com/gdamore/tcell
 //
-// This is the 3 pass version of klondike in which you can go through the deck 3 times.
+// By default this is the 3 pass version of klondike in which 3 cards are dealt at a time and you can go
+// through the deck 3 times.  The number of cards dealt, the number of passes and how the tableau is built
+// can all be changed with flags.  Thoughtful mode shows every face down card.
+//
+// The rules and the state of a game are in the engine package, this is the screen and text front ends for it.
 package main
 
 import (
@@ -11,34 +15,29 @@ import (
 	"fmt"
 	//"log"
 	"os"
+	"path/filepath"
 	"strings"
-	"unicode"
+	"time"
 
 	"github.com/gdamore/tcell"
-	"github.com/mattn/go-runewidth"
+	"github.com/tmasterson/cardgames/display"
 	"github.com/tmasterson/cardgames/generic"
 	"github.com/tmasterson/cardgames/solitaire"
+	"github.com/tmasterson/cardgames/solitaire/klondike/engine"
+	"github.com/tmasterson/cardgames/solitaire/klondike/solver"
+	"github.com/tmasterson/cardgames/stats"
 )
 
-// Box defines the coordinates of a box and the area where cards will
-// be printed on the single card groups i.e waste and aces.
-type box struct {
-	title                     string
-	leftX, rightX, topY, botY int
-	cardArea                  int
-	style                     tcell.Style
-}
-
-// Move is a structure to track moves.
+// Move is a structure to track a move as its keys are typed.
 // from:  Stack to mover cards from
 // to:  Stack to move cards to
-// pass:  Number of passes through the waste stack
 // howmany:  Number of cards to move
+// quit:  The player wants to stop
 type move struct {
 	from    int
 	to      int
-	pass    int
 	howmany int
+	quit    bool
 }
 
 // This variable sets up logging to the file game.out which will show each move and is automatically truncated for each run.
@@ -48,63 +47,51 @@ type move struct {
 //)
 
 // Define the boxes for easier manipulation
-var wasteArea, ace1, ace2, ace3, ace4, playArea box
-var vcount int
+var wasteArea, ace1, ace2, ace3, ace4, playArea display.Box
 
-// Print a string in a given area of the screen in a given style
-// s: The screen variable
-// x, y: Cooridinates of the left end of the string.
-// style: The string style.
-// str: The string to be printed.
-func putString(s tcell.Screen, x, y int, style tcell.Style, str string) {
-	for _, c := range str {
-		w := runewidth.RuneWidth(c)
-		s.SetContent(x, y, c, nil, style)
-		x += w
-	}
+// Vcount is the number of cards dealt to the waste at a time and passes is how many times
+// the deck can be gone through, 0 for no limit.
+var vcount = 3
+var passes = 3
+
+// Build is the Ptype of the tableau, 'T' for alternate colors, 'S' for by suit and 'M' for
+// whitehead, built in the same color with every card dealt face up.  Thoughtful shows the face
+// down cards.
+var build = 'T'
+var thoughtful bool
+
+// Options returns the engine options for the flags given.
+func options() engine.Options {
+	return engine.Options{Draw: vcount, Passes: passes, Build: build, Source: shuffleSource}
 }
 
-// Makebox draws a box on the screen.
-// s: The screen variable
-// title: A string that defines a title for the box.  Can be blank or null
-// leftX, topY, rightX, botY: The coordinates for the corners of the box
-// style: The style for the box
-//
-// If this were to be made more generic it should be set so that you can do it without borders.
-func makeBox(s tcell.Screen, title string, leftX, topY, rightX, botY int, style tcell.Style) (box, error) {
-	center := (rightX - leftX) / 2
-	var b box
-	if len(title) > rightX-leftX-2 {
-		return b, fmt.Errorf("title length %d must not exceed right-left-2 %d", len(title), rightX-leftX-2)
-	}
-	b.title = title
-	b.leftX = leftX
-	b.topY = topY
-	b.rightX = rightX
-	b.botY = botY
-	b.cardArea = b.leftX + center - 1
-	b.style = style
-	// draw the box
-	for col := b.leftX; col <= b.rightX; col++ {
-		s.SetContent(col, b.topY, tcell.RuneHLine, nil, b.style)
-		s.SetContent(col, b.botY, tcell.RuneHLine, nil, b.style)
-	}
-	for row := b.topY; row <= b.botY; row++ {
-		s.SetContent(b.leftX, row, tcell.RuneVLine, nil, b.style)
-		s.SetContent(b.rightX, row, tcell.RuneVLine, nil, b.style)
-	}
-	s.SetContent(b.leftX, b.topY, tcell.RuneULCorner, nil, style)
-	s.SetContent(b.leftX, b.botY, tcell.RuneLLCorner, nil, style)
-	s.SetContent(b.rightX, b.topY, tcell.RuneURCorner, nil, style)
-	s.SetContent(b.rightX, b.botY, tcell.RuneLRCorner, nil, style)
-	titlepos := b.leftX + center - len(title)/2
-	if titlepos == b.leftX {
-		titlepos++
-	}
-	putString(s, titlepos, b.topY, style, title)
-	return b, nil
+// Autoplay moves cards to the aces whenever it is safe and anim shows the moves made for
+// the player so they can be followed.
+var autoplay bool
+var anim *display.Animator
+
+// Clock times the game, it is paused with the P key and while the terminal does not have focus.
+// Focus follows the terminal's focus reports and focusPaused is set when the clock was paused
+// for losing focus, so it starts again only then when focus comes back.
+var clock = stats.NewClock()
+var focus *display.Focus
+var focusPaused bool
+
+// Theme gives the styles for the screen, boxes, cards, cursor and status line.
+var theme = display.Themes[display.DefaultTheme]
+
+// Pending is a move made for the player waiting for its animation to finish.
+// safe:  Keep making only safe moves after it, otherwise everything that will go.
+type pending struct {
+	m    engine.Move
+	safe bool
 }
 
+// The source used to shuffle the deck, nil uses the default, and the hash of the
+// committed seed which is shown before play when the deal can be verified.
+var shuffleSource generic.Source
+var dealHash string
+
 // DrawScreen draws the screen putting all the boxes in place
 // s: The screen variable
 // style: The style for the screen
@@ -118,225 +105,280 @@ func drawScreen(s tcell.Screen, style tcell.Style) error {
 	}
 	center := w / 2
 	title := fmt.Sprintf("Klondike %d variant", vcount)
-	putString(s, center-len(title)/2, 0, style, title)
+	if thoughtful {
+		title = fmt.Sprintf("Thoughtful Klondike %d variant", vcount)
+	}
+	display.PutString(s, center-len(title)/2, 0, style, title)
+	if dealHash != "" {
+		display.PutString(s, 0, 1, style, "Deal hash "+dealHash)
+	}
 	var err error
-	wasteArea, err = makeBox(s, "Waste", 0, 2, 10, 4, style)
+	wasteArea, err = display.MakeBox(s, "Waste", 0, 2, 10, 4, style)
 	if err != nil {
 		return err
 	}
-	ace1, err = makeBox(s, "Ace", wasteArea.rightX+2, wasteArea.topY, wasteArea.rightX+8, wasteArea.topY+2, style)
+	ace1, err = display.MakeBox(s, "Ace", wasteArea.RightX+2, wasteArea.TopY, wasteArea.RightX+8, wasteArea.TopY+2, style)
 	if err != nil {
 		return err
 	}
-	ace2, err = makeBox(s, "Ace", ace1.rightX+1, wasteArea.topY, ace1.rightX+7, wasteArea.topY+2, style)
+	ace2, err = display.MakeBox(s, "Ace", ace1.RightX+1, wasteArea.TopY, ace1.RightX+7, wasteArea.TopY+2, style)
 	if err != nil {
 		return err
 	}
-	ace3, err = makeBox(s, "Ace", ace2.rightX+1, wasteArea.topY, ace2.rightX+7, wasteArea.topY+2, style)
+	ace3, err = display.MakeBox(s, "Ace", ace2.RightX+1, wasteArea.TopY, ace2.RightX+7, wasteArea.TopY+2, style)
 	if err != nil {
 		return err
 	}
-	ace4, err = makeBox(s, "Ace", ace3.rightX+1, wasteArea.topY, ace3.rightX+7, wasteArea.topY+2, style)
+	ace4, err = display.MakeBox(s, "Ace", ace3.RightX+1, wasteArea.TopY, ace3.RightX+7, wasteArea.TopY+2, style)
 	if err != nil {
 		return err
 	}
-	playArea, err = makeBox(s, "Tableau", 0, ace1.botY+2, 23, ace2.botY+16, style)
+	// face down cards take more room when they are shown
+	bot := ace2.BotY + 16
+	if thoughtful {
+		bot = h - 3
+	}
+	playArea, err = display.MakeBox(s, "Tableau", 0, ace1.BotY+2, 23, bot, style)
 	if err != nil {
 		return err
 	}
-	x := playArea.rightX + 5
-	y := playArea.topY
-	putString(s, (w-x)/2-3, y, style, "Moves:")
-	y++
-	putString(s, x, y, style, "All moves are a two character instruction.")
-	y++
-	putString(s, x, y, style, "AG will move from stack 1 to stack 7.")
-	y++
-	putString(s, x, y, style, "wA will move from waste to stack 1.")
+	x := playArea.RightX + 5
+	y := playArea.TopY
+	display.PutString(s, (w-x)/2-3, y, style, "Moves:")
 	y++
-	putString(s, x, y, style, "w<Enter will move from waste to an ace stack.")
-	y++
-	putString(s, x, y, style, "A<enter will move from stack 1 to an ace stack.")
+	// the help is made from the key bindings so it is right when they are changed
+	col1, col7, waste, ace := keys.Keys("column1"), keys.Keys("column7"), keys.Keys("waste"), keys.Keys("ace")
+	help := []string{
+		"All moves are a two key instruction.",
+		fmt.Sprintf("%s %s will move from stack 1 to stack 7.", col1, col7),
+		fmt.Sprintf("%s %s will move from waste to stack 1.", waste, col1),
+		fmt.Sprintf("%s %s will move from waste to an ace stack.", waste, ace),
+		fmt.Sprintf("%s %s will move from stack 1 to an ace stack.", col1, ace),
+		fmt.Sprintf("%s deals and %s quits.", keys.Keys("deal"), keys.Keys("quit")),
+		fmt.Sprintf("%s finishes once every card is face up.", keys.Keys("finish")),
+		fmt.Sprintf("%s pauses the clock.", keys.Keys("pause")),
+	}
+	if cur.on {
+		help = append(help,
+			fmt.Sprintf("%s %s %s %s move the cursor,", keys.Keys("left"), keys.Keys("right"), keys.Keys("up"), keys.Keys("down")),
+			fmt.Sprintf("%s picks up and drops, %s cancels.", keys.Keys("pick"), keys.Keys("cancel")))
+	}
+	for _, line := range help {
+		y++
+		display.PutString(s, x, y, style, line)
+	}
 	s.Show()
 	return nil
 }
 
+// CursorStyle returns the style for a card in cursor mode.
+// The cards under the cursor are in the theme's highlight and cards that have been picked up are underlined.
+// pile: The stack the card is in
+// fromTop: Where the card is counting from 1 for the top card
+func cursorStyle(style tcell.Style, pile, fromTop int) tcell.Style {
+	if !cur.on {
+		return style
+	}
+	if cur.held && pile == cur.from && fromTop <= cur.howmany {
+		style = style.Underline(true)
+	}
+	if pile == cur.pile && fromTop <= cur.depth {
+		style = theme.Highlight.Underline(cur.held && pile == cur.from && fromTop <= cur.howmany)
+	}
+	return style
+}
+
 // ShowStack prints the face up cards in each stack
 // s: Screen variable
 // stacks: A slice containing all the stacks or Piles of cards
-// style: The style for the cards
+// style: The style for empty places, the cards are styled by the theme
 func showStacks(s tcell.Screen, stacks []solitaire.Pile, style tcell.Style) {
 	for i, pile := range stacks {
 		switch pile.Ptype {
-		case 'T':
+		case 'T', 'S', 'M':
 			k := 1
-			for _, card := range pile.Cards {
+			for j, card := range pile.Cards {
 				if card.Faceup {
-					putString(s, playArea.leftX+i*3+2, playArea.topY+k, style, card.Rank+card.Suit)
+					display.PutString(s, playArea.LeftX+i*3+2, playArea.TopY+k, cursorStyle(theme.Card(card), i, len(pile.Cards)-j), card.Rank+card.Suit)
+					k++
+				} else if thoughtful {
+					display.PutString(s, playArea.LeftX+i*3+2, playArea.TopY+k, theme.Card(card).Dim(true), strings.ToLower(card.Rank+card.Suit))
 					k++
 				}
 			}
-			for playArea.topY+k < playArea.botY-1 {
-				putString(s, playArea.leftX+i*3+2, playArea.topY+k, style, "  ")
+			for playArea.TopY+k < playArea.BotY-1 {
+				// an empty stack shows the cursor where its first card goes
+				st := style
+				if k == 1 {
+					st = cursorStyle(style, i, 1)
+				}
+				display.PutString(s, playArea.LeftX+i*3+2, playArea.TopY+k, st, "  ")
 				k++
 			}
 		case 'W':
 			if len(pile.Cards) > 0 {
 				card := pile.Cards[pile.Firstfaceup]
-				putString(s, wasteArea.cardArea, wasteArea.topY+1, style, card.Rank+card.Suit)
+				display.PutString(s, wasteArea.CardArea, wasteArea.TopY+1, cursorStyle(theme.Card(card), i, 1), card.Rank+card.Suit)
 			} else {
-				putString(s, wasteArea.cardArea, wasteArea.topY+1, style, "  ")
+				display.PutString(s, wasteArea.CardArea, wasteArea.TopY+1, cursorStyle(style, i, 1), "  ")
 			}
 		case 'A':
+			str, st := "  ", style
 			if len(pile.Cards) > 0 {
 				card := pile.Cards[len(pile.Cards)-1]
-				switch card.Suit {
-				case "S":
-					putString(s, ace1.cardArea, ace1.topY+1, style, card.Rank+card.Suit)
-				case "H":
-					putString(s, ace2.cardArea, ace2.topY+1, style, card.Rank+card.Suit)
-				case "D":
-					putString(s, ace3.cardArea, ace3.topY+1, style, card.Rank+card.Suit)
-				case "C":
-					putString(s, ace4.cardArea, ace4.topY+1, style, card.Rank+card.Suit)
-				}
+				str, st = card.Rank+card.Suit, theme.Card(card)
 			}
+			p := pilePos(stacks, i)
+			display.PutString(s, p.X, p.Y, cursorStyle(st, i, 1), str)
 		}
 	}
 	s.Show()
 }
 
-// DealToWaste deals cards from the deck to the waste stack.
-// If all cards have been dealt from the deck it turns the waste stack into the deck
-// and increments the pass count.
-//
-// stacks: A slice containing all the stacks (could probably be just the wast stack)
-// deck: A pointer to the deck
-// pass: The pass count
+// Stock lists the cards still to be dealt from the deck for thoughtful mode.
+func stock(cards []generic.Card) string {
+	var names []string
+	for _, c := range cards {
+		names = append(names, c.Rank+c.Suit)
+	}
+	return strings.Join(names, " ")
+}
+
+// Keys are the key bindings for klondike.  They can be changed with a file read by display.LoadKeymap.
+// The digits used to give how many cards to move can not be changed.
+var keys = display.Keymap{
+	"column1": {"a"}, "column2": {"b"}, "column3": {"c"}, "column4": {"d"},
+	"column5": {"e"}, "column6": {"f"}, "column7": {"g"},
+	"waste":  {"w"},
+	"ace":    {"Enter"},
+	"deal":   {"Space"},
+	"quit":   {"q"},
+	"finish": {"Tab"},
+	"pause":  {"p"},
+	// cursor mode
+	"left":   {"Left", "h"},
+	"right":  {"Right", "l"},
+	"up":     {"Up", "k"},
+	"down":   {"Down", "j"},
+	"pick":   {"Enter"},
+	"cancel": {"Esc"},
+}
+
+// Cursor is the highlighted selection used in cursor mode.
+// pile:  The stack the cursor is on
+// depth:  How many cards from the top of a tableau stack are selected
+// held:  Cards have been picked up from the stack from
+type cursor struct {
+	on      bool
+	pile    int
+	depth   int
+	held    bool
+	from    int
+	howmany int
+}
+
+var cur cursor
+
+// CursorKey handles the keys for cursor mode.
+// Left and right move between stacks, up and down select more or fewer face up cards and
+// pick picks the cards up or drops them on the stack under the cursor.
 //
-// returns: The pass count
-func dealToWaste(stacks []solitaire.Pile, deck *generic.Deck, pass int) int {
-	if len(stacks[7].Cards) > 0 {
-		stacks[7].Cards[stacks[7].Firstfaceup].Turn()
-	}
-	if deck.AllDealt {
-		deck.Cards = deck.Cards[:0]
-		deck.Cards = append(deck.Cards, stacks[7].Cards...)
-		deck.LastDealt = 0
-		deck.AllDealt = false
-		stacks[7].Cards = stacks[7].Cards[:0]
-		pass++
-	}
-	stacks[7].Cards = append(stacks[7].Cards, deck.Deal(vcount, 1)...)
-	stacks[7].Firstfaceup = len(stacks[7].Cards) - 1
-	return pass
+// returns: The move and true if the key was used.
+func cursorKey(ev *tcell.EventKey, stacks []solitaire.Pile, cm move) (move, bool) {
+	ret := move{from: -1, to: -1}
+	switch {
+	case keys.Is(ev, "left"):
+		cur.pile = (cur.pile + len(stacks) - 1) % len(stacks)
+		cur.depth = 1
+	case keys.Is(ev, "right"):
+		cur.pile = (cur.pile + 1) % len(stacks)
+		cur.depth = 1
+	case keys.Is(ev, "up"):
+		p := stacks[cur.pile]
+		if cur.pile < 7 && cur.depth < len(p.Cards)-p.Firstfaceup {
+			cur.depth++
+		}
+	case keys.Is(ev, "down"):
+		if cur.depth > 1 {
+			cur.depth--
+		}
+	case keys.Is(ev, "pick"):
+		if cur.held {
+			cur.held = false
+			ret.from = cur.from
+			ret.to = cur.pile
+			ret.howmany = cur.howmany
+		} else if cur.pile < 8 && len(stacks[cur.pile].Cards) > 0 {
+			cur.held = true
+			cur.from = cur.pile
+			cur.howmany = cur.depth
+		}
+	case keys.Is(ev, "cancel"):
+		cur.held = false
+	default:
+		return cm, false
+	}
+	return ret, true
 }
 
 // processKey handles the processing of key strokes
 //
 // ev:  The event that that contains the key.
-// stacks:  The card stacks.  Passed primarily to handle dealing to the waste stack
-// deck:  The deck of cards.  Passed to handle dealing to the waste pile.
-// pass:  Number of passes so far reset by the deal to waste function.
-// movefrom:  The stack to move cards from on initial call it is -1 and is passed again to set the move to stack.
+// g:  The game.  Passed primarily to handle dealing to the waste stack
+// cm:  The move so far, on the initial call from is -1 and it is passed again to set the move to stack.
 //
 // returns: a filled move structure
 //
-func processKey(ev *tcell.EventKey, stacks []solitaire.Pile, deck *generic.Deck, cm move) move {
-	var ret move
-	switch ev.Key() {
-	case tcell.KeyEnter:
-		if cm.from != -1 {
-			if len(stacks[cm.from].Cards) != 0 {
-				switch stacks[cm.from].Cards[len(stacks[cm.from].Cards)-1].Suit {
-				case "S":
-					ret.from = cm.from
-					ret.to = 8
-					ret.pass = cm.pass
-					ret.howmany = 1
-				case "H":
-					ret.from = cm.from
-					ret.to = 9
-					ret.pass = cm.pass
-					ret.howmany = 1
-				case "D":
-					ret.from = cm.from
-					ret.to = 10
-					ret.pass = cm.pass
-					ret.howmany = 1
-				case "C":
-					ret.from = cm.from
-					ret.to = 11
-					ret.pass = cm.pass
-					ret.howmany = 1
-				}
-			} else {
-				ret.from = -1
-				ret.to = -1
-				ret.pass = cm.pass
-			}
+func processKey(ev *tcell.EventKey, g *engine.Game, cm move) move {
+	stacks := g.View().Stacks
+	if cur.on {
+		if ret, ok := cursorKey(ev, stacks, cm); ok {
+			return ret
 		}
-	case tcell.KeyRune:
-		switch unicode.ToUpper(ev.Rune()) {
-		case 'Q':
-			ret.from = -1
-			ret.to = -1
-			ret.pass = 3
-		case ' ':
-			ret.from = -1
-			ret.to = -1
-			ret.pass = dealToWaste(stacks[:], deck, cm.pass)
-		case 'A', 'B', 'C', 'D', 'E', 'F', 'G':
-			//		logger.Printf("in tableau")
+	}
+	ret := move{from: -1, to: -1}
+	switch {
+	case keys.Is(ev, "ace"):
+		if cm.from != -1 && len(stacks[cm.from].Cards) != 0 {
+			ret.from = cm.from
+			ret.to = engine.AceStack(stacks[cm.from].Cards[len(stacks[cm.from].Cards)-1].Suit)
+			ret.howmany = 1
+		}
+	case keys.Is(ev, "quit"):
+		ret.quit = true
+	case keys.Is(ev, "deal"):
+		g.Apply(engine.Move{Deal: true})
+	case keys.Is(ev, "waste"):
+		ret.from = engine.Waste
+	case ev.Key() == tcell.KeyRune && ev.Rune() >= '0' && ev.Rune() <= '9':
+		ret.howmany = cm.howmany*10 + int(ev.Rune()-'0')
+		if ret.howmany > 12 {
+			ret.howmany = 0
+		}
+	default:
+		for i := 0; i < engine.Columns; i++ {
+			if !keys.Is(ev, fmt.Sprintf("column%d", i+1)) {
+				continue
+			}
+			ret.howmany = cm.howmany
 			if cm.from == -1 {
-				ret.from = int(unicode.ToUpper(ev.Rune()) - 'A')
-				ret.to = -1
-				ret.pass = cm.pass
-				ret.howmany = cm.howmany
+				ret.from = i
 			} else {
 				ret.from = cm.from
-				ret.to = int(unicode.ToUpper(ev.Rune()) - 'A')
-				ret.pass = cm.pass
-				ret.howmany = cm.howmany
+				ret.to = i
 			}
-		case 'W':
-			//		logger.Printf("in waste")
-			ret.from = 7
-			ret.to = -1
-			ret.pass = cm.pass
-		case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
-			ret.from = -1
-			ret.to = -1
-			ret.pass = cm.pass
-			ret.howmany = cm.howmany*10 + int(ev.Rune()-'0')
-			if ret.howmany > 12 {
-				ret.howmany = 0
-			}
-		default:
-			ret.from = -1
-			ret.to = -1
-			ret.pass = cm.pass
 		}
 	}
 	return ret
 }
 
-func moveCards(stacks []solitaire.Pile, cm move) move {
-	//logger.Printf("cm = %v", cm)
+// MoveCards makes a move once both of its stacks are known.
+//
+// returns: The move reset for the next one, or unchanged if it is not complete.
+func moveCards(g *engine.Game, cm move) move {
 	if cm.from > -1 && cm.to > -1 {
 		if cm.to != cm.from {
-			if cm.to > 7 && stacks[cm.from].Firstfaceup < len(stacks[cm.from].Cards)-1 {
-				cm.howmany = len(stacks[cm.from].Cards) - 1
-			} else {
-				if cm.howmany < 1 {
-					cm.howmany = stacks[cm.from].Firstfaceup
-				} else {
-					cm.howmany = len(stacks[cm.from].Cards) - cm.howmany
-				}
-			}
-			if stacks[cm.from].CheckMove(&stacks[cm.to], cm.howmany) {
-				stacks[cm.from].DoMove(&stacks[cm.to], cm.howmany)
-			}
+			g.Apply(engine.Move{From: cm.from, To: cm.to, Count: cm.howmany})
 		}
 		cm.from = -1
 		cm.to = -1
@@ -345,61 +387,142 @@ func moveCards(stacks []solitaire.Pile, cm move) move {
 	return cm
 }
 
+// PilePos returns where the top card of a stack is shown on the screen.
+// An empty tableau stack gives where its first card goes.
+func pilePos(stacks []solitaire.Pile, i int) display.Point {
+	switch {
+	case i < 7:
+		n := 0
+		for _, card := range stacks[i].Cards {
+			if card.Faceup || thoughtful {
+				n++
+			}
+		}
+		if n == 0 {
+			n = 1
+		}
+		return display.Point{X: playArea.LeftX + i*3 + 2, Y: playArea.TopY + n}
+	case i == 7:
+		return display.Point{X: wasteArea.CardArea, Y: wasteArea.TopY + 1}
+	}
+	ace := []display.Box{ace1, ace2, ace3, ace4}[i-8]
+	return display.Point{X: ace.CardArea, Y: ace.TopY + 1}
+}
+
+// AutoPlay starts the next card moving to the aces for the player.
+// The move is made when the animation's Step gives it back to the game as the card arrives.
+//
+// s: Screen variable.
+// g: The game.
+// style: The style for the cards.
+// safe: Only make safe moves, otherwise move everything that will go.
+//
+// returns: true if a card was started moving.
+func autoPlay(s tcell.Screen, g *engine.Game, style tcell.Style, safe bool) bool {
+	m, ok := g.AutoMove(safe)
+	if !ok {
+		return false
+	}
+	stacks := g.View().Stacks
+	from := &stacks[m.From]
+	card := from.Cards[len(from.Cards)-1]
+	anim.Move(s, card.Rank+card.Suit, pilePos(stacks, m.From), pilePos(stacks, m.To), theme.Card(card), pending{m: m, safe: safe})
+	return true
+}
+
 // PlayGame is the main function that handles all aspects of the game.
 //
 // s: Screnn variable.
+// g: The game to play.
 // style: The style for the screen.
-func playGame(s tcell.Screen, style tcell.Style) int {
-	stacks := make([]solitaire.Pile, 12)
-	deck := generic.NewDeck()
-	deck.Shuffle()
-	// Make aces low card instead of high card
-	for i := range deck.Cards {
-		if deck.Cards[i].Rvalue == 14 { // change aces to 1 instead of 14
-			deck.Cards[i].Rvalue = 1
-		}
-	}
-	for i := range stacks {
-		switch i {
-		case 0, 1, 2, 3, 4, 5, 6: // tableau
-			stacks[i].Cards = deck.Deal(i+1, 1)
-			stacks[i].Firstfaceup = len(stacks[i].Cards) - 1
-			stacks[i].Ptype = 'T'
-		case 7: // waste
-			stacks[i].Cards = deck.Deal(3, 1)
-			stacks[i].Ptype = 'W'
-			stacks[i].Firstfaceup = len(stacks[i].Cards) - 1
-		case 8, 9, 10, 11: // aces
-			stacks[i].Ptype = 'A'
-		}
-	}
+//
+// returns: -1 if the game was won, otherwise the number of passes.
+func playGame(s tcell.Screen, g *engine.Game, style tcell.Style) int {
 	w, h := s.Size()
-	putString(s, 0, h-1, style, strings.Repeat(" ", w-1))
+	display.PutString(s, 0, h-1, style, strings.Repeat(" ", w-1))
 	s.Show()
-	cardmove := move{from: -1, to: -1, pass: 0, howmany: 0}
-	for cardmove.pass < vcount {
-		showStacks(s, stacks, style)
-		putString(s, 0, h-1, style, fmt.Sprintf("Pass# %02d, Waste# %02d, Deck# %02d", cardmove.pass, len(stacks[7].Cards), len(deck.Cards)-deck.LastDealt))
+	cardmove := move{from: -1, to: -1, howmany: 0}
+	animating := false
+	stop := display.StartTicker(s, time.Second)
+	defer stop()
+	defer anim.Stop()
+	clock.Start()
+	defer clock.Pause()
+	for !cardmove.quit && !g.Over() {
+		v := g.View()
+		showStacks(s, v.Stacks, style)
+		status := fmt.Sprintf("Pass# %02d, Waste# %02d, Deck# %02d, Moves# %03d, Time %s", v.Pass, len(v.Stacks[engine.Waste].Cards), len(v.Stock), v.Moves, stats.Format(clock.Elapsed()))
+		if !clock.Running() {
+			status += " Paused, P to go on"
+		}
+		display.PutString(s, 0, h-1, theme.Status, strings.Repeat(" ", w-1))
+		display.PutString(s, 0, h-1, theme.Status, status)
+		if thoughtful {
+			display.PutString(s, 0, h-2, style, strings.Repeat(" ", w-1))
+			display.PutString(s, 0, h-2, style, "Stock: "+stock(v.Stock))
+		}
+		anim.Draw(s)
 		s.Show()
-		ev := s.PollEvent()
+		ev := focus.Filter(s.PollEvent())
 		switch ev := ev.(type) {
+		case *display.EventFocus:
+			if !ev.Focused && clock.Running() {
+				clock.Pause()
+				focusPaused = true
+			} else if ev.Focused && focusPaused {
+				clock.Start()
+				focusPaused = false
+			}
 		case *tcell.EventKey:
-			if ev.Key() == tcell.KeyCtrlL {
+			switch {
+			case ev.Key() == tcell.KeyCtrlL:
 				s.Sync()
-			} else {
-				cardmove = moveCards(stacks[:], processKey(ev, stacks[:], &deck, cardmove))
-				//logger.Printf("cardmove = %v", cardmove)
+			case keys.Is(ev, "pause"):
+				clock.Toggle()
+				focusPaused = false
+			case !clock.Running():
+				// no play while paused
+			case keys.Is(ev, "finish"):
+				if !animating && g.CanFinish() {
+					animating = autoPlay(s, g, style, false)
+				}
+			default:
+				cardmove = moveCards(g, processKey(ev, g, cardmove))
+				if autoplay && !animating {
+					animating = autoPlay(s, g, style, true)
+				}
+			}
+		case *tcell.EventInterrupt:
+			if !clock.Running() {
+				break // the moving card waits while paused
+			}
+			if data, ok := anim.Step(ev.Data()); ok {
+				p := data.(pending)
+				g.Apply(p.m)
+				animating = autoPlay(s, g, style, p.safe)
 			}
 		}
-		total := 0
-		for i := 8; i < 12; i++ { // total the number of cards in aces
-			total += len(stacks[i].Cards)
-		}
-		if total == 52 { // if all cards are in aces stacks we are done
+		if g.IsWon() { // if all cards are in aces stacks we are done
 			return -1
 		}
 	}
-	return cardmove.pass
+	return g.View().Pass
+}
+
+// Winnable finds a deal that can be won at a difficulty, from the cache of rated deals if it has one.
+// difficulty: easy, medium, hard or expert
+// path: The file of rated deals, by default one in the user config directory
+func winnable(difficulty, path string) (solver.Rated, error) {
+	d, err := solver.ParseDifficulty(difficulty)
+	if err != nil {
+		return solver.Rated{}, err
+	}
+	if path == "" {
+		if path, err = solver.DefaultCachePath(); err != nil {
+			return solver.Rated{}, err
+		}
+	}
+	return solver.Deal(d, options(), path, solver.SearchTime)
 }
 
 func main() {
@@ -407,38 +530,149 @@ func main() {
 	//		log.Fatal("Error opening error log.\n")
 	//}
 	//defer f.Close()
-	numptr := flag.Int("v", 3, "Variant of klondike 1 or 3")
+	numptr := flag.Int("v", 3, "Number of cards dealt to the waste at a time, 1, 2 or 3")
+	passptr := flag.Int("passes", -1, "Number of passes through the deck, 0 for unlimited, by default the same as -v")
+	buildptr := flag.String("build", "alternate", "How the tableau is built: alternate colors, suit, or color for whitehead with every card face up")
+	flag.BoolVar(&thoughtful, "thoughtful", false, "Show all the face down cards")
+	flag.BoolVar(&autoplay, "auto", false, "Move cards to the aces whenever it is safe")
+	speedptr := flag.Int("speed", 20, "Milliseconds between the frames of a moving card, 0 for no animation")
+	cryptoptr := flag.Bool("crypto", false, "Shuffle using crypto/rand so the deal can not be predicted")
+	fairptr := flag.Bool("fair", false, "Show a hash of the deal seed before play and reveal the seed afterwards")
+	seedptr := flag.String("seed", "", "Replay the deal for a seed revealed after a -fair game")
+	diffptr := flag.String("difficulty", "", "Deal only games that can be won, easy, medium, hard or expert")
+	dealsptr := flag.String("deals", "", "File of rated deals kept for -difficulty, by default klondike.deals in the user config directory")
+	flag.BoolVar(&cur.on, "cursor", false, "Move a cursor over the stacks with the arrow or hjkl keys and pick up and drop cards with Enter")
+	keysptr := flag.String("keys", "", "File of key bindings, by default klondike.keys in the user config directory")
+	resultsptr := flag.String("results", "", "File to save the result of the game in, by default in the user config directory")
+	themeptr := flag.String("theme", display.DefaultTheme, "Colors to use: "+display.ThemeNames(display.Themes)+" or one from the -themes file")
+	textptr := flag.Bool("text", false, "Play with typed commands and a written description of the board, for screen readers")
+	themesptr := flag.String("themes", "", "File of color themes, by default klondike.themes in the user config directory")
+	var daily stats.Daily
+	daily.Flags(flag.CommandLine)
 	flag.Parse()
 	vcount = *numptr
-	if vcount != 1 && vcount != 3 {
-		fmt.Fprintf(os.Stderr, "Variant must be 1 or 3\n")
+	if vcount < 1 || vcount > 3 {
+		fmt.Fprintf(os.Stderr, "Variant must be 1, 2 or 3\n")
 		os.Exit(1)
 	}
-	tcell.SetEncodingFallback(tcell.EncodingFallbackASCII)
-	s, e := tcell.NewScreen()
-	if e != nil {
-		fmt.Fprintf(os.Stderr, "%v\n", e)
+	anim = display.NewAnimator(8, time.Duration(*speedptr)*time.Millisecond)
+	passes = *passptr
+	if passes < 0 {
+		passes = vcount
+	}
+	if *keysptr == "" {
+		if dir, err := os.UserConfigDir(); err == nil {
+			*keysptr = filepath.Join(dir, "cardgames", "klondike.keys")
+		}
+	}
+	var err error
+	var ok bool
+	if keys, err = display.LoadKeymap(*keysptr, keys); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", *keysptr, err)
 		os.Exit(1)
 	}
-	if e = s.Init(); e != nil {
-		fmt.Fprintf(os.Stderr, "%v\n", e)
+	if *themesptr == "" {
+		if dir, err := os.UserConfigDir(); err == nil {
+			*themesptr = filepath.Join(dir, "cardgames", "klondike.themes")
+		}
+	}
+	themes, err := display.LoadThemes(*themesptr, display.Themes)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", *themesptr, err)
 		os.Exit(1)
 	}
-	s.SetStyle(tcell.StyleDefault.
-		Foreground(tcell.ColorBlack).
-		Background(tcell.ColorWhite))
-	s.Clear()
-	s.HideCursor()
-	if err := drawScreen(s, tcell.StyleDefault); err != nil {
-		s.Fini()
-		fmt.Println(err)
+	if theme, ok = themes[*themeptr]; !ok {
+		fmt.Fprintf(os.Stderr, "Theme must be one of %s\n", display.ThemeNames(themes))
+		os.Exit(1)
+	}
+	cur.depth = 1
+	builds := map[string]rune{"alternate": 'T', "suit": 'S', "color": 'M'}
+	if build, ok = builds[*buildptr]; !ok {
+		fmt.Fprintf(os.Stderr, "Build must be alternate, suit or color\n")
+		os.Exit(1)
+	}
+	var commit generic.Commitment
+	var rated solver.Rated
+	switch {
+	case daily.On:
+		rules := fmt.Sprintf("draw %d, passes %d, build %s", vcount, passes, *buildptr)
+		if thoughtful {
+			rules += ", thoughtful"
+		}
+		shuffleSource = daily.Source("klondike", rules, time.Now())
+	case *diffptr != "":
+		rated, err = winnable(*diffptr, *dealsptr)
+		shuffleSource = rated.Source()
+	case *seedptr != "":
+		commit, err = generic.ParseSeed(*seedptr)
+	case *fairptr:
+		commit, err = generic.NewCommitment()
+	case *cryptoptr:
+		shuffleSource = generic.CryptoSource{}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
 		os.Exit(1)
 	}
-	st := playGame(s, tcell.StyleDefault)
-	s.Fini()
+	if commit.Seed != nil {
+		shuffleSource = commit.Source()
+		dealHash = commit.Hash
+	}
+	var st int
+	g := engine.NewGame(options())
+	if *textptr {
+		st = playText(os.Stdin, os.Stdout, g)
+	} else {
+		tcell.SetEncodingFallback(tcell.EncodingFallbackASCII)
+		s, e := tcell.NewScreen()
+		if e != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", e)
+			os.Exit(1)
+		}
+		if e = s.Init(); e != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", e)
+			os.Exit(1)
+		}
+		s.SetStyle(theme.Screen)
+		s.Clear()
+		s.HideCursor()
+		if err := drawScreen(s, theme.Box); err != nil {
+			s.Fini()
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		focus = display.ReportFocus(os.Stdout)
+		st = playGame(s, g, theme.Screen)
+		focus.Stop()
+		s.Fini()
+	}
 	if st == -1 {
 		fmt.Println("Congratulations you won!")
 	} else {
 		fmt.Println("You either quit or lost. Better luck next time.")
 	}
+	moves := g.View().Moves
+	fmt.Printf("%d moves in %s\n", moves, stats.Format(clock.Elapsed()))
+	path := *resultsptr
+	if path == "" {
+		path, err = stats.DefaultPath()
+	}
+	result := stats.Result{Game: "klondike", Won: st == -1, Moves: moves, Elapsed: clock.Elapsed(), Finished: time.Now()}
+	if err == nil {
+		err = stats.Append(path, result)
+	}
+	if err == nil {
+		err = daily.Record(os.Stdout, result)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "The result could not be saved: %v\n", err)
+	}
+	if rated.Seed != "" {
+		fmt.Printf("The deal was rated %s, replay it with -seed %s\n", rated.Difficulty(), rated.Seed)
+	}
+	if dealHash != "" {
+		fmt.Printf("Deal seed %s\n", commit.SeedString())
+		fmt.Printf("Its sha256 should be the hash %s shown before play.\n", dealHash)
+		fmt.Println("Replay the deal with -seed to check it.")
+	}
 }
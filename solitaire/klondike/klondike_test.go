@@ -1,11 +1,14 @@
 package main
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/gdamore/tcell"
+	"github.com/tmasterson/cardgames/display"
 	"github.com/tmasterson/cardgames/generic"
 	"github.com/tmasterson/cardgames/solitaire"
+	"github.com/tmasterson/cardgames/solitaire/klondike/engine"
 )
 
 func mkTestScreen(t *testing.T, charset string) tcell.SimulationScreen {
@@ -19,65 +22,6 @@ func mkTestScreen(t *testing.T, charset string) tcell.SimulationScreen {
 	return s
 }
 
-func TestPutString(t *testing.T) {
-	s := mkTestScreen(t, "")
-	defer s.Fini()
-	putString(s, 1, 1, tcell.StyleDefault, "test")
-	s.Show()
-	txt, x, y := s.GetContents()
-	if len(txt) != x*y {
-		t.Errorf("Incorrect size of content: should be %d but was %d", x*y, len(txt))
-	}
-	var txt2 []byte
-	for i := 81; i < 85; i++ {
-		txt2 = append(txt2, txt[i].Bytes[0])
-	}
-	if string(txt2) != "test" {
-		t.Errorf("Incorrect string should be test but was %s", txt2)
-	}
-}
-
-func TestMakeBox(t *testing.T) {
-	s := mkTestScreen(t, "")
-	defer s.Fini()
-	box, err := makeBox(s, "test", 1, 1, 6, 4, tcell.StyleDefault)
-	if err == nil {
-		t.Fatalf("Should get and error here")
-	}
-	box, err = makeBox(s, "test", 1, 1, 8, 4, tcell.StyleDefault)
-	if err != nil {
-		t.Fatalf("Should not get an error here %s", err)
-	}
-	s.Show()
-	txt, x, y := s.GetContents()
-	if len(txt) != x*y {
-		t.Errorf("Incorrect size of content: should be %d but was %d", x*y, len(txt))
-	}
-	if box.leftX != 1 || box.rightX != 8 || box.topY != 1 || box.botY != 4 || box.style != tcell.StyleDefault || box.title != "test" || box.cardArea != 3 {
-		t.Errorf("expected 1, 8, 1, 4, %v, test, 3 but was %d %d %d %d %v %s %d", tcell.StyleDefault, box.leftX, box.rightX, box.topY, box.botY, box.style, box.title, box.cardArea)
-	}
-	w, _ := s.Size()
-	topline := box.topY * w
-	centerpos := (box.rightX-box.leftX)/2 + topline + box.leftX
-	titlepos := centerpos - len("test")/2
-	if txt[titlepos].Runes[0] != 't' {
-		t.Errorf("expected t but was %c at %d %d", txt[titlepos].Runes[0], titlepos, centerpos)
-	}
-	if txt[topline+box.leftX].Runes[0] != tcell.RuneULCorner {
-		t.Errorf("Expected %c but got %c", tcell.RuneULCorner, txt[topline+box.leftX].Runes[0])
-	}
-	if txt[topline+box.leftX+box.rightX-1].Runes[0] != tcell.RuneURCorner {
-		t.Errorf("Expected %c but got %c", tcell.RuneURCorner, txt[topline+box.leftX+box.rightX-1].Runes[0])
-	}
-	botline := box.botY * w
-	if txt[botline+box.leftX].Runes[0] != tcell.RuneLLCorner {
-		t.Errorf("Expected %c but got %c", tcell.RuneLLCorner, txt[botline+box.leftX].Runes[0])
-	}
-	if txt[botline+box.leftX+box.rightX-1].Runes[0] != tcell.RuneLRCorner {
-		t.Errorf("Expected %c but got %c", tcell.RuneLRCorner, txt[botline+box.leftX+box.rightX-1].Runes[0])
-	}
-}
-
 func TestDrawScreen(t *testing.T) {
 	s := mkTestScreen(t, "")
 	defer s.Fini()
@@ -87,37 +31,36 @@ func TestDrawScreen(t *testing.T) {
 		t.Errorf("Expected an error here")
 	}
 	s.SetSize(80, 25)
+	saved := keys
+	defer func() { keys = saved }()
+	keys = display.Keymap{}
+	for action, k := range saved {
+		keys[action] = k
+	}
+	keys["quit"] = []string{"x"}
 	err = drawScreen(s, tcell.StyleDefault)
 	if err != nil {
 		t.Errorf("There should be no errors")
 	}
-}
-
-func TestDealToWaste(t *testing.T) {
-	deck := generic.NewDeck()
-	stacks := make([]solitaire.Pile, 12)
-	stacks[7].Cards = deck.Deal(3, 1)
-	stacks[7].Firstfaceup = len(stacks[7].Cards) - 1
-	pass := dealToWaste(stacks[:], &deck, 0)
-	if len(stacks[7].Cards) != 6 {
-		t.Errorf("Should have 6 cards on stack but have %d", len(stacks[7].Cards))
-	}
-	if pass != 0 {
-		t.Errorf("Pass should be 0 but was %d", pass)
-	}
-	deck.AllDealt = true
-	pass = dealToWaste(stacks[:], &deck, 0)
-	if len(stacks[7].Cards) != 3 {
-		t.Errorf("Should have 3 cards on stack but have %d", len(stacks[7].Cards))
-	}
-	if pass != 1 {
-		t.Errorf("Pass should be 1 but was %d", pass)
+	s.Show()
+	cells, w, h := s.GetContents()
+	var screen strings.Builder
+	for i, c := range cells {
+		screen.WriteString(string(c.Runes))
+		if i%w == w-1 && i/w < h-1 {
+			screen.WriteString("\n")
+		}
 	}
-	if len(deck.Cards) != 6 {
-		t.Errorf("deck should have 6 cards left but has %d", len(deck.Cards))
+	if !strings.Contains(screen.String(), "Space deals and X quits.") {
+		t.Errorf("Expected the help to show the keys bound but was\n%s", screen.String())
 	}
 }
 
+// newTestGame returns a game of the stacks given with the whole deck in the stock.
+func newTestGame(stacks []solitaire.Pile) *engine.Game {
+	return engine.NewGameFrom(engine.DefaultOptions, stacks, generic.NewDeck())
+}
+
 func TestProcessKey(t *testing.T) {
 	stacks := make([]solitaire.Pile, 12)
 	deck := generic.NewDeck()
@@ -135,125 +78,175 @@ func TestProcessKey(t *testing.T) {
 	stacks[5].Ptype = 'T'
 	stacks[6].Cards = append(stacks[6].Cards, generic.NewCard("3", "D", "red", 3, 4, true))
 	stacks[6].Ptype = 'T'
-	cm := move{from: -1, to: -1, pass: 0, howmany: 0}
-	cm = processKey(tcell.NewEventKey(tcell.KeyRune, 'O', tcell.ModNone), stacks[:], &deck, cm)
-	if cm.to != -1 || cm.from != -1 || cm.pass != 0 {
-		t.Errorf("Expected -1, -1, 0 but was %d, %d, %d", cm.to, cm.from, cm.pass)
-	}
-	cm = move{from: -1, to: -1, pass: 0, howmany: 0}
-	cm = processKey(tcell.NewEventKey(tcell.KeyRune, 'Q', tcell.ModNone), stacks[:], &deck, cm)
-	if cm.to != -1 || cm.from != -1 || cm.pass != 3 {
-		t.Errorf("Expected -1, -1, 3 but was %d, %d, %d", cm.to, cm.from, cm.pass)
-	}
-	deck.AllDealt = true
-	cm = move{from: -1, to: -1, pass: 0, howmany: 0}
-	cm = processKey(tcell.NewEventKey(tcell.KeyRune, ' ', tcell.ModNone), stacks[:], &deck, cm)
-	if cm.to != -1 || cm.from != -1 || cm.pass != 1 {
-		t.Errorf("Expected -1, -1, 1 but was %d, %d, %d", cm.to, cm.from, cm.pass)
-	}
-	cm = move{from: -1, to: -1, pass: 0, howmany: 0}
-	cm = processKey(tcell.NewEventKey(tcell.KeyRune, 'B', tcell.ModNone), stacks[:], &deck, cm)
-	if cm.to != -1 || cm.from != 1 || cm.pass != 0 {
-		t.Errorf("Expected -1, 1, 0 but was %v", cm)
-	}
-	cm = move{from: 1, to: -1, pass: 0, howmany: 0}
-	cm = processKey(tcell.NewEventKey(tcell.KeyRune, 'F', tcell.ModNone), stacks[:], &deck, cm)
-	if cm.to != 5 || cm.from != 1 || cm.pass != 0 {
-		t.Errorf("Expected 5, 1, 0 but was %d, %d, %d", cm.to, cm.from, cm.pass)
-	}
-	cm = processKey(tcell.NewEventKey(tcell.KeyRune, '1', tcell.ModNone), stacks[:], &deck, cm)
-	cm = processKey(tcell.NewEventKey(tcell.KeyRune, '2', tcell.ModNone), stacks[:], &deck, cm)
-	if cm.to != -1 || cm.from != -1 || cm.pass != 0 || cm.howmany != 12 {
-		t.Errorf("Expected -1, -1, 0, 12 but was %v", cm)
-	}
-	cm = move{from: -1, to: -1, pass: 0, howmany: 0}
-	cm = processKey(tcell.NewEventKey(tcell.KeyRune, 'W', tcell.ModNone), stacks[:], &deck, cm)
-	if cm.to != -1 || cm.from != 7 || cm.pass != 0 {
-		t.Errorf("Expected -1, 7, 0 but was %d, %d, %d", cm.to, cm.from, cm.pass)
-	}
-	cm = move{from: 1, to: -1, pass: 0, howmany: 0}
-	cm = processKey(tcell.NewEventKey(tcell.KeyEnter, ' ', tcell.ModNone), stacks[:], &deck, cm)
-	if cm.to != 8 || cm.from != 1 || cm.pass != 0 {
-		t.Errorf("Expected 8, 1, 0 but was %d, %d, %d", cm.to, cm.from, cm.pass)
-	}
-	cm = move{from: 6, to: -1, pass: 0, howmany: 0}
-	cm = processKey(tcell.NewEventKey(tcell.KeyEnter, ' ', tcell.ModNone), stacks[:], &deck, cm)
-	if cm.to != 10 || cm.from != 6 || cm.pass != 0 {
-		t.Errorf("Expected 10, 6, 0 but was %d, %d, %d", cm.to, cm.from, cm.pass)
+	deck.Deal(52, 0)
+	g := engine.NewGameFrom(engine.DefaultOptions, stacks, deck)
+	cm := move{from: -1, to: -1, howmany: 0}
+	cm = processKey(tcell.NewEventKey(tcell.KeyRune, 'O', tcell.ModNone), g, cm)
+	if cm.to != -1 || cm.from != -1 {
+		t.Errorf("Expected -1, -1 but was %d, %d", cm.to, cm.from)
+	}
+	cm = move{from: -1, to: -1, howmany: 0}
+	cm = processKey(tcell.NewEventKey(tcell.KeyRune, 'Q', tcell.ModNone), g, cm)
+	if cm.to != -1 || cm.from != -1 || !cm.quit {
+		t.Errorf("Expected -1, -1 and quit but was %v", cm)
+	}
+	cm = move{from: -1, to: -1, howmany: 0}
+	cm = processKey(tcell.NewEventKey(tcell.KeyRune, ' ', tcell.ModNone), g, cm)
+	if cm.to != -1 || cm.from != -1 || g.View().Pass != 0 {
+		t.Errorf("Expected -1, -1 and no pass used with nothing to deal but was %d, %d, %d", cm.to, cm.from, g.View().Pass)
+	}
+	cm = move{from: -1, to: -1, howmany: 0}
+	cm = processKey(tcell.NewEventKey(tcell.KeyRune, 'B', tcell.ModNone), g, cm)
+	if cm.to != -1 || cm.from != 1 {
+		t.Errorf("Expected -1, 1 but was %v", cm)
+	}
+	cm = move{from: 1, to: -1, howmany: 0}
+	cm = processKey(tcell.NewEventKey(tcell.KeyRune, 'F', tcell.ModNone), g, cm)
+	if cm.to != 5 || cm.from != 1 {
+		t.Errorf("Expected 5, 1 but was %d, %d", cm.to, cm.from)
+	}
+	cm = processKey(tcell.NewEventKey(tcell.KeyRune, '1', tcell.ModNone), g, cm)
+	cm = processKey(tcell.NewEventKey(tcell.KeyRune, '2', tcell.ModNone), g, cm)
+	if cm.to != -1 || cm.from != -1 || cm.howmany != 12 {
+		t.Errorf("Expected -1, -1, 12 but was %v", cm)
+	}
+	cm = move{from: -1, to: -1, howmany: 0}
+	cm = processKey(tcell.NewEventKey(tcell.KeyRune, 'W', tcell.ModNone), g, cm)
+	if cm.to != -1 || cm.from != 7 {
+		t.Errorf("Expected -1, 7 but was %d, %d", cm.to, cm.from)
+	}
+	cm = move{from: 1, to: -1, howmany: 0}
+	cm = processKey(tcell.NewEventKey(tcell.KeyEnter, ' ', tcell.ModNone), g, cm)
+	if cm.to != 8 || cm.from != 1 {
+		t.Errorf("Expected 8, 1 but was %d, %d", cm.to, cm.from)
+	}
+	cm = move{from: 6, to: -1, howmany: 0}
+	cm = processKey(tcell.NewEventKey(tcell.KeyEnter, ' ', tcell.ModNone), g, cm)
+	if cm.to != 10 || cm.from != 6 {
+		t.Errorf("Expected 10, 6 but was %d, %d", cm.to, cm.from)
 	}
 	stacks[6].Cards[0].Suit = "H"
-	cm = processKey(tcell.NewEventKey(tcell.KeyEnter, ' ', tcell.ModNone), stacks[:], &deck, cm)
-	if cm.to != 9 || cm.from != 6 || cm.pass != 0 {
-		t.Errorf("Expected 9, 6, 0 but was %d, %d, %d", cm.to, cm.from, cm.pass)
+	cm = processKey(tcell.NewEventKey(tcell.KeyEnter, ' ', tcell.ModNone), g, cm)
+	if cm.to != 9 || cm.from != 6 {
+		t.Errorf("Expected 9, 6 but was %d, %d", cm.to, cm.from)
 	}
 	stacks[6].Cards[0].Suit = "C"
-	cm = processKey(tcell.NewEventKey(tcell.KeyEnter, ' ', tcell.ModNone), stacks[:], &deck, cm)
-	if cm.to != 11 || cm.from != 6 || cm.pass != 0 {
-		t.Errorf("Expected 11, 6, 0 but was %d, %d, %d", cm.to, cm.from, cm.pass)
+	cm = processKey(tcell.NewEventKey(tcell.KeyEnter, ' ', tcell.ModNone), g, cm)
+	if cm.to != 11 || cm.from != 6 {
+		t.Errorf("Expected 11, 6 but was %d, %d", cm.to, cm.from)
 	}
 }
 
 func TestMoveCards(t *testing.T) {
-	var cm2 move
 	stacks := make([]solitaire.Pile, 12)
-	stacks[0].Cards = append(stacks[0].Cards, generic.NewCard("Q", "S", "black", 12, 16, true))
+	stacks[0].Cards = []generic.Card{generic.NewCard("Q", "S", "black", 12, 16, true)}
+	stacks[1].Cards = []generic.Card{generic.NewCard("J", "H", "red", 11, 8, true)}
+	g := newTestGame(stacks)
+	cm := move{from: 1, to: -1}
+	if cm2 := moveCards(g, cm); cm2 != cm {
+		t.Errorf("Expected an incomplete move unchanged but was %v", cm2)
+	}
+	cm2 := moveCards(g, move{from: 0, to: 1})
+	if cm2.from != -1 || cm2.to != -1 || g.View().Moves != 0 {
+		t.Errorf("Expected the QS not to go on the JH but was %v", cm2)
+	}
+	moveCards(g, move{from: 1, to: 0})
+	if v := g.View(); len(v.Stacks[0].Cards) != 2 || v.Moves != 1 {
+		t.Errorf("Expected the JH on the QS")
+	}
+}
+
+func TestStock(t *testing.T) {
+	deck := generic.NewDeck()
+	if str := stock(deck.Cards[49:]); str != "AD AC AS" {
+		t.Errorf("Expected AD AC AS but was %q", str)
+	}
+	if str := stock(nil); str != "" {
+		t.Errorf("Expected an empty stock but was %q", str)
+	}
+}
+
+func TestAutoPlay(t *testing.T) {
+	s := mkTestScreen(t, "")
+	defer s.Fini()
+	s.SetSize(80, 25)
+	drawScreen(s, tcell.StyleDefault)
+	stacks := make([]solitaire.Pile, 12)
+	for i := 8; i < 12; i++ {
+		stacks[i].Ptype = 'A'
+	}
+	stacks[3].Ptype = 'T'
+	stacks[3].Cards = []generic.Card{generic.NewCard("A", "D", "red", 1, 4, true)}
+	if p := pilePos(stacks, 3); p.X != playArea.LeftX+11 || p.Y != playArea.TopY+1 {
+		t.Errorf("Expected the AD at %d,%d but was %v", playArea.LeftX+11, playArea.TopY+1, p)
+	}
+	if p := pilePos(stacks, 10); p.X != ace3.CardArea {
+		t.Errorf("Expected stack 10 in the third ace box but was %v", p)
+	}
+	if !autoPlay(s, newTestGame(stacks), tcell.StyleDefault, true) {
+		t.Fatalf("Expected the AD to start moving")
+	}
+	ev, ok := s.PollEvent().(*tcell.EventInterrupt)
+	if !ok {
+		t.Fatalf("Expected an interrupt when the card arrived")
+	}
+	data, ok := anim.Step(ev.Data())
+	if !ok {
+		t.Fatalf("Expected the card to arrive but was %v", ev.Data())
+	}
+	p := data.(pending)
+	if p.m.From != 3 || p.m.To != 10 || !p.safe {
+		t.Errorf("Expected a safe move from 3 to 10 but was %v", p)
+	}
+	if len(stacks[3].Cards) != 1 {
+		t.Errorf("Expected the move not to be made until the interrupt is handled")
+	}
+}
+
+func TestCursorKey(t *testing.T) {
+	cur = cursor{on: true, depth: 1}
+	defer func() { cur = cursor{} }()
+	stacks := make([]solitaire.Pile, 12)
 	stacks[0].Ptype = 'T'
-	stacks[1].Cards = append(stacks[1].Cards, generic.NewCard("4", "S", "black", 4, 16, true))
+	stacks[0].Cards = []generic.Card{
+		generic.NewCard("9", "C", "black", 9, 2, false),
+		generic.NewCard("8", "H", "red", 8, 8, true),
+		generic.NewCard("7", "S", "black", 7, 16, true),
+	}
+	stacks[0].Firstfaceup = 1
 	stacks[1].Ptype = 'T'
-	stacks[2].Cards = append(stacks[2].Cards, generic.NewCard("T", "S", "black", 10, 16, true))
-	stacks[2].Ptype = 'T'
-	stacks[3].Cards = append(stacks[3].Cards, generic.NewCard("8", "S", "black", 8, 16, true))
-	stacks[3].Ptype = 'T'
-	stacks[4].Cards = append(stacks[4].Cards, generic.NewCard("9", "S", "black", 9, 16, true))
-	stacks[4].Ptype = 'T'
-	stacks[5].Cards = append(stacks[5].Cards, generic.NewCard("7", "S", "black", 7, 16, true))
-	stacks[5].Ptype = 'T'
-	stacks[6].Cards = append(stacks[6].Cards, generic.NewCard("3", "D", "red", 3, 4, true))
-	stacks[6].Ptype = 'T'
-	cm := move{from: -1, to: -1, pass: 0, howmany: 0}
-	cm2 = moveCards(stacks[:], cm)
-	if cm2 != cm {
-		t.Errorf("Expected %v but was %v", cm, cm2)
-	}
-	cm = move{from: 1, to: -1, pass: 0, howmany: 0}
-	cm2 = moveCards(stacks[:], cm)
-	if cm2 != cm {
-		t.Errorf("Expected %v but was %v", cm, cm2)
-	}
-	cm = move{from: 1, to: 1, pass: 0, howmany: 0}
-	cm2 = moveCards(stacks[:], cm)
-	if cm2.from != -1 || cm2.to != -1 {
-		t.Errorf("Expected -1, -1 but was %d, %d", cm2.from, cm2.to)
-	}
-	cm = move{from: 4, to: 5, pass: 0, howmany: 0}
-	cm2 = moveCards(stacks[:], cm)
-	if cm2.from != -1 || cm2.to != -1 || len(stacks[4].Cards) != 1 || len(stacks[5].Cards) != 1 {
-		t.Errorf("Expected -1, -1, 1, 1 but was %d, %d, %d, %d", cm2.from, cm2.to, len(stacks[4].Cards), len(stacks[5].Cards))
-	}
-	cm = move{from: 6, to: 1, pass: 0, howmany: 0}
-	cm2 = moveCards(stacks[:], cm)
-	if cm2.from != -1 || cm2.to != -1 || len(stacks[1].Cards) != 2 || len(stacks[6].Cards) != 0 {
-		t.Errorf("Expected -1, -1, 2, 0 but was %d, %d, %d, %d", cm2.from, cm2.to, len(stacks[1].Cards), len(stacks[6].Cards))
-	}
-	stacks[6].Cards = append(stacks[6].Cards, generic.NewCard("J", "D", "red", 11, 4, false))
-	stacks[6].Cards = append(stacks[6].Cards, generic.NewCard("9", "C", "black", 9, 2, false))
-	stacks[6].Cards = append(stacks[6].Cards, generic.NewCard("7", "C", "black", 7, 2, true))
-	stacks[6].Cards = append(stacks[6].Cards, generic.NewCard("6", "D", "red", 6, 4, true))
-	stacks[6].Cards = append(stacks[6].Cards, generic.NewCard("5", "S", "black", 5, 16, true))
-	cm = move{from: 6, to: 5, pass: 0, howmany: 2}
-	cm2 = moveCards(stacks[:], cm)
-	if cm2.from != -1 || cm2.to != -1 || len(stacks[5].Cards) != 3 || len(stacks[6].Cards) != 3 {
-		t.Errorf("Expected -1, -1, 3, 3 but was %d, %d, %d, %d", cm2.from, cm2.to, len(stacks[5].Cards), len(stacks[6].Cards))
-	}
-    if !stacks[6].Cards[len(stacks[6].Cards)-1].Faceup {
-        t.Errorf("expected last card to be faceup but it was not")
-    }
-	stacks[4].Cards = append(stacks[4].Cards, generic.NewCard("8", "D", "red", 8, 4, true))
-    stacks[4].Firstfaceup = len(stacks[4].Cards)-1
-    stacks[6].Firstfaceup = len(stacks[6].Cards)-1
-	cm = move{from: 6, to: 4, pass: 0, howmany: 1}
-	cm2 = moveCards(stacks[:], cm)
-	if cm2.from != -1 || cm2.to != -1 || len(stacks[4].Cards) != 3 || len(stacks[6].Cards) != 2 {
-		t.Errorf("Expected -1, -1, 3, 2 but was %d, %d, %d, %d", cm2.from, cm2.to, len(stacks[4].Cards), len(stacks[6].Cards))
+	stacks[1].Cards = []generic.Card{generic.NewCard("9", "S", "black", 9, 16, true)}
+	g := newTestGame(stacks)
+	cm := move{from: -1, to: -1}
+	for _, k := range []tcell.Key{tcell.KeyUp, tcell.KeyUp, tcell.KeyUp} {
+		cm = processKey(tcell.NewEventKey(k, 0, tcell.ModNone), g, cm)
+	}
+	if cur.depth != 2 {
+		t.Errorf("Expected the depth to stop at the 2 face up cards but was %d", cur.depth)
+	}
+	cm = processKey(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone), g, cm)
+	if !cur.held || cur.from != 0 || cur.howmany != 2 {
+		t.Errorf("Expected 2 cards picked up from stack 0 but was %+v", cur)
+	}
+	if st := cursorStyle(tcell.StyleDefault, 0, 2); st == tcell.StyleDefault {
+		t.Errorf("Expected the held cards to be highlighted")
+	}
+	cm = processKey(tcell.NewEventKey(tcell.KeyRune, 'l', tcell.ModNone), g, cm)
+	cm = processKey(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone), g, cm)
+	if cm.from != 0 || cm.to != 1 || cm.howmany != 2 || cur.held {
+		t.Errorf("Expected a move of 2 cards from 0 to 1 but was %v", cm)
+	}
+	moveCards(g, cm)
+	if len(stacks[1].Cards) != 3 {
+		t.Errorf("Expected the 8H and 7S on the 9S but was %d cards", len(stacks[1].Cards))
+	}
+	cm = processKey(tcell.NewEventKey(tcell.KeyLeft, 0, tcell.ModNone), g, move{from: -1, to: -1})
+	cm = processKey(tcell.NewEventKey(tcell.KeyLeft, 0, tcell.ModNone), g, cm)
+	if cur.pile != 11 {
+		t.Errorf("Expected the cursor to wrap to stack 11 but was %d", cur.pile)
+	}
+	cm = processKey(tcell.NewEventKey(tcell.KeyRune, 'q', tcell.ModNone), g, cm)
+	if !cm.quit {
+		t.Errorf("Expected the other keys to work in cursor mode")
 	}
 }
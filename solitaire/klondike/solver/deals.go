@@ -0,0 +1,239 @@
+package solver
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/tmasterson/cardgames/generic"
+	"github.com/tmasterson/cardgames/solitaire/klondike/engine"
+)
+
+// Difficulty is how hard a deal that can be won is to win.
+type Difficulty int
+
+// The difficulties from easiest to hardest.
+const (
+	Easy Difficulty = iota
+	Medium
+	Hard
+	Expert
+)
+
+// difficultyNames are the names of the difficulties in order.
+var difficultyNames = []string{"easy", "medium", "hard", "expert"}
+
+// bands are the highest scores for easy, medium and hard deals.
+var bands = []float64{220, 300, 1000}
+
+// ErrNotFound is returned when no deal of the difficulty asked for was found.
+var ErrNotFound = errors.New("no deal of that difficulty was found")
+
+// SearchTime is how long a game should wait for a deal to be found when the cache has none.
+const SearchTime = 5 * time.Second
+
+// String returns the name of the difficulty.
+func (d Difficulty) String() string {
+	if d < 0 || int(d) >= len(difficultyNames) {
+		return fmt.Sprintf("Difficulty(%d)", int(d))
+	}
+	return difficultyNames[d]
+}
+
+// ParseDifficulty returns the difficulty with a name.
+func ParseDifficulty(name string) (Difficulty, error) {
+	for i, n := range difficultyNames {
+		if n == strings.ToLower(name) {
+			return Difficulty(i), nil
+		}
+	}
+	return 0, fmt.Errorf("difficulty must be one of %s", strings.Join(difficultyNames, ", "))
+}
+
+// Score returns how hard the deal is.  It is the number of choices made along the way found,
+// the moves times the branching, made larger by how much searching it took to find it.
+func (r Rating) Score() float64 {
+	if r.Moves == 0 {
+		return 0
+	}
+	return float64(r.Moves) * r.Branching * math.Sqrt(float64(r.Nodes)/float64(r.Moves))
+}
+
+// Difficulty returns the band the deal's score falls in.  It only has a meaning for a deal that
+// was solved, one that was not may be impossible or just too hard for the solver.
+func (r Rating) Difficulty() Difficulty {
+	s := r.Score()
+	for i, b := range bands {
+		if s <= b {
+			return Difficulty(i)
+		}
+	}
+	return Expert
+}
+
+// Rate deals the game for a seed and rates it.
+func Rate(seed []byte, o engine.Options) Rating {
+	o.Source = generic.NewSeedSource(seed)
+	_, r := Solve(engine.NewGame(o), Limit)
+	return r
+}
+
+// Rated is a deal that has been solved, kept in the cache.
+// Seed: The seed of the deal in hex, as given to -seed
+// Draw, Passes, Build: The options it was rated for
+type Rated struct {
+	Seed   string `json:"seed"`
+	Draw   int    `json:"draw"`
+	Passes int    `json:"passes"`
+	Build  string `json:"build"`
+	Rating
+}
+
+// Source returns the shuffle source that deals the game.
+func (r Rated) Source() generic.Source {
+	seed, _ := hex.DecodeString(r.Seed)
+	return generic.NewSeedSource(seed)
+}
+
+// matches tells if the deal was rated for the options.
+func (r Rated) matches(o engine.Options) bool {
+	build := o.Build
+	if build == 0 {
+		build = 'T'
+	}
+	return r.Draw == o.Draw && r.Passes == o.Passes && r.Build == string(build)
+}
+
+// Cache is a file of deals that have been solved and rated but not yet played, so that a deal of
+// the difficulty wanted can usually be found without searching.
+// Rand: Where the seeds of new deals are read from, nil uses crypto/rand
+type Cache struct {
+	Path  string
+	Deals []Rated
+	Rand  io.Reader
+}
+
+// DefaultCachePath returns the file rated deals are kept in under the user's config directory.
+func DefaultCachePath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "cardgames", "klondike.deals"), nil
+}
+
+// LoadCache reads the rated deals in the file at path, one per line.
+// A missing file is an empty cache.
+func LoadCache(path string) (*Cache, error) {
+	c := &Cache{Path: path}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var r Rated
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		c.Deals = append(c.Deals, r)
+	}
+	return c, scanner.Err()
+}
+
+// Save writes the cache back to its file.  The directory is made if it is missing.
+func (c *Cache) Save() error {
+	if err := os.MkdirAll(filepath.Dir(c.Path), 0755); err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	for _, r := range c.Deals {
+		b, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		buf.Write(append(b, '\n'))
+	}
+	return ioutil.WriteFile(c.Path, buf.Bytes(), 0644)
+}
+
+// Take removes a deal of the difficulty for the options from the cache and returns it.
+//
+// returns: The deal and true, or false if the cache has none.
+func (c *Cache) Take(d Difficulty, o engine.Options) (Rated, bool) {
+	for i, r := range c.Deals {
+		if r.matches(o) && r.Difficulty() == d {
+			c.Deals = append(c.Deals[:i], c.Deals[i+1:]...)
+			return r, true
+		}
+	}
+	return Rated{}, false
+}
+
+// Search rates new random deals until it finds one of the difficulty for the options.
+// Every other deal that is solved on the way is added to the cache for later.
+// within: How long to keep trying, no new deal is started after it has passed
+//
+// returns: The deal, or ErrNotFound if none of the deals tried were of the difficulty.
+func (c *Cache) Search(d Difficulty, o engine.Options, within time.Duration) (Rated, error) {
+	build := o.Build
+	if build == 0 {
+		build = 'T'
+	}
+	seeds := c.Rand
+	if seeds == nil {
+		seeds = rand.Reader
+	}
+	for end := time.Now().Add(within); time.Now().Before(end); {
+		seed := make([]byte, 16)
+		if _, err := io.ReadFull(seeds, seed); err != nil {
+			return Rated{}, err
+		}
+		r := Rate(seed, o)
+		if !r.Solved {
+			continue
+		}
+		rated := Rated{Seed: hex.EncodeToString(seed), Draw: o.Draw, Passes: o.Passes, Build: string(build), Rating: r}
+		if r.Difficulty() == d {
+			return rated, nil
+		}
+		c.Deals = append(c.Deals, rated)
+	}
+	return Rated{}, ErrNotFound
+}
+
+// Deal returns a deal that can be won of the difficulty for the options, taken from the cache
+// in the file at path or found by searching.  The cache is saved afterwards.
+// within: How long to search if the cache has none, such as SearchTime
+func Deal(d Difficulty, o engine.Options, path string, within time.Duration) (Rated, error) {
+	c, err := LoadCache(path)
+	if err != nil {
+		return Rated{}, err
+	}
+	r, ok := c.Take(d, o)
+	if !ok {
+		r, err = c.Search(d, o, within)
+	}
+	if serr := c.Save(); err == nil {
+		err = serr
+	}
+	return r, err
+}
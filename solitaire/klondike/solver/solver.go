@@ -0,0 +1,143 @@
+// Package solver plays klondike deals through to find whether they can be won and how hard they
+// are, so that players can be dealt only games that can be won at the difficulty they ask for.
+package solver
+
+import (
+	"github.com/tmasterson/cardgames/generic"
+	"github.com/tmasterson/cardgames/solitaire"
+	"github.com/tmasterson/cardgames/solitaire/klondike/engine"
+)
+
+// Limit is the number of positions searched before a deal is given up on as too hard.
+const Limit = 100000
+
+// Rating is what the solver found out about a deal.
+// Solved: A way to win was found
+// Moves: The number of moves in the way found, including deals
+// Branching: The average number of moves worth trying in the positions along the way
+// Nodes: The number of positions searched
+type Rating struct {
+	Solved    bool    `json:"solved"`
+	Moves     int     `json:"moves"`
+	Branching float64 `json:"branching"`
+	Nodes     int     `json:"nodes"`
+}
+
+// search holds the state of a depth first search.
+type search struct {
+	seen    map[position]bool
+	nodes   int
+	limit   int
+	path    []engine.Move
+	choices int
+}
+
+// Solve searches for a way to win a game, looking at no more than limit positions.
+//
+// returns: The moves that win the game, or nil if none were found, and the rating of the deal.
+func Solve(g *engine.Game, limit int) ([]engine.Move, Rating) {
+	s := search{seen: make(map[position]bool), limit: limit}
+	if !s.solve(engine.Restore(g.View())) {
+		return nil, Rating{Nodes: s.nodes}
+	}
+	r := Rating{Solved: true, Moves: len(s.path), Nodes: s.nodes}
+	if len(s.path) > 0 {
+		r.Branching = float64(s.choices) / float64(len(s.path))
+	}
+	return s.path, r
+}
+
+// solve tries each move worth making in turn until the game is won.
+// The choices along the path are added up for the branching of the deal.
+func (s *search) solve(g *engine.Game) bool {
+	if g.IsWon() {
+		return true
+	}
+	if s.nodes >= s.limit {
+		return false
+	}
+	v := g.View()
+	k := key(v)
+	if s.seen[k] {
+		return false
+	}
+	s.seen[k] = true
+	s.nodes++
+	moves := candidates(g, v)
+	for _, m := range moves {
+		next := engine.Restore(v)
+		if next.Apply(m) != nil {
+			continue
+		}
+		s.path = append(s.path, m)
+		s.choices += len(moves)
+		if s.solve(next) {
+			return true
+		}
+		s.path = s.path[:len(s.path)-1]
+		s.choices -= len(moves)
+	}
+	return false
+}
+
+// tableau is the tableau columns, which can be swapped around without changing the game.
+var tableau = []int{0, 1, 2, 3, 4, 5, 6}
+
+// position tells positions apart for the search.  The passes only count when they are limited.
+type position struct {
+	hash uint64
+	pass int
+}
+
+// key returns the position of a game, the same for games that differ only in the number of
+// moves made or the order of the tableau columns.
+func key(v engine.View) position {
+	k := position{hash: solitaire.Hash(v.Stacks, v.Stock, tableau)}
+	if v.Passes > 0 {
+		k.pass = v.Pass
+	}
+	return k
+}
+
+// candidates returns the moves worth trying, most promising first.  A card that can safely
+// go to the aces is the only move tried.  Tableau moves are only tried when they turn a card
+// over, empty a column, or uncover a card that can go to the aces, as shuffling runs between
+// columns for any other reason gets nowhere.
+func candidates(g *engine.Game, v engine.View) []engine.Move {
+	if m, ok := g.AutoMove(true); ok {
+		return []engine.Move{m}
+	}
+	var aces, reveal, waste, other []engine.Move
+	for _, m := range g.LegalMoves() {
+		switch {
+		case m.Deal:
+		case m.To >= engine.FirstAce:
+			aces = append(aces, m)
+		case m.From == engine.Waste:
+			waste = append(waste, m)
+		default:
+			p := v.Stacks[m.From]
+			i := len(p.Cards) - m.Count
+			switch {
+			case i == p.Firstfaceup && i > 0:
+				reveal = append(reveal, m)
+			case i == 0 && p.Cards[0].Rvalue != 13:
+				other = append(other, m)
+			case i > p.Firstfaceup && toAces(v.Stacks, p.Cards[i-1]):
+				other = append(other, m)
+			}
+		}
+	}
+	moves := append(append(append(aces, reveal...), waste...), other...)
+	if len(v.Stock) > 0 || len(v.Stacks[engine.Waste].Cards) > 0 {
+		if !g.Over() {
+			moves = append(moves, engine.Move{Deal: true})
+		}
+	}
+	return moves
+}
+
+// toAces tells if a card can go on its ace stack.
+func toAces(stacks []solitaire.Pile, c generic.Card) bool {
+	return len(stacks[engine.AceStack(c.Suit)].Cards) == c.Rvalue-1
+}
@@ -0,0 +1,147 @@
+package solver
+
+import (
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/tmasterson/cardgames/generic"
+	"github.com/tmasterson/cardgames/solitaire"
+	"github.com/tmasterson/cardgames/solitaire/klondike/engine"
+)
+
+// suit returns the cards of a suit from low to high, face up.
+func suit(s string) []generic.Card {
+	colors := map[string]string{"S": "black", "H": "red", "D": "red", "C": "black"}
+	var cards []generic.Card
+	for i, r := range "A23456789TJQK" {
+		cards = append(cards, generic.NewCard(string(r), s, colors[s], i+1, 0, true))
+	}
+	return cards
+}
+
+// endgame returns a game with every suit but spades on the aces.  The three to king of spades
+// are in the first column and the ace and two in the second with the top given first.
+func endgame(top, under generic.Card) *engine.Game {
+	stacks := make([]solitaire.Pile, engine.StackCount)
+	for _, s := range []string{"H", "D", "C"} {
+		stacks[engine.AceStack(s)].Cards = suit(s)
+	}
+	spades := suit("S")
+	for i := len(spades) - 1; i >= 2; i-- {
+		stacks[0].Cards = append(stacks[0].Cards, spades[i])
+	}
+	stacks[1].Cards = []generic.Card{under, top}
+	deck := generic.NewDeck()
+	deck.Deal(52, 0)
+	return engine.NewGameFrom(engine.DefaultOptions, stacks, deck)
+}
+
+func TestSolve(t *testing.T) {
+	spades := suit("S")
+	moves, r := Solve(endgame(spades[0], spades[1]), Limit)
+	if !r.Solved || len(moves) != 13 || r.Moves != 13 || r.Branching != 1 {
+		t.Errorf("Expected the spades played to the aces in 13 moves but was %+v %v", r, moves)
+	}
+	moves, r = Solve(endgame(spades[1], spades[0]), Limit)
+	if r.Solved || moves != nil || r.Nodes != 1 {
+		t.Errorf("Expected the ace buried under the two not to be solved but was %+v", r)
+	}
+	o := engine.Options{Draw: 1, Build: 'T', Source: generic.NewSeedSource([]byte("solver"))}
+	g := engine.NewGame(o)
+	moves, r = Solve(g, Limit)
+	if !r.Solved || r.Branching < 1 || r.Nodes < r.Moves {
+		t.Fatalf("Expected the deal solved but was %+v", r)
+	}
+	for _, m := range moves {
+		if err := g.Apply(m); err != nil {
+			t.Fatalf("Expected %+v to be legal but was %v", m, err)
+		}
+	}
+	if !g.IsWon() {
+		t.Errorf("Expected the moves found to win the game")
+	}
+}
+
+func TestDifficulty(t *testing.T) {
+	for i, name := range []string{"easy", "Medium", "hard", "expert"} {
+		d, err := ParseDifficulty(name)
+		if err != nil || d != Difficulty(i) {
+			t.Errorf("Expected %s to be difficulty %d but was %v %v", name, i, d, err)
+		}
+	}
+	if _, err := ParseDifficulty("trivial"); err == nil {
+		t.Errorf("Expected an error for an unknown difficulty")
+	}
+	if Hard.String() != "hard" {
+		t.Errorf("Expected hard but was %s", Hard)
+	}
+	for _, c := range []struct {
+		r Rating
+		d Difficulty
+	}{
+		{Rating{Solved: true, Moves: 100, Branching: 1.5, Nodes: 100}, Easy},
+		{Rating{Solved: true, Moves: 100, Branching: 2.5, Nodes: 100}, Medium},
+		{Rating{Solved: true, Moves: 100, Branching: 2, Nodes: 1000}, Hard},
+		{Rating{Solved: true, Moves: 100, Branching: 2, Nodes: 10000}, Expert},
+	} {
+		if d := c.r.Difficulty(); d != c.d {
+			t.Errorf("Expected %+v to be %s but was %s", c.r, c.d, d)
+		}
+	}
+}
+
+func TestDeal(t *testing.T) {
+	dir, err := ioutil.TempDir("", "solver")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "sub", "klondike.deals")
+	o := engine.Options{Draw: 1, Build: 'T'}
+	c := &Cache{Path: path, Deals: []Rated{
+		{Seed: "01", Draw: 1, Build: "T", Rating: Rating{Solved: true, Moves: 100, Branching: 1.5, Nodes: 100}},
+		{Seed: "00", Draw: 1, Build: "T", Rating: Rating{Solved: true, Moves: 100, Branching: 2, Nodes: 10000}},
+	}}
+	if err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if r, err := Deal(Expert, o, path, 0); err != nil || r.Seed != "00" {
+		t.Errorf("Expected the cached expert deal but was %+v %v", r, err)
+	}
+	if c, _ = LoadCache(path); len(c.Deals) != 1 || c.Deals[0].Seed != "01" {
+		t.Errorf("Expected the deal taken from the cache but it had %+v", c.Deals)
+	}
+	if _, err = Deal(Expert, o, path, 0); err != ErrNotFound {
+		t.Errorf("Expected no expert deal left but was %v", err)
+	}
+	if _, err = Deal(Easy, engine.Options{Draw: 3, Passes: 3, Build: 'T'}, path, 0); err != ErrNotFound {
+		t.Errorf("Expected no deal for other options but was %v", err)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	o := engine.Options{Draw: 1, Build: 'T'}
+	c := &Cache{Rand: rand.New(rand.NewSource(12))}
+	r, err := c.Search(Easy, o, time.Minute)
+	if err != nil || !r.Solved || r.Difficulty() != Easy || len(r.Seed) != 32 {
+		t.Fatalf("Expected an easy deal but was %+v %v", r, err)
+	}
+	if again, _ := (&Cache{Rand: rand.New(rand.NewSource(12))}).Search(Easy, o, time.Minute); again.Seed != r.Seed {
+		t.Errorf("Expected the same seeds to find the same deal but was %s and %s", again.Seed, r.Seed)
+	}
+	if len(c.Deals) == 0 {
+		t.Errorf("Expected the other deals solved on the way kept")
+	}
+	for _, d := range c.Deals {
+		if !d.Solved || d.Difficulty() == Easy || !d.matches(o) {
+			t.Errorf("Expected only other solved deals kept but was %+v", d)
+		}
+	}
+	if _, err := c.Search(Easy, o, 0); err != ErrNotFound {
+		t.Errorf("Expected nothing searched with no time but was %v", err)
+	}
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/tmasterson/cardgames/generic"
+	"github.com/tmasterson/cardgames/solitaire"
+	"github.com/tmasterson/cardgames/solitaire/klondike/engine"
+	"github.com/tmasterson/cardgames/stats"
+)
+
+// The text front end reads a command a line and writes out what happens so the game can be played
+// with a screen reader.  It plays the same engine as the screen, only the input and output are
+// different.
+
+// TextHelp lists the commands for the text front end.
+const textHelp = `Commands:
+ag moves the face up cards of stack A to stack G.
+a2g moves the top 2 cards of stack A to stack G.
+wa moves from the waste to stack A.
+a ace or w ace moves the top card to its ace stack.
+deal deals from the stock to the waste.
+board describes every stack, a or w on its own describes one.
+finish moves every card to the aces once they are all face up.
+help shows this and quit stops.
+`
+
+// PileName returns the name of a stack as used in the text front end.
+func pileName(i int) string {
+	switch {
+	case i < engine.Columns:
+		return "stack " + string(rune('A'+i))
+	case i == engine.Waste:
+		return "the waste"
+	}
+	return "the " + generic.SuitName("SHDC"[i-8:i-7]) + " aces"
+}
+
+// DescribePile returns a line telling what is in a stack.
+// Face down cards are counted, or named in thoughtful mode, and only the top card of the waste
+// and of the aces are given.
+func describePile(stacks []solitaire.Pile, i int) string {
+	name := pileName(i)
+	name = strings.ToUpper(name[:1]) + name[1:]
+	p := stacks[i]
+	if len(p.Cards) == 0 {
+		return name + ": empty.\n"
+	}
+	switch {
+	case i < engine.Columns:
+		var cards []string
+		down := 0
+		for _, c := range p.Cards {
+			switch {
+			case c.Faceup:
+				cards = append(cards, c.Name())
+			case thoughtful:
+				cards = append(cards, "face down "+c.Name())
+			default:
+				down++
+			}
+		}
+		if down > 0 {
+			cards = append([]string{fmt.Sprintf("%d face down", down)}, cards...)
+		}
+		return name + ": " + strings.Join(cards, ", ") + ".\n"
+	case i == engine.Waste:
+		return fmt.Sprintf("%s: %s, %d cards.\n", name, p.Cards[p.Firstfaceup].Name(), len(p.Cards))
+	}
+	return name + ": up to the " + p.Cards[len(p.Cards)-1].Name() + ".\n"
+}
+
+// DescribeStock returns a line telling how many cards are left to deal and which pass it is.
+func describeStock(v engine.View) string {
+	str := fmt.Sprintf("Stock: %d cards", len(v.Stock))
+	if thoughtful && len(v.Stock) > 0 {
+		var cards []string
+		for _, c := range v.Stock {
+			cards = append(cards, c.Name())
+		}
+		str += ", " + strings.Join(cards, ", ")
+	}
+	if v.Passes > 0 {
+		return fmt.Sprintf("%s.  Pass %d of %d.\n", str, v.Pass+1, v.Passes)
+	}
+	return fmt.Sprintf("%s.  Pass %d.\n", str, v.Pass+1)
+}
+
+// Describe returns a description of the whole board.
+func describe(v engine.View) string {
+	var b strings.Builder
+	for i := range v.Stacks {
+		b.WriteString(describePile(v.Stacks, i))
+	}
+	b.WriteString(describeStock(v))
+	return b.String()
+}
+
+// ParseMove reads a move typed as the keys for it, such as ag, a2g or wa, or a stack followed by
+// the word ace.
+//
+// returns: The move or an error telling what is wrong with it.
+func parseMove(words []string, stacks []solitaire.Pile) (move, error) {
+	cm := move{from: -1, to: -1}
+	word := words[0]
+	switch {
+	case word[0] == 'w':
+		cm.from = engine.Waste
+	case word[0] >= 'a' && word[0] <= 'g':
+		cm.from = int(word[0] - 'a')
+	default:
+		return cm, fmt.Errorf("%s is not a command, type help for the commands", word)
+	}
+	word = word[1:]
+	for len(word) > 0 && word[0] >= '0' && word[0] <= '9' {
+		cm.howmany = cm.howmany*10 + int(word[0]-'0')
+		word = word[1:]
+	}
+	switch {
+	case len(words) == 2 && words[1] == "ace" && word == "":
+		p := stacks[cm.from]
+		if len(p.Cards) == 0 {
+			return cm, errors.New("There is no card to move.")
+		}
+		cm.to = engine.AceStack(p.Cards[len(p.Cards)-1].Suit)
+		cm.howmany = 1
+	case len(words) == 1 && len(word) == 1 && word[0] >= 'a' && word[0] <= 'g':
+		cm.to = int(word[0] - 'a')
+	default:
+		return cm, errors.New("A move is two stacks such as ag, or a stack and ace such as w ace.")
+	}
+	return cm, nil
+}
+
+// ApplyMove makes a move and returns what happened.
+func applyMove(g *engine.Game, m engine.Move) string {
+	before := len(g.View().Stacks[m.To].Cards)
+	if err := g.Apply(m); err != nil {
+		return "That move is not allowed.\n"
+	}
+	stacks := g.View().Stacks
+	to := stacks[m.To].Cards
+	str := fmt.Sprintf("Moved the %s", to[before].Name())
+	if n := len(to) - before; n > 1 {
+		str += fmt.Sprintf(" and %d more", n-1)
+	}
+	str += fmt.Sprintf(" from %s to %s.\n", pileName(m.From), pileName(m.To))
+	return str + describePile(stacks, m.From)
+}
+
+// PlayText plays a game reading commands from in and writing to out.
+//
+// in: Where the commands are read from, a line each.
+// out: Where the board and the result of each command are written.
+// g: The game to play.
+//
+// returns: -1 if the game was won, otherwise the number of passes, the same as playGame.
+func playText(in io.Reader, out io.Writer, g *engine.Game) int {
+	if dealHash != "" {
+		fmt.Fprintf(out, "Deal hash %s\n", dealHash)
+	}
+	fmt.Fprintf(out, "Klondike dealing %d at a time.  Type help for the commands.\n", g.View().Draw)
+	fmt.Fprint(out, describe(g.View()))
+	clock.Start()
+	defer clock.Pause()
+	scanner := bufio.NewScanner(in)
+	for !g.Over() {
+		fmt.Fprint(out, "> ")
+		if !scanner.Scan() {
+			break
+		}
+		words := strings.Fields(strings.ToLower(scanner.Text()))
+		if len(words) == 0 {
+			continue
+		}
+		switch words[0] {
+		case "quit":
+			return g.View().Pass
+		case "help":
+			fmt.Fprint(out, textHelp)
+		case "board":
+			fmt.Fprint(out, describe(g.View()))
+		case "deal":
+			g.Apply(engine.Move{Deal: true})
+			v := g.View()
+			fmt.Fprint(out, describePile(v.Stacks, engine.Waste))
+			fmt.Fprint(out, describeStock(v))
+		case "finish":
+			if !g.CanFinish() {
+				fmt.Fprintln(out, "The stock must be dealt and every card face up to finish.")
+				break
+			}
+			for m, ok := g.AutoMove(false); ok; m, ok = g.AutoMove(false) {
+				fmt.Fprint(out, applyMove(g, m))
+			}
+		default:
+			if len(words) == 1 && len(words[0]) == 1 {
+				if i := strings.Index("abcdefgw", words[0]); i >= 0 {
+					fmt.Fprint(out, describePile(g.View().Stacks, i))
+					break
+				}
+			}
+			cm, err := parseMove(words, g.View().Stacks)
+			if err != nil {
+				fmt.Fprintln(out, err)
+				break
+			}
+			fmt.Fprint(out, applyMove(g, engine.Move{From: cm.from, To: cm.to, Count: cm.howmany}))
+		}
+		if autoplay {
+			for m, ok := g.AutoMove(true); ok; m, ok = g.AutoMove(true) {
+				fmt.Fprint(out, applyMove(g, m))
+			}
+		}
+		if g.IsWon() {
+			fmt.Fprintf(out, "All the cards are on the aces in %d moves and %s.\n", g.View().Moves, stats.Format(clock.Elapsed()))
+			return -1
+		}
+	}
+	if g.Over() {
+		fmt.Fprintln(out, "There are no passes left.")
+	}
+	return g.View().Pass
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tmasterson/cardgames/generic"
+	"github.com/tmasterson/cardgames/solitaire"
+	"github.com/tmasterson/cardgames/solitaire/klondike/engine"
+)
+
+func TestDescribePile(t *testing.T) {
+	stacks := make([]solitaire.Pile, 12)
+	stacks[0].Cards = []generic.Card{
+		generic.NewCard("K", "S", "black", 13, 16, false),
+		generic.NewCard("9", "H", "red", 9, 8, true),
+	}
+	stacks[0].Firstfaceup = 1
+	if s := describePile(stacks, 0); s != "Stack A: 1 face down, nine of hearts.\n" {
+		t.Errorf("Expected one face down and the nine of hearts but was %q", s)
+	}
+	thoughtful = true
+	if s := describePile(stacks, 0); !strings.Contains(s, "face down king of spades") {
+		t.Errorf("Expected the face down king named in thoughtful mode but was %q", s)
+	}
+	thoughtful = false
+	if s := describePile(stacks, 9); s != "The hearts aces: empty.\n" {
+		t.Errorf("Expected the empty hearts aces but was %q", s)
+	}
+	if s := describePile(stacks, 7); s != "The waste: empty.\n" {
+		t.Errorf("Expected the empty waste but was %q", s)
+	}
+}
+
+func TestParseMove(t *testing.T) {
+	stacks := make([]solitaire.Pile, 12)
+	stacks[7].Cards = []generic.Card{generic.NewCard("A", "D", "red", 1, 4, true)}
+	cm, err := parseMove([]string{"a2g"}, stacks)
+	if err != nil || cm.from != 0 || cm.to != 6 || cm.howmany != 2 {
+		t.Errorf("Expected 2 cards from 0 to 6 but was %v %v", cm, err)
+	}
+	cm, err = parseMove([]string{"w", "ace"}, stacks)
+	if err != nil || cm.from != 7 || cm.to != 10 {
+		t.Errorf("Expected the waste to the diamonds but was %v %v", cm, err)
+	}
+	for _, words := range [][]string{{"xyz"}, {"a", "ace"}, {"ah"}, {"agg"}} {
+		if _, err := parseMove(words, stacks); err == nil {
+			t.Errorf("Expected %v to be an error", words)
+		}
+	}
+}
+
+func TestPlayText(t *testing.T) {
+	stacks := make([]solitaire.Pile, 12)
+	for i := range stacks {
+		stacks[i].Ptype = 'T'
+	}
+	stacks[7].Ptype = 'W'
+	for i := 8; i < 12; i++ {
+		stacks[i].Ptype = 'A'
+	}
+	for i, suit := range []string{"S", "H", "D", "C"} {
+		color := "black"
+		if suit == "H" || suit == "D" {
+			color = "red"
+		}
+		for r := 1; r <= 13; r++ {
+			stacks[8+i].Cards = append(stacks[8+i].Cards, generic.NewCard("A", suit, color, r, 0, true))
+		}
+	}
+	last := stacks[11].Cards[12]
+	stacks[11].Cards = stacks[11].Cards[:12]
+	last.Rank = "K"
+	stacks[0].Cards = []generic.Card{last}
+	stacks[1].Cards = []generic.Card{generic.NewCard("2", "H", "red", 2, 8, true)}
+	deck := generic.NewDeck()
+	deck.AllDealt = true
+	deck.LastDealt = len(deck.Cards)
+	var out strings.Builder
+	in := strings.NewReader("help\nnonsense\nab\na ace\n")
+	g := engine.NewGameFrom(engine.DefaultOptions, stacks, deck)
+	if st := playText(in, &out, g); st != -1 {
+		t.Errorf("Expected the game won but was %d\n%s", st, out.String())
+	}
+	text := out.String()
+	for _, want := range []string{"Commands:", "nonsense is not a command", "That move is not allowed", "Moved the king of clubs from stack A to the clubs aces", "All the cards are on the aces in 1 moves"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in the output but was\n%s", want, text)
+		}
+	}
+	in = strings.NewReader("quit\n")
+	if st := playText(in, &out, g); st != 0 {
+		t.Errorf("Expected quit to stop on pass 0 but was %d", st)
+	}
+}
@@ -0,0 +1,126 @@
+package solitaire
+
+import (
+	"github.com/tmasterson/cardgames/generic"
+)
+
+// Pos is where a card in a Layout is shown.
+// Row counts down from the top and Col is in half card widths so overlapping rows line up.
+type Pos struct {
+	Row, Col int
+}
+
+// Layout holds cards laid out overlapping each other such as in pyramid or tripeaks.
+// A card can only be played once every card covering it has been removed.
+// Covers[i] lists the positions of the cards that cover position i.
+type Layout struct {
+	Cards   []generic.Card
+	Removed []bool
+	Covers  [][]int
+	Pos     []Pos
+}
+
+// NewPyramid deals 28 cards face up into a pyramid of seven rows.
+// Each card is covered by the two cards below it in the next row.
+func NewPyramid(deck *generic.Deck) Layout {
+	var l Layout
+	for r := 0; r < 7; r++ {
+		for c := 0; c <= r; c++ {
+			l.Pos = append(l.Pos, Pos{Row: r, Col: 6 - r + 2*c})
+			var covers []int
+			if r < 6 {
+				below := (r+1)*(r+2)/2 + c
+				covers = []int{below, below + 1}
+			}
+			l.Covers = append(l.Covers, covers)
+		}
+	}
+	l.Cards = deck.Deal(len(l.Pos), len(l.Pos))
+	l.Removed = make([]bool, len(l.Cards))
+	return l
+}
+
+// NewTriPeaks deals 28 cards into three peaks over a bottom row of ten.
+// Only the bottom row is dealt face up, the others are turned up as they are uncovered.
+// Positions 0 to 2 are the tops of the peaks.
+func NewTriPeaks(deck *generic.Deck) Layout {
+	var l Layout
+	for p := 0; p < 3; p++ {
+		l.Pos = append(l.Pos, Pos{Row: 0, Col: 6*p + 3})
+		l.Covers = append(l.Covers, []int{3 + 2*p, 4 + 2*p})
+	}
+	for p := 0; p < 3; p++ {
+		for k := 0; k < 2; k++ {
+			l.Pos = append(l.Pos, Pos{Row: 1, Col: 6*p + 2 + 2*k})
+			below := 9 + 3*p + k
+			l.Covers = append(l.Covers, []int{below, below + 1})
+		}
+	}
+	for j := 0; j < 9; j++ {
+		l.Pos = append(l.Pos, Pos{Row: 2, Col: 2*j + 1})
+		l.Covers = append(l.Covers, []int{18 + j, 19 + j})
+	}
+	for j := 0; j < 10; j++ {
+		l.Pos = append(l.Pos, Pos{Row: 3, Col: 2 * j})
+		l.Covers = append(l.Covers, nil)
+	}
+	l.Cards = deck.Deal(len(l.Pos), 10)
+	l.Removed = make([]bool, len(l.Cards))
+	return l
+}
+
+// Free tells if the card at position i is still in the layout and uncovered.
+func (l *Layout) Free(i int) bool {
+	if i < 0 || i >= len(l.Cards) || l.Removed[i] {
+		return false
+	}
+	for _, j := range l.Covers[i] {
+		if !l.Removed[j] {
+			return false
+		}
+	}
+	return true
+}
+
+// Remove takes the card at position i out of the layout.
+// Any face down cards that are uncovered by this are turned face up.
+func (l *Layout) Remove(i int) {
+	l.Removed[i] = true
+	for j := range l.Cards {
+		if !l.Cards[j].Faceup && l.Free(j) {
+			l.Cards[j].Turn()
+		}
+	}
+}
+
+// FreeCards returns the positions of all the cards that can be played.
+func (l *Layout) FreeCards() []int {
+	var free []int
+	for i := range l.Cards {
+		if l.Free(i) {
+			free = append(free, i)
+		}
+	}
+	return free
+}
+
+// Find returns the position of the free card with the given rank and suit or -1 if there is none.
+func (l *Layout) Find(rank, suit string) int {
+	for _, i := range l.FreeCards() {
+		if l.Cards[i].Rank == rank && l.Cards[i].Suit == suit {
+			return i
+		}
+	}
+	return -1
+}
+
+// Remaining returns the number of cards still in the layout.
+func (l *Layout) Remaining() int {
+	n := 0
+	for _, r := range l.Removed {
+		if !r {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,83 @@
+package solitaire
+
+import (
+	"testing"
+
+	"github.com/tmasterson/cardgames/generic"
+)
+
+func TestNewPyramid(t *testing.T) {
+	deck := generic.NewDeck()
+	l := NewPyramid(&deck)
+	if len(l.Cards) != 28 || deck.LastDealt != 28 {
+		t.Fatalf("expected 28 cards but was %d", len(l.Cards))
+	}
+	if l.Pos[0].Col != 6 || l.Pos[27].Row != 6 || l.Pos[27].Col != 12 {
+		t.Errorf("positions were wrong %v %v", l.Pos[0], l.Pos[27])
+	}
+	if len(l.Covers[0]) != 2 || l.Covers[0][0] != 1 || l.Covers[0][1] != 2 {
+		t.Errorf("expected the top card covered by 1 and 2 but was %v", l.Covers[0])
+	}
+	if len(l.FreeCards()) != 7 {
+		t.Errorf("expected 7 free cards but was %d", len(l.FreeCards()))
+	}
+}
+
+func TestLayoutRemove(t *testing.T) {
+	deck := generic.NewDeck()
+	l := NewPyramid(&deck)
+	// position 20 in the sixth row is covered by 26 and 27
+	if l.Free(20) {
+		t.Errorf("position 20 should be covered")
+	}
+	l.Remove(26)
+	if l.Free(20) {
+		t.Errorf("position 20 should still be covered by 27")
+	}
+	l.Remove(27)
+	if !l.Free(20) || l.Free(26) {
+		t.Errorf("position 20 should be free and 26 gone")
+	}
+	if l.Remaining() != 26 {
+		t.Errorf("expected 26 cards left but was %d", l.Remaining())
+	}
+	c := l.Cards[20]
+	if l.Find(c.Rank, c.Suit) != 20 || l.Find(l.Cards[0].Rank, l.Cards[0].Suit) != -1 {
+		t.Errorf("Find should only return free cards")
+	}
+	l.Cards[14].Faceup = false
+	l.Remove(19)
+	l.Remove(20)
+	if !l.Cards[14].Faceup {
+		t.Errorf("expected an uncovered card to be turned face up")
+	}
+}
+
+func TestNewTriPeaks(t *testing.T) {
+	deck := generic.NewDeck()
+	l := NewTriPeaks(&deck)
+	if len(l.Cards) != 28 || deck.LastDealt != 28 {
+		t.Fatalf("expected 28 cards but was %d", len(l.Cards))
+	}
+	free := l.FreeCards()
+	if len(free) != 10 || free[0] != 18 {
+		t.Errorf("expected the bottom row of 10 free but was %v", free)
+	}
+	for i, c := range l.Cards {
+		if c.Faceup != (i >= 18) {
+			t.Errorf("expected only the bottom row face up but %d was %v", i, c.Faceup)
+		}
+	}
+	// the first peak is 0 over 3 and 4 over 9, 10 and 11 over 18 to 21
+	for _, i := range []int{18, 19, 20, 21, 9, 10, 11} {
+		l.Remove(i)
+	}
+	if !l.Free(3) || !l.Free(4) || l.Free(0) || !l.Cards[3].Faceup || l.Free(5) {
+		t.Errorf("expected the first peak uncovered down to 3 and 4")
+	}
+	l.Remove(3)
+	l.Remove(4)
+	if !l.Free(0) || !l.Cards[0].Faceup {
+		t.Errorf("expected the top of the first peak free")
+	}
+}
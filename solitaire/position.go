@@ -0,0 +1,174 @@
+package solitaire
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sort"
+	"strings"
+
+	"github.com/tmasterson/cardgames/generic"
+)
+
+// cardRanks and cardSuits give the order cards are numbered in for encoding.
+const (
+	cardRanks = "A23456789TJQK"
+	cardSuits = "SHDC"
+)
+
+// cardCode returns a number from 0 to 51 for a card, or 255 for one that is not in a deck.
+// Aces are numbered the same whether they are played high or low.
+func cardCode(c generic.Card) byte {
+	r := strings.Index(cardRanks, c.Rank)
+	s := strings.Index(cardSuits, c.Suit)
+	if r < 0 || s < 0 || len(c.Rank) != 1 || len(c.Suit) != 1 {
+		return 255
+	}
+	return byte(r*4 + s)
+}
+
+// encodePile returns a pile as its number of cards and the index of its first face up card, each
+// a uvarint so there is no limit on the size of a pile, and then a byte for each card.  A first
+// face up index below 0 is taken as 0 and one past the top as every card face down, so piles
+// that show the same are encoded the same.
+func encodePile(p Pile) []byte {
+	first := p.Firstfaceup
+	if first < 0 {
+		first = 0
+	}
+	if first > len(p.Cards) {
+		first = len(p.Cards)
+	}
+	b := make([]byte, 0, len(p.Cards)+2*binary.MaxVarintLen64)
+	b = appendUvarint(b, uint64(len(p.Cards)))
+	b = appendUvarint(b, uint64(first))
+	for _, c := range p.Cards {
+		b = append(b, cardCode(c))
+	}
+	return b
+}
+
+// appendUvarint appends a number as a uvarint.
+func appendUvarint(b []byte, n uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(b, buf[:binary.PutUvarint(buf[:], n)]...)
+}
+
+// decodePile returns the index of the first face up card and the card codes of an encoded pile.
+func decodePile(e []byte) (int, []byte) {
+	_, n := binary.Uvarint(e)
+	first, m := binary.Uvarint(e[n:])
+	return int(first), e[n+m:]
+}
+
+// canonical returns the encoded piles with the piles in each group of interchangeable piles put
+// in order, so that positions which differ only in the order of those piles are the same.
+func canonical(piles []Pile, interchangeable [][]int) [][]byte {
+	enc := make([][]byte, len(piles))
+	for i, p := range piles {
+		enc[i] = encodePile(p)
+	}
+	sorted := make([][]byte, len(piles))
+	for i, j := range slots(enc, interchangeable) {
+		sorted[j] = enc[i]
+	}
+	return sorted
+}
+
+// slots returns the place each pile takes in the canonical form, which is where it is unless it
+// is in a group of interchangeable piles, whose places are shared out in the order of their encodings.
+func slots(enc [][]byte, interchangeable [][]int) []int {
+	slot := make([]int, len(enc))
+	for i := range slot {
+		slot[i] = i
+	}
+	for _, group := range interchangeable {
+		order := append([]int(nil), group...)
+		sort.SliceStable(order, func(a, b int) bool { return bytes.Compare(enc[order[a]], enc[order[b]]) < 0 })
+		for i, j := range order {
+			slot[j] = group[i]
+		}
+	}
+	return slot
+}
+
+// Encode returns a position as a compact string of bytes.  Each pile is its number of cards,
+// the index of its first face up card and a byte for each card, then the stock follows the same
+// way with its cards in the order they will be dealt.  The counts and indexes are uvarints, a
+// single byte for piles of fewer than 128 cards.
+//
+// piles: The piles of the game
+// stock: The cards left to deal
+// interchangeable: Groups of pile indexes whose order does not matter to the game, such as the
+// tableau of freecell or klondike.  The piles in a group are sorted so positions that differ
+// only by swapping them are encoded the same.
+func Encode(piles []Pile, stock []generic.Card, interchangeable ...[]int) []byte {
+	var b []byte
+	for _, e := range canonical(piles, interchangeable) {
+		b = append(b, e...)
+	}
+	return append(b, encodePile(Pile{Cards: stock})...)
+}
+
+// zobrist returns the random number for a card at a place in a position.  The numbers are made
+// by mixing the bits of the place with the splitmix64 finalizer so no table of them is needed.
+// slot: The index of the pile, or the number of piles for the stock
+// depth: The card's index in the pile
+// c: The card, which counts as a different card face up and face down
+func zobrist(slot, depth int, c generic.Card) uint64 {
+	z := uint64(slot)<<24 | uint64(depth)<<9 | uint64(cardCode(c))<<1
+	if c.Faceup {
+		z |= 1
+	}
+	z += 0x9e3779b97f4a7c15
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+// Hash returns a 64 bit Zobrist hash of a position, the exclusive or of a random number for each
+// card, face up or down, at its place.  The piles in a group of interchangeable piles take their
+// places in the same canonical order as Encode, so positions that encode the same hash the same.
+// Different positions almost always hash differently.  Without interchangeable piles the hash
+// can be kept up to date a move at a time with MoveHash.
+func Hash(piles []Pile, stock []generic.Card, interchangeable ...[]int) uint64 {
+	var slot []int
+	if len(interchangeable) > 0 {
+		enc := make([][]byte, len(piles))
+		for i, p := range piles {
+			enc[i] = encodePile(p)
+		}
+		slot = slots(enc, interchangeable)
+	}
+	var h uint64
+	for i, p := range piles {
+		at := i
+		if slot != nil {
+			at = slot[i]
+		}
+		for depth, c := range p.Cards {
+			h ^= zobrist(at, depth, c)
+		}
+	}
+	for depth, c := range stock {
+		h ^= zobrist(len(piles), depth, c)
+	}
+	return h
+}
+
+// MoveHash returns the Hash h of a position, taken without interchangeable piles, updated for
+// the move DoMove makes of the cards from index up in pile from onto pile to, including the card
+// it turns over.  It is called before the move is made and only looks at the cards that change.
+func MoveHash(h uint64, piles []Pile, from, to, index int) uint64 {
+	p, q := &piles[from], &piles[to]
+	for i, c := range p.Cards[index:] {
+		h ^= zobrist(from, index+i, c) ^ zobrist(to, len(q.Cards)+i, c)
+	}
+	if index <= p.Firstfaceup && p.Firstfaceup > 0 && p.Firstfaceup-1 < index {
+		turned := p.Firstfaceup - 1
+		c := p.Cards[turned]
+		h ^= zobrist(from, turned, c)
+		c.Turn()
+		h ^= zobrist(from, turned, c)
+	}
+	return h
+}
@@ -0,0 +1,84 @@
+package solitaire
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/tmasterson/cardgames/generic"
+)
+
+// position returns three piles, the first two with a face down card under a face up one,
+// and a stock of two cards.
+func position() ([]Pile, []generic.Card) {
+	deck := generic.NewDeck()
+	piles := make([]Pile, 3)
+	for i := 0; i < 2; i++ {
+		piles[i].Cards = deck.Deal(2, 1)
+		piles[i].Firstfaceup = 1
+	}
+	return piles, deck.Deal(2, 0)
+}
+
+func TestEncode(t *testing.T) {
+	piles, stock := position()
+	// 2H 2D, 2C 2S, nothing, then the stock 3H 3D
+	want := []byte{2, 1, 5, 6, 2, 1, 7, 4, 0, 0, 2, 0, 9, 10}
+	if b := Encode(piles, stock); !bytes.Equal(b, want) {
+		t.Errorf("Expected %v but was %v", want, b)
+	}
+	swapped := []Pile{piles[1], piles[0], piles[2]}
+	if bytes.Equal(Encode(piles, stock), Encode(swapped, stock)) || Hash(piles, stock) == Hash(swapped, stock) {
+		t.Errorf("Expected swapped piles to be a different position")
+	}
+	group := []int{0, 1, 2}
+	if !bytes.Equal(Encode(piles, stock, group), Encode(swapped, stock, group)) || Hash(piles, stock, group) != Hash(swapped, stock, group) {
+		t.Errorf("Expected swapped interchangeable piles to be the same position")
+	}
+	h := Hash(piles, stock)
+	piles[0].Cards[0].Turn()
+	if Hash(piles, stock) == h {
+		t.Errorf("Expected turning a card over to change the hash")
+	}
+	piles[0].Cards[0].Turn()
+	if Hash(piles, []generic.Card{stock[1], stock[0]}) == h {
+		t.Errorf("Expected the order of the stock to change the hash")
+	}
+	if Hash(piles, stock) != h {
+		t.Errorf("Expected the same position to hash the same")
+	}
+	piles[2].Firstfaceup = -1
+	if Hash(piles, stock) != h || !bytes.Equal(Encode(piles, stock), want) {
+		t.Errorf("Expected a first face up of -1 on an empty pile to be the same as 0")
+	}
+	big := Pile{Cards: make([]generic.Card, 300), Firstfaceup: 299}
+	if b := Encode([]Pile{big}, nil); !bytes.Equal(b[:4], []byte{0xac, 0x02, 0xab, 0x02}) || len(b) != 306 {
+		t.Errorf("Expected a pile of 300 cards to be encoded in full but was %v", b[:4])
+	}
+	h = Hash([]Pile{big}, nil)
+	big.Cards[299].Turn()
+	if Hash([]Pile{big}, nil) == h {
+		t.Errorf("Expected the top card of a pile of 300 to be face up or down")
+	}
+	ace := generic.NewCard("A", "S", "black", 14, 16, true)
+	low := ace
+	low.Rvalue = 1
+	if cardCode(ace) != 0 || cardCode(low) != 0 || cardCode(generic.Card{}) != 255 {
+		t.Errorf("Expected the ace of spades to be 0 played high or low")
+	}
+}
+
+func TestMoveHash(t *testing.T) {
+	piles, stock := position()
+	// the 2S onto the 2D turning the 2C, the 2D and 2S to the empty pile turning the 2H, then the 2C
+	// onto the 2H
+	for _, m := range []struct{ from, to, index int }{{1, 0, 1}, {0, 2, 1}, {1, 0, 0}} {
+		h := MoveHash(Hash(piles, stock), piles, m.from, m.to, m.index)
+		piles[m.from].DoMove(&piles[m.to], m.index)
+		if fresh := Hash(piles, stock); h != fresh {
+			t.Errorf("Expected the hash after moving %v to be %x but was %x", m, fresh, h)
+		}
+	}
+	if !piles[0].Cards[0].Faceup {
+		t.Errorf("Expected the 2H turned over")
+	}
+}
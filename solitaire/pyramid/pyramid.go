@@ -0,0 +1,357 @@
+// This program allows a user to play the game pyramid
+// It uses the ncurses tcell created by Garrett D'Amore which can be gotten by
+// go get -u github.com/gdamore/tcell
+//
+// 28 cards are dealt into a pyramid where each card is covered by the two below it.
+// Pairs of uncovered cards adding up to 13 are removed, kings on their own, using the
+// top of the waste as well.  The game is won when the pyramid is cleared.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gdamore/tcell"
+	"github.com/tmasterson/cardgames/display"
+	"github.com/tmasterson/cardgames/generic"
+	"github.com/tmasterson/cardgames/solitaire"
+	"github.com/tmasterson/cardgames/stats"
+)
+
+// WastePos is used in a selection for the top card of the waste.
+const wastePos = -1
+
+// Game holds everything about a game of pyramid.
+// recycles:  How many times the waste may be turned over into the stock.
+// pass:  How many times it has been turned over.
+// selected:  A card chosen to be paired, nil if none is.
+// typed:  The rank typed so far when choosing a card.
+type game struct {
+	layout   solitaire.Layout
+	waste    solitaire.Pile
+	deck     generic.Deck
+	recycles int
+	pass     int
+	moves    int
+	selected *int
+	typed    string
+	msg      string
+}
+
+// Define the boxes for easier manipulation
+var playArea, wasteArea display.Box
+
+// NewGame deals a new game.
+func newGame(deck generic.Deck, recycles int) *game {
+	// Make aces low card instead of high card
+	for i := range deck.Cards {
+		if deck.Cards[i].Rvalue == 14 {
+			deck.Cards[i].Rvalue = 1
+		}
+	}
+	g := &game{deck: deck, recycles: recycles}
+	g.layout = solitaire.NewPyramid(&g.deck)
+	g.waste.Ptype = 'W'
+	return g
+}
+
+// Card returns the card at a position which may be the waste.
+func (g *game) card(pos int) generic.Card {
+	if pos == wastePos {
+		return g.waste.Cards[len(g.waste.Cards)-1]
+	}
+	return g.layout.Cards[pos]
+}
+
+// Remove takes the card at a position out of play.
+func (g *game) remove(pos int) {
+	if pos != wastePos {
+		g.layout.Remove(pos)
+		return
+	}
+	// the waste works like a tableau when a card is taken off the top
+	g.waste.Reduce(len(g.waste.Cards) - 1)
+	g.waste.ChangeFirstFaceUp()
+}
+
+// Find returns the position of a playable card by rank and suit.
+func (g *game) find(rank, suit string) (int, error) {
+	if n := len(g.waste.Cards); n > 0 && g.waste.Cards[n-1].Rank == rank && g.waste.Cards[n-1].Suit == suit {
+		return wastePos, nil
+	}
+	if i := g.layout.Find(rank, suit); i >= 0 {
+		return i, nil
+	}
+	return 0, fmt.Errorf("%s%s is not available", rank, suit)
+}
+
+// Choose selects the card at pos.  Kings are removed at once, otherwise the card is held
+// until a second card is chosen and the pair removed if it adds to 13.
+func (g *game) choose(pos int) error {
+	c := g.card(pos)
+	if c.Rvalue == 13 {
+		g.remove(pos)
+		g.selected = nil
+		g.moves++
+		return nil
+	}
+	if g.selected == nil {
+		g.selected = &pos
+		g.msg = "Selected " + c.Rank + c.Suit
+		return nil
+	}
+	first := *g.selected
+	g.selected = nil
+	if first == pos {
+		g.msg = ""
+		return nil
+	}
+	other := g.card(first)
+	if c.Rvalue+other.Rvalue != 13 {
+		return fmt.Errorf("%s and %s do not add up to 13", other.Rank+other.Suit, c.Rank+c.Suit)
+	}
+	// remove the waste last so the positions in the layout are still good
+	if pos == wastePos {
+		pos, first = first, pos
+	}
+	g.remove(pos)
+	g.remove(first)
+	g.moves++
+	g.msg = ""
+	return nil
+}
+
+// Deal turns the next card from the stock onto the waste.
+// The waste is turned over once the stock is empty if recycles are left.
+func (g *game) deal() error {
+	if g.deck.AllDealt && g.pass >= g.recycles {
+		return errors.New("no more passes through the stock")
+	}
+	g.selected = nil
+	g.pass = solitaire.DealToWaste(&g.waste, &g.deck, 1, g.pass)
+	g.moves++
+	return nil
+}
+
+// HasMove tells if any pair or king can be removed or the stock dealt.
+func (g *game) hasMove() bool {
+	if !g.deck.AllDealt || g.pass < g.recycles {
+		return true
+	}
+	free := g.layout.FreeCards()
+	if len(g.waste.Cards) > 0 {
+		free = append(free, wastePos)
+	}
+	for i, a := range free {
+		if g.card(a).Rvalue == 13 {
+			return true
+		}
+		for _, b := range free[i+1:] {
+			if g.card(a).Rvalue+g.card(b).Rvalue == 13 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// DrawScreen draws the screen putting all the boxes in place
+// s: The screen variable
+// style: The style for the screen
+//
+// Returns: Returns an error if one occurs otherwise nil
+func drawScreen(s tcell.Screen, style tcell.Style) error {
+	w, h := s.Size()
+	if w < 80 || h < 25 {
+		return errors.New("Screen size must be at least 80 by 25")
+	}
+	title := "Pyramid"
+	display.PutString(s, w/2-len(title)/2, 0, style, title)
+	var err error
+	playArea, err = display.MakeBox(s, "Pyramid", 0, 2, 32, 18, style)
+	if err != nil {
+		return err
+	}
+	wasteArea, err = display.MakeBox(s, "Waste", playArea.RightX+3, 2, playArea.RightX+13, 4, style)
+	if err != nil {
+		return err
+	}
+	x := wasteArea.LeftX
+	y := wasteArea.BotY + 2
+	help := []string{
+		"Type a card such as 5H to choose it.",
+		"Choose two cards adding to 13 to",
+		"remove them, a king goes on its own.",
+		"J is 11, Q is 12 and K is 13.",
+		"W chooses the top of the waste.",
+		"Space deals a card to the waste.",
+		"Escape clears the choice, X quits.",
+	}
+	for _, line := range help {
+		display.PutString(s, x, y, style, line)
+		y++
+	}
+	s.Show()
+	return nil
+}
+
+// ShowGame prints the pyramid, the waste and the status line.
+// s: Screen variable
+// g: The game
+// style: The style for the cards
+func showGame(s tcell.Screen, g *game, style tcell.Style) {
+	w, h := s.Size()
+	blank := strings.Repeat(" ", playArea.RightX-playArea.LeftX-1)
+	for y := playArea.TopY + 1; y < playArea.BotY; y++ {
+		display.PutString(s, playArea.LeftX+1, y, style, blank)
+	}
+	for i, c := range g.layout.Cards {
+		if g.layout.Removed[i] {
+			continue
+		}
+		p := g.layout.Pos[i]
+		cs := style
+		if g.selected != nil && *g.selected == i {
+			cs = cs.Reverse(true)
+		}
+		display.PutString(s, playArea.LeftX+3+p.Col*2, playArea.TopY+2+p.Row*2, cs, c.Rank+c.Suit)
+	}
+	str := "  "
+	if n := len(g.waste.Cards); n > 0 {
+		str = g.waste.Cards[n-1].Rank + g.waste.Cards[n-1].Suit
+	}
+	ws := style
+	if g.selected != nil && *g.selected == wastePos {
+		ws = ws.Reverse(true)
+	}
+	display.PutString(s, wasteArea.CardArea, wasteArea.TopY+1, ws, str)
+	display.PutString(s, 0, h-2, style, strings.Repeat(" ", w-1))
+	display.PutString(s, 0, h-2, style, g.msg)
+	display.PutString(s, 0, h-1, style, strings.Repeat(" ", w-1))
+	display.PutString(s, 0, h-1, style, fmt.Sprintf("Pass# %02d of %02d, Waste# %02d, Stock# %02d, Pyramid# %02d", g.pass+1, g.recycles+1, len(g.waste.Cards), len(g.deck.Cards)-g.deck.LastDealt, g.layout.Remaining()))
+	s.Show()
+}
+
+// ProcessKey handles the processing of key strokes
+//
+// ev:  The event that that contains the key.
+// g:  The game the key acts on.
+//
+// returns: false if the player wants to quit
+func processKey(ev *tcell.EventKey, g *game) bool {
+	var err error
+	switch ev.Key() {
+	case tcell.KeyEscape:
+		g.selected = nil
+		g.typed = ""
+		g.msg = ""
+	case tcell.KeyRune:
+		r := unicode.ToUpper(ev.Rune())
+		switch {
+		case g.typed != "":
+			rank := g.typed
+			g.typed = ""
+			var pos int
+			pos, err = g.find(rank, string(r))
+			if err == nil {
+				err = g.choose(pos)
+			}
+		case r == 'X':
+			return false
+		case r == ' ':
+			err = g.deal()
+		case r == 'W':
+			if len(g.waste.Cards) == 0 {
+				err = errors.New("the waste is empty")
+				break
+			}
+			err = g.choose(wastePos)
+		case strings.ContainsRune("A23456789TJQK", r):
+			g.typed = string(r)
+			g.msg = "Choose " + g.typed
+		}
+	}
+	if err != nil {
+		g.msg = err.Error() + "."
+	}
+	return true
+}
+
+// PlayGame is the main function that handles all aspects of the game.
+//
+// s: Screen variable.
+// g: The game to play.
+// style: The style for the screen.
+//
+// returns: true if the game was won.
+func playGame(s tcell.Screen, g *game, style tcell.Style) bool {
+	for {
+		if g.layout.Remaining() == 0 {
+			return true
+		}
+		if !g.hasMove() {
+			g.msg = "There are no moves left. Press any key."
+			showGame(s, g, style)
+			s.PollEvent()
+			return false
+		}
+		showGame(s, g, style)
+		ev := s.PollEvent()
+		switch ev := ev.(type) {
+		case *tcell.EventKey:
+			if ev.Key() == tcell.KeyCtrlL {
+				s.Sync()
+			} else if !processKey(ev, g) {
+				return false
+			}
+		}
+	}
+}
+
+func main() {
+	recycles := flag.Int("recycles", 2, "Number of times the waste can be turned over into the stock")
+	var daily stats.Daily
+	daily.Flags(flag.CommandLine)
+	flag.Parse()
+	if *recycles < 0 {
+		fmt.Fprintf(os.Stderr, "Recycles can not be negative\n")
+		os.Exit(1)
+	}
+	deck := generic.NewDeck()
+	deck.Source = daily.Source("pyramid", fmt.Sprintf("recycles %d", *recycles), time.Now())
+	deck.Shuffle()
+	g := newGame(deck, *recycles)
+	tcell.SetEncodingFallback(tcell.EncodingFallbackASCII)
+	s, e := tcell.NewScreen()
+	if e != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", e)
+		os.Exit(1)
+	}
+	if e = s.Init(); e != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", e)
+		os.Exit(1)
+	}
+	s.Clear()
+	s.HideCursor()
+	if err := drawScreen(s, tcell.StyleDefault); err != nil {
+		s.Fini()
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	start := time.Now()
+	won := playGame(s, g, tcell.StyleDefault)
+	s.Fini()
+	if won {
+		fmt.Println("Congratulations you won!")
+	} else {
+		fmt.Println("You either quit or lost. Better luck next time.")
+	}
+	if err := daily.Record(os.Stdout, stats.Result{Game: "pyramid", Won: won, Moves: g.moves, Elapsed: time.Since(start), Finished: time.Now()}); err != nil {
+		fmt.Fprintf(os.Stderr, "The result could not be saved: %v\n", err)
+	}
+}
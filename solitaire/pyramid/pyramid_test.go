@@ -0,0 +1,85 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell"
+	"github.com/tmasterson/cardgames/generic"
+)
+
+func key(r rune) *tcell.EventKey {
+	return tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone)
+}
+
+func TestNewGame(t *testing.T) {
+	g := newGame(generic.NewDeck(), 2)
+	if g.layout.Remaining() != 28 {
+		t.Errorf("Expected 28 cards in the pyramid but was %d", g.layout.Remaining())
+	}
+	if len(g.layout.FreeCards()) != 7 {
+		t.Errorf("Expected 7 free cards but was %d", len(g.layout.FreeCards()))
+	}
+	for _, c := range g.deck.Cards {
+		if c.Rvalue == 14 {
+			t.Errorf("Expected aces to be low but %s%s was %d", c.Rank, c.Suit, c.Rvalue)
+		}
+	}
+}
+
+func TestChoose(t *testing.T) {
+	g := newGame(generic.NewDeck(), 2)
+	g.layout.Cards[21] = generic.NewCard("6", "H", "red", 6, 8, true)
+	g.layout.Cards[22] = generic.NewCard("7", "S", "black", 7, 16, true)
+	g.layout.Cards[23] = generic.NewCard("K", "C", "black", 13, 2, true)
+	g.layout.Cards[24] = generic.NewCard("5", "D", "red", 5, 4, true)
+	if err := g.choose(21); err != nil || g.selected == nil {
+		t.Errorf("Expected 6H to be selected but was %v", err)
+	}
+	if err := g.choose(24); err == nil {
+		t.Errorf("Expected 6H and 5D to be refused")
+	}
+	if g.selected != nil || g.layout.Remaining() != 28 {
+		t.Errorf("Expected nothing selected or removed")
+	}
+	g.choose(21)
+	if err := g.choose(22); err != nil {
+		t.Errorf("Expected 6H and 7S to be removed but was %v", err)
+	}
+	if !g.layout.Removed[21] || !g.layout.Removed[22] || !g.layout.Free(15) {
+		t.Errorf("Expected 6H and 7S removed freeing position 15")
+	}
+	g.choose(23)
+	if !g.layout.Removed[23] {
+		t.Errorf("Expected the king to be removed on its own")
+	}
+	if g.moves != 2 {
+		t.Errorf("Expected the pair and the king to be 2 moves but was %d", g.moves)
+	}
+}
+
+func TestWaste(t *testing.T) {
+	g := newGame(generic.NewDeck(), 0)
+	g.layout.Cards[21] = generic.NewCard("6", "H", "red", 6, 8, true)
+	g.deck.Cards[g.deck.LastDealt] = generic.NewCard("9", "S", "black", 9, 16, false)
+	g.deck.Cards[g.deck.LastDealt+1] = generic.NewCard("7", "C", "black", 7, 2, false)
+	g.deal()
+	g.deal()
+	processKey(key('w'), g)
+	processKey(key('6'), g)
+	processKey(key('h'), g)
+	if !g.layout.Removed[21] || len(g.waste.Cards) != 1 {
+		t.Errorf("Expected 7C and 6H removed but was %v %d", g.layout.Removed[21], len(g.waste.Cards))
+	}
+	if top := g.waste.Cards[0]; top.Rank != "9" || !top.Faceup {
+		t.Errorf("Expected 9S face up on the waste but was %v", top)
+	}
+	for !g.deck.AllDealt {
+		g.deal()
+	}
+	if err := g.deal(); err == nil {
+		t.Errorf("Expected no more passes with no recycles")
+	}
+	if processKey(key('x'), g) {
+		t.Errorf("Expected x to quit")
+	}
+}
@@ -0,0 +1,32 @@
+package solitaire
+
+import (
+	"github.com/tmasterson/cardgames/generic"
+)
+
+// DealToWaste deals cards from the deck to the waste stack.
+// If all cards have been dealt from the deck it turns the waste stack into the deck
+// and increments the pass count.
+//
+// waste: The waste stack
+// deck: A pointer to the deck which is used as the stock
+// n: The number of cards to deal
+// pass: The pass count
+//
+// returns: The pass count
+func DealToWaste(waste *Pile, deck *generic.Deck, n, pass int) int {
+	if len(waste.Cards) > 0 {
+		waste.Cards[waste.Firstfaceup].Turn()
+	}
+	if deck.AllDealt {
+		deck.Cards = deck.Cards[:0]
+		deck.Cards = append(deck.Cards, waste.Cards...)
+		deck.LastDealt = 0
+		deck.AllDealt = false
+		waste.Cards = waste.Cards[:0]
+		pass++
+	}
+	waste.Cards = append(waste.Cards, deck.Deal(n, 1)...)
+	waste.Firstfaceup = len(waste.Cards) - 1
+	return pass
+}
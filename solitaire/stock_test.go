@@ -0,0 +1,27 @@
+package solitaire
+
+import (
+	"testing"
+
+	"github.com/tmasterson/cardgames/generic"
+)
+
+func TestDealToWaste(t *testing.T) {
+	deck := generic.NewDeck()
+	var waste Pile
+	waste.Ptype = 'W'
+	pass := DealToWaste(&waste, &deck, 1, 0)
+	if len(waste.Cards) != 1 || !waste.Cards[0].Faceup || pass != 0 {
+		t.Errorf("expected 1 face up card but had %d", len(waste.Cards))
+	}
+	pass = DealToWaste(&waste, &deck, 3, pass)
+	if len(waste.Cards) != 4 || waste.Cards[0].Faceup || waste.Firstfaceup != 3 {
+		t.Errorf("expected 4 cards with only the top face up")
+	}
+	deck.LastDealt = 52
+	deck.AllDealt = true
+	pass = DealToWaste(&waste, &deck, 1, pass)
+	if pass != 1 || len(deck.Cards) != 4 || len(waste.Cards) != 1 {
+		t.Errorf("expected the waste to be turned over into the deck but had %d %d %d", pass, len(deck.Cards), len(waste.Cards))
+	}
+}
@@ -0,0 +1,299 @@
+// This program allows a user to play the game tripeaks
+// It uses the ncurses tcell created by Garrett D'Amore which can be gotten by
+// go get -u github.com/gdamore/tcell
+//
+// 28 cards are dealt into three overlapping peaks.  Uncovered cards are played onto the waste
+// if they are one rank above or below its top card, going around the corner from king to ace.
+// Playing several cards in a row without dealing from the stock builds a streak worth more
+// points for each card and clearing a peak earns a bonus.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gdamore/tcell"
+	"github.com/tmasterson/cardgames/display"
+	"github.com/tmasterson/cardgames/generic"
+	"github.com/tmasterson/cardgames/solitaire"
+	"github.com/tmasterson/cardgames/stats"
+)
+
+// Points for clearing a peak, the last peak is worth more, and the cost of dealing.
+const (
+	peakBonus = 15
+	lastBonus = 30
+	dealCost  = 5
+)
+
+// Game holds everything about a game of tripeaks.
+// streak:  How many cards have been played since the last deal.
+// peaks:  How many peaks have been cleared.
+type game struct {
+	layout solitaire.Layout
+	waste  solitaire.Pile
+	deck   generic.Deck
+	score  int
+	moves  int
+	streak int
+	peaks  int
+	typed  string
+	msg    string
+}
+
+// Define the boxes for easier manipulation
+var playArea, wasteArea display.Box
+
+// NewGame deals the peaks and turns the first card of the stock onto the waste.
+// wrap: Allows kings and aces to be played on each other
+func newGame(deck generic.Deck, wrap bool) *game {
+	// Make aces low card instead of high card
+	for i := range deck.Cards {
+		if deck.Cards[i].Rvalue == 14 {
+			deck.Cards[i].Rvalue = 1
+		}
+	}
+	g := &game{deck: deck}
+	g.layout = solitaire.NewTriPeaks(&g.deck)
+	g.waste.Ptype = 'G'
+	g.waste.Wrap = wrap
+	solitaire.DealToWaste(&g.waste, &g.deck, 1, 0)
+	return g
+}
+
+// Play moves the free card at pos onto the waste and scores it.
+func (g *game) play(pos int) error {
+	c := g.layout.Cards[pos]
+	// a one card pile lets CheckMove decide if it fits on the waste
+	from := solitaire.Pile{Cards: []generic.Card{c}}
+	if !from.CheckMove(&g.waste, 0) {
+		return fmt.Errorf("%s can not be played on the waste", c.Rank+c.Suit)
+	}
+	g.layout.Remove(pos)
+	g.waste.Add(from.Cards)
+	g.waste.Firstfaceup = len(g.waste.Cards) - 1
+	g.moves++
+	g.streak++
+	g.score += g.streak
+	if pos < 3 {
+		g.peaks++
+		if g.peaks == 3 {
+			g.score += lastBonus
+		} else {
+			g.score += peakBonus
+		}
+	}
+	return nil
+}
+
+// Deal turns the next card of the stock onto the waste ending the streak.
+func (g *game) deal() error {
+	if g.deck.AllDealt {
+		return errors.New("the stock is empty")
+	}
+	solitaire.DealToWaste(&g.waste, &g.deck, 1, 0)
+	g.moves++
+	g.streak = 0
+	g.score -= dealCost
+	return nil
+}
+
+// HasMove tells if a card can be played or dealt.
+func (g *game) hasMove() bool {
+	if !g.deck.AllDealt {
+		return true
+	}
+	top := g.waste.Cards[len(g.waste.Cards)-1]
+	for _, i := range g.layout.FreeCards() {
+		if solitaire.Adjacent(g.layout.Cards[i], top, g.waste.Wrap) {
+			return true
+		}
+	}
+	return false
+}
+
+// DrawScreen draws the screen putting all the boxes in place
+// s: The screen variable
+// style: The style for the screen
+//
+// Returns: Returns an error if one occurs otherwise nil
+func drawScreen(s tcell.Screen, style tcell.Style) error {
+	w, h := s.Size()
+	if w < 80 || h < 25 {
+		return errors.New("Screen size must be at least 80 by 25")
+	}
+	title := "TriPeaks"
+	display.PutString(s, w/2-len(title)/2, 0, style, title)
+	var err error
+	playArea, err = display.MakeBox(s, "Peaks", 0, 2, 46, 12, style)
+	if err != nil {
+		return err
+	}
+	wasteArea, err = display.MakeBox(s, "Waste", 0, playArea.BotY+2, 10, playArea.BotY+4, style)
+	if err != nil {
+		return err
+	}
+	x := wasteArea.RightX + 5
+	y := wasteArea.TopY
+	help := []string{
+		"Type a card such as 5H to play it onto the waste.",
+		"Space deals a card from the stock.",
+		"X quits.",
+		"",
+		"Each card played in a row scores one more point.",
+		"Dealing ends the streak and costs 5 points.",
+	}
+	for _, line := range help {
+		display.PutString(s, x, y, style, line)
+		y++
+	}
+	s.Show()
+	return nil
+}
+
+// ShowGame prints the peaks, the waste and the status line.
+// Face down cards are shown as ##.
+// s: Screen variable
+// g: The game
+// style: The style for the cards
+func showGame(s tcell.Screen, g *game, style tcell.Style) {
+	w, h := s.Size()
+	blank := strings.Repeat(" ", playArea.RightX-playArea.LeftX-1)
+	for y := playArea.TopY + 1; y < playArea.BotY; y++ {
+		display.PutString(s, playArea.LeftX+1, y, style, blank)
+	}
+	for i, c := range g.layout.Cards {
+		if g.layout.Removed[i] {
+			continue
+		}
+		str := "##"
+		if c.Faceup {
+			str = c.Rank + c.Suit
+		}
+		p := g.layout.Pos[i]
+		display.PutString(s, playArea.LeftX+3+p.Col*2, playArea.TopY+2+p.Row*2, style, str)
+	}
+	top := g.waste.Cards[len(g.waste.Cards)-1]
+	display.PutString(s, wasteArea.CardArea, wasteArea.TopY+1, style, top.Rank+top.Suit)
+	display.PutString(s, 0, h-2, style, strings.Repeat(" ", w-1))
+	display.PutString(s, 0, h-2, style, g.msg)
+	display.PutString(s, 0, h-1, style, strings.Repeat(" ", w-1))
+	display.PutString(s, 0, h-1, style, fmt.Sprintf("Score# %d, Streak# %02d, Stock# %02d, Peaks# %d", g.score, g.streak, len(g.deck.Cards)-g.deck.LastDealt, g.peaks))
+	s.Show()
+}
+
+// ProcessKey handles the processing of key strokes
+//
+// ev:  The event that that contains the key.
+// g:  The game the key acts on.
+//
+// returns: false if the player wants to quit
+func processKey(ev *tcell.EventKey, g *game) bool {
+	var err error
+	switch ev.Key() {
+	case tcell.KeyEscape:
+		g.typed = ""
+		g.msg = ""
+	case tcell.KeyRune:
+		r := unicode.ToUpper(ev.Rune())
+		switch {
+		case g.typed != "":
+			rank := g.typed
+			g.typed = ""
+			g.msg = ""
+			pos := g.layout.Find(rank, string(r))
+			if pos < 0 {
+				err = fmt.Errorf("%s%c is not available", rank, r)
+				break
+			}
+			err = g.play(pos)
+		case r == 'X':
+			return false
+		case r == ' ':
+			g.msg = ""
+			err = g.deal()
+		case strings.ContainsRune("A23456789TJQK", r):
+			g.typed = string(r)
+			g.msg = "Play " + g.typed
+		}
+	}
+	if err != nil {
+		g.msg = err.Error() + "."
+	}
+	return true
+}
+
+// PlayGame is the main function that handles all aspects of the game.
+//
+// s: Screen variable.
+// g: The game to play.
+// style: The style for the screen.
+//
+// returns: true if every peak was cleared.
+func playGame(s tcell.Screen, g *game, style tcell.Style) bool {
+	for {
+		if g.layout.Remaining() == 0 {
+			return true
+		}
+		if !g.hasMove() {
+			g.msg = "There are no moves left. Press any key."
+			showGame(s, g, style)
+			s.PollEvent()
+			return false
+		}
+		showGame(s, g, style)
+		ev := s.PollEvent()
+		switch ev := ev.(type) {
+		case *tcell.EventKey:
+			if ev.Key() == tcell.KeyCtrlL {
+				s.Sync()
+			} else if !processKey(ev, g) {
+				return false
+			}
+		}
+	}
+}
+
+func main() {
+	wrap := flag.Bool("wrap", true, "Allow kings and aces to be played on each other")
+	var daily stats.Daily
+	daily.Flags(flag.CommandLine)
+	flag.Parse()
+	deck := generic.NewDeck()
+	deck.Source = daily.Source("tripeaks", fmt.Sprintf("wrap %v", *wrap), time.Now())
+	deck.Shuffle()
+	g := newGame(deck, *wrap)
+	tcell.SetEncodingFallback(tcell.EncodingFallbackASCII)
+	s, e := tcell.NewScreen()
+	if e != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", e)
+		os.Exit(1)
+	}
+	if e = s.Init(); e != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", e)
+		os.Exit(1)
+	}
+	s.Clear()
+	s.HideCursor()
+	if err := drawScreen(s, tcell.StyleDefault); err != nil {
+		s.Fini()
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	start := time.Now()
+	won := playGame(s, g, tcell.StyleDefault)
+	s.Fini()
+	if won {
+		fmt.Printf("Congratulations you cleared all three peaks with a score of %d!\n", g.score)
+	} else {
+		fmt.Printf("You finished with a score of %d.\n", g.score)
+	}
+	if err := daily.Record(os.Stdout, stats.Result{Game: "tripeaks", Won: won, Moves: g.moves, Score: g.score, Elapsed: time.Since(start), Finished: time.Now()}); err != nil {
+		fmt.Fprintf(os.Stderr, "The result could not be saved: %v\n", err)
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell"
+	"github.com/tmasterson/cardgames/generic"
+)
+
+func key(r rune) *tcell.EventKey {
+	return tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone)
+}
+
+func TestNewGame(t *testing.T) {
+	g := newGame(generic.NewDeck(), true)
+	if g.layout.Remaining() != 28 || len(g.waste.Cards) != 1 || !g.waste.Wrap {
+		t.Errorf("Expected 28 cards in the peaks and 1 on a wrapping waste but was %d %d", g.layout.Remaining(), len(g.waste.Cards))
+	}
+}
+
+func TestStreak(t *testing.T) {
+	g := newGame(generic.NewDeck(), true)
+	g.waste.Cards[0] = generic.NewCard("K", "H", "red", 13, 8, true)
+	g.layout.Cards[18] = generic.NewCard("A", "S", "black", 1, 16, true)
+	g.layout.Cards[19] = generic.NewCard("2", "D", "red", 2, 4, true)
+	g.layout.Cards[20] = generic.NewCard("9", "C", "black", 9, 2, true)
+	processKey(key('a'), g)
+	processKey(key('s'), g)
+	processKey(key('2'), g)
+	processKey(key('d'), g)
+	if g.streak != 2 || g.score != 3 || len(g.waste.Cards) != 3 {
+		t.Errorf("Expected a streak of 2 scoring 3 but was %d %d", g.streak, g.score)
+	}
+	processKey(key('9'), g)
+	processKey(key('c'), g)
+	if g.msg == "" || g.layout.Removed[20] {
+		t.Errorf("Expected 9C to be refused")
+	}
+	processKey(key(' '), g)
+	if g.streak != 0 || g.score != 3-dealCost {
+		t.Errorf("Expected dealing to end the streak and cost %d but was %d %d", dealCost, g.streak, g.score)
+	}
+	if processKey(key('x'), g) {
+		t.Errorf("Expected x to quit")
+	}
+}
+
+func TestPeakBonus(t *testing.T) {
+	g := newGame(generic.NewDeck(), true)
+	for p := 0; p < 3; p++ {
+		for i := 3; i < 28; i++ {
+			g.layout.Removed[i] = true
+		}
+		g.layout.Cards[p].Faceup = true
+		g.waste.Cards[len(g.waste.Cards)-1] = g.layout.Cards[p]
+		g.waste.Cards[len(g.waste.Cards)-1].Rvalue = g.layout.Cards[p].Rvalue%13 + 1
+		if err := g.play(p); err != nil {
+			t.Fatalf("Expected peak %d to be played but was %v", p, err)
+		}
+	}
+	// three cards in a streak score 6 plus two peaks and the last peak
+	if g.peaks != 3 || g.score != 6+2*peakBonus+lastBonus {
+		t.Errorf("Expected all peaks cleared scoring %d but was %d", 6+2*peakBonus+lastBonus, g.score)
+	}
+	if g.layout.Remaining() != 0 || g.moves != 3 {
+		t.Errorf("Expected the layout emptied in 3 moves but was %d", g.moves)
+	}
+}
@@ -0,0 +1,338 @@
+// This program allows a user to play the game yukon
+// It uses the ncurses tcell created by Garrett D'Amore which can be gotten by
+// go get -u github.com/gdamore/tcell
+//
+// All 52 cards are dealt to seven tableau columns and there is no stock.  Any face up
+// card can be moved along with every card on top of it whether or not they are in sequence.
+// The russian variant builds the tableau down by suit instead of alternate colors.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gdamore/tcell"
+	"github.com/tmasterson/cardgames/display"
+	"github.com/tmasterson/cardgames/generic"
+	"github.com/tmasterson/cardgames/solitaire"
+	"github.com/tmasterson/cardgames/stats"
+)
+
+// Move is a structure to track moves.
+// from:  Stack to move cards from
+// to:  Stack to move cards to
+// howmany:  Number of cards to move, 0 finds the card that fits
+type move struct {
+	from    int
+	to      int
+	howmany int
+}
+
+// The stacks are the seven tableau columns followed by the four aces.
+const (
+	columns    = 7
+	firstAce   = 7
+	stackCount = 11
+)
+
+// Define the boxes for easier manipulation
+var aces [4]display.Box
+var playArea display.Box
+
+// Russian is true when playing russian solitaire
+var russian bool
+
+// Name returns the name of the game being played.
+func name() string {
+	if russian {
+		return "russian"
+	}
+	return "yukon"
+}
+
+// Daily is the deal of the day and the leaderboard it is recorded on.
+var daily stats.Daily
+
+// Moves is the number of moves made in the game, counted as cards are moved or dealt.
+var moves int
+
+// DrawScreen draws the screen putting all the boxes in place
+// s: The screen variable
+// style: The style for the screen
+//
+// Returns: Returns an error if one occurs otherwise nil
+func drawScreen(s tcell.Screen, style tcell.Style) error {
+	w, h := s.Size()
+	if w < 80 || h < 25 {
+		return errors.New("Screen size must be at least 80 by 25")
+	}
+	title := "Yukon"
+	if russian {
+		title = "Russian Solitaire"
+	}
+	display.PutString(s, w/2-len(title)/2, 0, style, title)
+	var err error
+	x := 0
+	for i := range aces {
+		aces[i], err = display.MakeBox(s, "Ace", x, 2, x+6, 4, style)
+		if err != nil {
+			return err
+		}
+		x = aces[i].RightX + 1
+	}
+	playArea, err = display.MakeBox(s, "Tableau", 0, aces[0].BotY+2, 30, h-2, style)
+	if err != nil {
+		return err
+	}
+	x = playArea.RightX + 5
+	y := playArea.TopY
+	help := []string{
+		"Moves:",
+		"AG moves from stack 1 to stack 7 finding",
+		"   the face up card that fits.",
+		"A3G moves the top 3 cards of stack 1 to 7.",
+		"A<Enter> moves from stack 1 to an ace stack.",
+		"Q quits.",
+		"",
+		"Any face up card can be moved with all",
+		"the cards on top of it.",
+	}
+	for _, line := range help {
+		display.PutString(s, x, y, style, line)
+		y++
+	}
+	for i := 0; i < columns; i++ {
+		display.PutString(s, playArea.LeftX+i*4+2, playArea.TopY+1, style, string(rune('A'+i)))
+	}
+	s.Show()
+	return nil
+}
+
+// ShowStacks prints the cards in each stack
+// Face down cards are shown as a count and if a column is too long for the screen
+// the cards nearest the bottom are replaced with ..
+// s: Screen variable
+// stacks: A slice containing all the stacks or Piles of cards
+// style: The style for the cards
+func showStacks(s tcell.Screen, stacks []solitaire.Pile, style tcell.Style) {
+	rows := playArea.BotY - playArea.TopY - 2
+	for i := 0; i < columns; i++ {
+		pile := stacks[i]
+		var lines []string
+		if pile.Firstfaceup > 0 {
+			lines = append(lines, fmt.Sprintf("#%d", pile.Firstfaceup))
+		}
+		for _, card := range pile.Cards[pile.Firstfaceup:] {
+			lines = append(lines, card.Rank+card.Suit)
+		}
+		if len(lines) > rows {
+			lines = append([]string{".."}, lines[len(lines)-rows+1:]...)
+		}
+		for k := 0; k < rows; k++ {
+			str := "   "
+			if k < len(lines) {
+				str = fmt.Sprintf("%-3s", lines[k])
+			}
+			display.PutString(s, playArea.LeftX+i*4+2, playArea.TopY+2+k, style, str)
+		}
+	}
+	for i := range aces {
+		pile := stacks[firstAce+i]
+		str := "  "
+		if len(pile.Cards) > 0 {
+			card := pile.Cards[len(pile.Cards)-1]
+			str = card.Rank + card.Suit
+		}
+		display.PutString(s, aces[i].CardArea, aces[i].TopY+1, style, str)
+	}
+	s.Show()
+}
+
+// Deal lays out a new game.
+// The first column gets one card and each other column gets one more face down card than
+// the one before it with five face up cards on top.
+func deal(deck *generic.Deck) []solitaire.Pile {
+	stacks := make([]solitaire.Pile, stackCount)
+	// Make aces low card instead of high card
+	for i := range deck.Cards {
+		if deck.Cards[i].Rvalue == 14 {
+			deck.Cards[i].Rvalue = 1
+		}
+	}
+	for i := range stacks {
+		switch {
+		case i == 0:
+			stacks[i].Cards = deck.Deal(1, 1)
+		case i < columns:
+			stacks[i].Cards = deck.Deal(i+5, 5)
+			stacks[i].Firstfaceup = i
+		default:
+			stacks[i].Ptype = 'A'
+			continue
+		}
+		stacks[i].Ptype = 'T'
+		if russian {
+			stacks[i].Ptype = 'S'
+		}
+	}
+	return stacks
+}
+
+// ProcessKey handles the processing of key strokes
+//
+// ev:  The event that that contains the key.
+// stacks:  The card stacks.  Used to find an ace stack for the Enter key.
+// cm:  The move so far.
+//
+// returns: The move with the key added and a bool which is false if the player quit.
+func processKey(ev *tcell.EventKey, stacks []solitaire.Pile, cm move) (move, bool) {
+	switch ev.Key() {
+	case tcell.KeyEnter:
+		if cm.from != -1 {
+			cm.to = solitaire.Foundation(stacks, cm.from)
+			cm.howmany = 1
+			if cm.to == -1 {
+				cm = move{from: -1, to: -1}
+			}
+		}
+	case tcell.KeyRune:
+		r := unicode.ToUpper(ev.Rune())
+		switch {
+		case r == 'Q':
+			return cm, false
+		case r >= 'A' && r < 'A'+columns:
+			if cm.from == -1 {
+				cm.from = int(r - 'A')
+			} else {
+				cm.to = int(r - 'A')
+			}
+		case r >= '0' && r <= '9' && cm.from != -1:
+			cm.howmany = cm.howmany*10 + int(r-'0')
+		default:
+			cm = move{from: -1, to: -1}
+		}
+	}
+	return cm, true
+}
+
+// MoveCards makes the move once both ends are known.
+// If no count was given the face up card that fits onto the destination is found.
+//
+// returns: The move reset for the next one, or unchanged if it is not complete.
+func moveCards(stacks []solitaire.Pile, cm move) move {
+	if cm.from < 0 || cm.to < 0 {
+		return cm
+	}
+	from := &stacks[cm.from]
+	to := &stacks[cm.to]
+	if cm.from != cm.to && len(from.Cards) > 0 {
+		index := -1
+		switch {
+		case to.Ptype == 'A':
+			index = len(from.Cards) - 1
+		case cm.howmany > 0 && cm.howmany <= len(from.Cards):
+			index = len(from.Cards) - cm.howmany
+		default:
+			index = from.FindMove(to)
+		}
+		if index >= 0 && from.CheckMove(to, index) {
+			from.DoMove(to, index)
+			moves++
+		}
+	}
+	return move{from: -1, to: -1}
+}
+
+// Won tells if every card is on the aces.
+func won(stacks []solitaire.Pile) bool {
+	total := 0
+	for i := firstAce; i < stackCount; i++ {
+		total += len(stacks[i].Cards)
+	}
+	return total == 52
+}
+
+// PlayGame is the main function that handles all aspects of the game.
+//
+// s: Screen variable.
+// style: The style for the screen.
+//
+// returns: true if the game was won and the number of moves made.
+func playGame(s tcell.Screen, style tcell.Style) (bool, int) {
+	deck := generic.NewDeck()
+	deck.Source = daily.Source(name(), "", time.Now())
+	deck.Shuffle()
+	stacks := deal(&deck)
+	w, h := s.Size()
+	cardmove := move{from: -1, to: -1}
+	moves = 0
+	for {
+		showStacks(s, stacks, style)
+		status := ""
+		if cardmove.from >= 0 {
+			status = fmt.Sprintf("Move from %c", 'A'+cardmove.from)
+			if cardmove.howmany > 0 {
+				status += fmt.Sprintf(" %d cards", cardmove.howmany)
+			}
+		}
+		display.PutString(s, 0, h-1, style, strings.Repeat(" ", w-1))
+		display.PutString(s, 0, h-1, style, status)
+		s.Show()
+		if won(stacks) {
+			return true, moves
+		}
+		ev := s.PollEvent()
+		switch ev := ev.(type) {
+		case *tcell.EventKey:
+			if ev.Key() == tcell.KeyCtrlL {
+				s.Sync()
+				continue
+			}
+			var ok bool
+			cardmove, ok = processKey(ev, stacks, cardmove)
+			if !ok {
+				return false, moves
+			}
+			cardmove = moveCards(stacks, cardmove)
+		}
+	}
+}
+
+func main() {
+	flag.BoolVar(&russian, "russian", false, "Play russian solitaire which builds down by suit")
+	daily.Flags(flag.CommandLine)
+	flag.Parse()
+	tcell.SetEncodingFallback(tcell.EncodingFallbackASCII)
+	s, e := tcell.NewScreen()
+	if e != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", e)
+		os.Exit(1)
+	}
+	if e = s.Init(); e != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", e)
+		os.Exit(1)
+	}
+	s.Clear()
+	s.HideCursor()
+	if err := drawScreen(s, tcell.StyleDefault); err != nil {
+		s.Fini()
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	start := time.Now()
+	win, moves := playGame(s, tcell.StyleDefault)
+	s.Fini()
+	if win {
+		fmt.Println("Congratulations you won!")
+	} else {
+		fmt.Println("You either quit or lost. Better luck next time.")
+	}
+	if err := daily.Record(os.Stdout, stats.Result{Game: name(), Won: win, Moves: moves, Elapsed: time.Since(start), Finished: time.Now()}); err != nil {
+		fmt.Fprintf(os.Stderr, "The result could not be saved: %v\n", err)
+	}
+}
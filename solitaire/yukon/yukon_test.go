@@ -0,0 +1,116 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell"
+	"github.com/tmasterson/cardgames/generic"
+	"github.com/tmasterson/cardgames/solitaire"
+)
+
+func key(r rune) *tcell.EventKey {
+	return tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone)
+}
+
+func TestDeal(t *testing.T) {
+	deck := generic.NewDeck()
+	deck.Shuffle()
+	stacks := deal(&deck)
+	total := 0
+	for i := 0; i < columns; i++ {
+		p := stacks[i]
+		total += len(p.Cards)
+		up := 0
+		for _, c := range p.Cards {
+			if c.Faceup {
+				up++
+			}
+		}
+		if i > 0 && (len(p.Cards) != i+5 || up != 5 || p.Firstfaceup != i) {
+			t.Errorf("column %d should have %d cards with 5 face up but had %d with %d", i, i+5, len(p.Cards), up)
+		}
+		if p.Ptype != 'T' {
+			t.Errorf("expected a T pile but was %c", p.Ptype)
+		}
+	}
+	if total != 52 || !deck.AllDealt {
+		t.Errorf("expected all 52 cards dealt but was %d", total)
+	}
+	russian = true
+	defer func() { russian = false }()
+	deck = generic.NewDeck()
+	stacks = deal(&deck)
+	if stacks[3].Ptype != 'S' || stacks[firstAce].Ptype != 'A' {
+		t.Errorf("expected S and A piles but were %c %c", stacks[3].Ptype, stacks[firstAce].Ptype)
+	}
+}
+
+func TestProcessKey(t *testing.T) {
+	stacks := make([]solitaire.Pile, stackCount)
+	for i := firstAce; i < stackCount; i++ {
+		stacks[i].Ptype = 'A'
+	}
+	stacks[1].Cards = append(stacks[1].Cards, generic.NewCard("A", "S", "black", 1, 16, true))
+	cm := move{from: -1, to: -1}
+	cm, _ = processKey(key('b'), stacks, cm)
+	cm, _ = processKey(key('1'), stacks, cm)
+	cm, _ = processKey(key('2'), stacks, cm)
+	cm, _ = processKey(key('g'), stacks, cm)
+	if cm.from != 1 || cm.to != 6 || cm.howmany != 12 {
+		t.Errorf("expected 1 6 12 but was %v", cm)
+	}
+	cm = move{from: 1, to: -1}
+	cm, _ = processKey(tcell.NewEventKey(tcell.KeyEnter, ' ', tcell.ModNone), stacks, cm)
+	if cm.to != firstAce {
+		t.Errorf("expected the ace to go to stack %d but was %d", firstAce, cm.to)
+	}
+	if _, ok := processKey(key('q'), stacks, cm); ok {
+		t.Errorf("expected q to quit")
+	}
+}
+
+func TestMoveCards(t *testing.T) {
+	stacks := make([]solitaire.Pile, stackCount)
+	for i := 0; i < columns; i++ {
+		stacks[i].Ptype = 'T'
+	}
+	// yukon lets the 8S move with the unrelated 2D on top of it
+	stacks[0].Cards = append(stacks[0].Cards, generic.NewCard("9", "H", "red", 9, 8, false))
+	stacks[0].Cards = append(stacks[0].Cards, generic.NewCard("8", "S", "black", 8, 16, true))
+	stacks[0].Cards = append(stacks[0].Cards, generic.NewCard("2", "D", "red", 2, 4, true))
+	stacks[0].Firstfaceup = 1
+	stacks[1].Cards = append(stacks[1].Cards, generic.NewCard("9", "D", "red", 9, 4, true))
+	moves = 0
+	cm := moveCards(stacks, move{from: 0, to: 1})
+	if cm.from != -1 || len(stacks[1].Cards) != 3 || len(stacks[0].Cards) != 1 {
+		t.Fatalf("expected 2 cards to move but columns have %d and %d", len(stacks[0].Cards), len(stacks[1].Cards))
+	}
+	if !stacks[0].Cards[0].Faceup {
+		t.Errorf("expected the 9H to be turned face up")
+	}
+	moveCards(stacks, move{from: 1, to: 0, howmany: 1})
+	if len(stacks[0].Cards) != 1 {
+		t.Errorf("the 2D should not move onto the 9H")
+	}
+	if moves != 1 {
+		t.Errorf("Expected 1 move made but was %d", moves)
+	}
+}
+
+func TestDrawScreen(t *testing.T) {
+	s := tcell.NewSimulationScreen("")
+	if e := s.Init(); e != nil {
+		t.Fatalf("Failed to initialize screen: %v", e)
+	}
+	defer s.Fini()
+	s.SetSize(30, 10)
+	if err := drawScreen(s, tcell.StyleDefault); err == nil {
+		t.Errorf("Expected an error here")
+	}
+	s.SetSize(80, 25)
+	if err := drawScreen(s, tcell.StyleDefault); err != nil {
+		t.Errorf("There should be no errors")
+	}
+	deck := generic.NewDeck()
+	showStacks(s, deal(&deck), tcell.StyleDefault)
+}
@@ -0,0 +1,70 @@
+// Package stats keeps the time and results of games so they can be used for statistics and leaderboards.
+package stats
+
+import (
+	"fmt"
+	"time"
+)
+
+// Clock times a game, it can be paused and resumed.
+// Now is used to read the time and can be replaced for testing.
+type Clock struct {
+	Now     func() time.Time
+	start   time.Time
+	elapsed time.Duration
+	running bool
+}
+
+// NewClock returns a stopped clock reading the system time.
+func NewClock() *Clock {
+	return &Clock{Now: time.Now}
+}
+
+// Start starts or resumes the clock.
+func (c *Clock) Start() {
+	if c.running {
+		return
+	}
+	c.start = c.Now()
+	c.running = true
+}
+
+// Pause stops the clock keeping the time so far.
+func (c *Clock) Pause() {
+	if !c.running {
+		return
+	}
+	c.elapsed += c.Now().Sub(c.start)
+	c.running = false
+}
+
+// Toggle pauses a running clock or starts a paused one.
+func (c *Clock) Toggle() {
+	if c.running {
+		c.Pause()
+	} else {
+		c.Start()
+	}
+}
+
+// Running tells if the clock is running.
+func (c *Clock) Running() bool {
+	return c.running
+}
+
+// Elapsed returns the time the clock has been running.
+func (c *Clock) Elapsed() time.Duration {
+	if c.running {
+		return c.elapsed + c.Now().Sub(c.start)
+	}
+	return c.elapsed
+}
+
+// Format returns a duration as minutes and seconds, with hours in front if there are any.
+func Format(d time.Duration) string {
+	secs := int(d / time.Second)
+	if secs >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", secs/3600, secs/60%60, secs%60)
+	}
+	return fmt.Sprintf("%02d:%02d", secs/60, secs%60)
+}
@@ -0,0 +1,215 @@
+package stats
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/tmasterson/cardgames/generic"
+)
+
+// Entry is a result for a daily deal on the leaderboard.
+// Rules: The options the game was played with, such as the number of cards dealt at a time
+// Seed: The seed of the deal in hex, entries with the same game and seed played the same deal
+type Entry struct {
+	Player string `json:"player"`
+	Rules  string `json:"rules,omitempty"`
+	Seed   string `json:"seed"`
+	Result
+}
+
+// LeaderboardPath returns the file the leaderboard is kept in under the user's config directory.
+func LeaderboardPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "cardgames", "leaderboard.json"), nil
+}
+
+// AddEntry adds an entry to the end of the leaderboard file at path.
+func AddEntry(path string, e Entry) error {
+	return appendLine(path, e)
+}
+
+// LoadEntries reads every entry in the leaderboard file at path.  A missing file has no entries.
+func LoadEntries(path string) ([]Entry, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, scanner.Err()
+}
+
+// Import adds the entries in the leaderboard file from, such as one sent by a friend, to the
+// file at path.  Entries that are already there are skipped.  It returns the number added.
+func Import(path, from string) (int, error) {
+	have, err := LoadEntries(path)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := os.Stat(from); err != nil {
+		return 0, err
+	}
+	entries, err := LoadEntries(from)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %v", from, err)
+	}
+	seen := make(map[string]bool)
+	key := func(e Entry) string {
+		return e.Player + "\x00" + e.Game + "\x00" + e.Rules + "\x00" + e.Seed + "\x00" + e.Finished.UTC().Format(time.RFC3339Nano)
+	}
+	for _, e := range have {
+		seen[key(e)] = true
+	}
+	added := 0
+	for _, e := range entries {
+		if seen[key(e)] {
+			continue
+		}
+		seen[key(e)] = true
+		if err := AddEntry(path, e); err != nil {
+			return added, err
+		}
+		added++
+	}
+	return added, nil
+}
+
+// Rank returns the entries for one deal played by the same rules, best first.  A win beats a
+// loss, then a higher score, fewer moves and a shorter time are better.
+func Rank(entries []Entry, game, rules, seed string) []Entry {
+	var ranked []Entry
+	for _, e := range entries {
+		if e.Game == game && e.Rules == rules && e.Seed == seed {
+			ranked = append(ranked, e)
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		switch {
+		case a.Won != b.Won:
+			return a.Won
+		case a.Score != b.Score:
+			return a.Score > b.Score
+		case a.Moves != b.Moves:
+			return a.Moves < b.Moves
+		}
+		return a.Elapsed < b.Elapsed
+	})
+	return ranked
+}
+
+// WriteBoard writes ranked entries as a table with the player's own results marked.
+func WriteBoard(w io.Writer, ranked []Entry, player string) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "Rank\tPlayer\tWon\tMoves\tTime\tScore\t\t")
+	for i, e := range ranked {
+		won, you := "no", ""
+		if e.Won {
+			won = "yes"
+		}
+		if e.Player == player {
+			you = "<"
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%d\t%s\t%d\t%s\t\n", i+1, e.Player, won, e.Moves, Format(e.Elapsed), e.Score, you)
+	}
+	return tw.Flush()
+}
+
+// Daily holds the command line flags for playing the deal of the day and recording it on
+// the leaderboard.
+type Daily struct {
+	On     bool
+	Player string
+	Path   string
+	Import string
+	rules  string
+	seed   string
+	day    time.Time
+}
+
+// Flags adds the -daily, -player, -leaderboard and -import flags to fs.
+func (d *Daily) Flags(fs *flag.FlagSet) {
+	player := os.Getenv("USER")
+	if player == "" {
+		player = "player"
+	}
+	fs.BoolVar(&d.On, "daily", false, "Play the deal of the day and compare your result on the leaderboard")
+	fs.StringVar(&d.Player, "player", player, "Your name on the leaderboard")
+	fs.StringVar(&d.Path, "leaderboard", "", "Leaderboard file, by default leaderboard.json in the user config directory")
+	fs.StringVar(&d.Import, "import", "", "Comma separated leaderboard files of other players to add to yours")
+}
+
+// Source returns the shuffle source for the game's deal of the day, or nil if -daily was not given.
+// rules: The options the game is played with, only results played by the same rules are compared
+func (d *Daily) Source(game, rules string, day time.Time) generic.Source {
+	if !d.On {
+		return nil
+	}
+	c := generic.DailySeed(game, day)
+	d.rules = rules
+	d.seed = c.SeedString()
+	d.day = day
+	return c.Source()
+}
+
+// Record adds the result of the deal of the day to the leaderboard, imports any other
+// leaderboards and writes how the result compares to everyone who played the same deal.
+// Nothing is done if -daily was not given.
+func (d *Daily) Record(w io.Writer, r Result) error {
+	if !d.On {
+		return nil
+	}
+	var err error
+	path := d.Path
+	if path == "" {
+		if path, err = LeaderboardPath(); err != nil {
+			return err
+		}
+	}
+	if err = AddEntry(path, Entry{Player: d.Player, Rules: d.rules, Seed: d.seed, Result: r}); err != nil {
+		return err
+	}
+	for _, from := range strings.Split(d.Import, ",") {
+		if from = strings.TrimSpace(from); from == "" {
+			continue
+		}
+		if _, err = Import(path, from); err != nil {
+			return err
+		}
+	}
+	entries, err := LoadEntries(path)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Deal of the day %s, seed %s\n", d.day.UTC().Format("2006-01-02"), d.seed)
+	if d.rules != "" {
+		fmt.Fprintf(w, "Played with %s\n", d.rules)
+	}
+	return WriteBoard(w, Rank(entries, r.Game, d.rules, d.seed), d.Player)
+}
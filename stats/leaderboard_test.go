@@ -0,0 +1,98 @@
+package stats
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLeaderboard(t *testing.T) {
+	dir, err := ioutil.TempDir("", "stats")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	mine := filepath.Join(dir, "mine.json")
+	theirs := filepath.Join(dir, "theirs.json")
+	now := time.Unix(1000, 0)
+	for _, e := range []Entry{
+		{Player: "ann", Seed: "aa", Result: Result{Game: "klondike", Won: true, Moves: 120, Elapsed: time.Minute, Finished: now}},
+		{Player: "bob", Seed: "aa", Result: Result{Game: "klondike", Won: true, Moves: 100, Elapsed: 2 * time.Minute, Finished: now}},
+		{Player: "cat", Seed: "aa", Result: Result{Game: "klondike", Won: false, Moves: 50, Finished: now}},
+		{Player: "bob", Seed: "bb", Result: Result{Game: "klondike", Won: true, Moves: 90, Finished: now}},
+		{Player: "dan", Rules: "draw 1", Seed: "aa", Result: Result{Game: "klondike", Won: true, Moves: 80, Finished: now}},
+	} {
+		if err := AddEntry(theirs, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	n, err := Import(mine, theirs)
+	if err != nil || n != 5 {
+		t.Errorf("Expected 5 entries imported but was %d %v", n, err)
+	}
+	if n, err = Import(mine, theirs); err != nil || n != 0 {
+		t.Errorf("Expected nothing imported twice but was %d %v", n, err)
+	}
+	if _, err = Import(mine, filepath.Join(dir, "missing.json")); err == nil {
+		t.Errorf("Expected an error importing a missing file")
+	}
+	entries, err := LoadEntries(mine)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ranked := Rank(entries, "klondike", "", "aa")
+	if len(ranked) != 3 || ranked[0].Player != "bob" || ranked[1].Player != "ann" || ranked[2].Player != "cat" {
+		t.Errorf("Expected bob, ann then cat but was %+v", ranked)
+	}
+	if ranked = Rank(entries, "klondike", "draw 1", "aa"); len(ranked) != 1 || ranked[0].Player != "dan" {
+		t.Errorf("Expected only dan played by other rules but was %+v", ranked)
+	}
+	ranked = Rank(entries, "klondike", "", "aa")
+	var buf bytes.Buffer
+	WriteBoard(&buf, ranked, "ann")
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 || !strings.Contains(lines[2], "ann") || !strings.HasSuffix(lines[2], "<") || strings.HasSuffix(lines[1], "<") {
+		t.Errorf("Expected ann's row marked but was\n%s", buf.String())
+	}
+}
+
+func TestDaily(t *testing.T) {
+	dir, err := ioutil.TempDir("", "stats")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	var d Daily
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	d.Flags(fs)
+	path := filepath.Join(dir, "leaderboard.json")
+	if d.Source("golf", "", time.Now()) != nil {
+		t.Errorf("Expected no source without -daily")
+	}
+	var buf bytes.Buffer
+	if err := d.Record(&buf, Result{Game: "golf"}); err != nil || buf.Len() != 0 {
+		t.Errorf("Expected nothing recorded without -daily but was %q %v", buf.String(), err)
+	}
+	if err := fs.Parse([]string{"-daily", "-player", "ann", "-leaderboard", path}); err != nil {
+		t.Fatal(err)
+	}
+	day := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	if d.Source("golf", "wrap true", day) == nil {
+		t.Fatalf("Expected a source for the deal of the day")
+	}
+	if err := d.Record(&buf, Result{Game: "golf", Won: true, Finished: day}); err != nil {
+		t.Fatalf("Expected the result recorded but was %v", err)
+	}
+	if !strings.Contains(buf.String(), "2026-10-19") || !strings.Contains(buf.String(), "ann") || !strings.Contains(buf.String(), "wrap true") {
+		t.Errorf("Expected the board for the day but was\n%s", buf.String())
+	}
+	entries, _ := LoadEntries(path)
+	if len(entries) != 1 || entries[0].Player != "ann" || entries[0].Seed == "" || entries[0].Rules != "wrap true" {
+		t.Errorf("Expected ann's entry with the seed but was %+v", entries)
+	}
+}
@@ -0,0 +1,110 @@
+package stats
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Result is the outcome of one game.
+type Result struct {
+	Game     string        `json:"game"`
+	Won      bool          `json:"won"`
+	Moves    int           `json:"moves"`
+	Elapsed  time.Duration `json:"elapsed"`
+	Score    int           `json:"score,omitempty"`
+	Finished time.Time     `json:"finished"`
+}
+
+// DefaultPath returns the file results are kept in under the user's config directory.
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "cardgames", "results.json"), nil
+}
+
+// Append adds a result to the end of the file at path, one result per line.
+// The directory is created if it is missing.
+func Append(path string, r Result) error {
+	return appendLine(path, r)
+}
+
+// appendLine adds v as a line of JSON to the end of the file at path.
+func appendLine(path string, v interface{}) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		f.Close()
+		return err
+	}
+	if _, err = f.Write(append(b, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Load reads every result in the file at path.  A missing file has no results.
+func Load(path string) ([]Result, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var results []Result
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var r Result
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			return nil, err
+		}
+		results = append(results, r)
+	}
+	return results, scanner.Err()
+}
+
+// Summary totals the results of one game.
+// Best and Fewest are only for games that were won and are 0 if none were.
+type Summary struct {
+	Played, Won int
+	Best        time.Duration
+	Fewest      int
+}
+
+// Summarize totals the results for the named game.
+func Summarize(results []Result, game string) Summary {
+	var s Summary
+	for _, r := range results {
+		if r.Game != game {
+			continue
+		}
+		s.Played++
+		if !r.Won {
+			continue
+		}
+		s.Won++
+		if s.Best == 0 || r.Elapsed < s.Best {
+			s.Best = r.Elapsed
+		}
+		if s.Fewest == 0 || r.Moves < s.Fewest {
+			s.Fewest = r.Moves
+		}
+	}
+	return s
+}
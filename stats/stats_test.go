@@ -0,0 +1,74 @@
+package stats
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestClock(t *testing.T) {
+	now := time.Unix(1000, 0)
+	c := NewClock()
+	c.Now = func() time.Time { return now }
+	if c.Elapsed() != 0 || c.Running() {
+		t.Errorf("Expected a new clock to be stopped at 0")
+	}
+	c.Start()
+	now = now.Add(5 * time.Second)
+	if c.Elapsed() != 5*time.Second {
+		t.Errorf("Expected 5s but was %v", c.Elapsed())
+	}
+	c.Toggle()
+	now = now.Add(time.Minute)
+	if c.Elapsed() != 5*time.Second || c.Running() {
+		t.Errorf("Expected the paused clock to stay at 5s but was %v", c.Elapsed())
+	}
+	c.Toggle()
+	now = now.Add(2 * time.Second)
+	c.Start()
+	if c.Elapsed() != 7*time.Second {
+		t.Errorf("Expected 7s but was %v", c.Elapsed())
+	}
+}
+
+func TestFormat(t *testing.T) {
+	if s := Format(75 * time.Second); s != "01:15" {
+		t.Errorf("Expected 01:15 but was %s", s)
+	}
+	if s := Format(time.Hour + 2*time.Minute + 3*time.Second); s != "1:02:03" {
+		t.Errorf("Expected 1:02:03 but was %s", s)
+	}
+}
+
+func TestResults(t *testing.T) {
+	dir, err := ioutil.TempDir("", "stats")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "sub", "results.json")
+	results, err := Load(path)
+	if err != nil || len(results) != 0 {
+		t.Errorf("Expected no results from a missing file but was %v %v", results, err)
+	}
+	for _, r := range []Result{
+		{Game: "klondike", Won: true, Moves: 120, Elapsed: 5 * time.Minute},
+		{Game: "klondike", Won: false, Moves: 40, Elapsed: time.Minute},
+		{Game: "klondike", Won: true, Moves: 100, Elapsed: 6 * time.Minute},
+		{Game: "yukon", Won: true, Moves: 10, Elapsed: time.Second},
+	} {
+		if err := Append(path, r); err != nil {
+			t.Fatalf("Append failed %v", err)
+		}
+	}
+	results, err = Load(path)
+	if err != nil || len(results) != 4 {
+		t.Fatalf("Expected 4 results but was %d %v", len(results), err)
+	}
+	s := Summarize(results, "klondike")
+	if s.Played != 3 || s.Won != 2 || s.Best != 5*time.Minute || s.Fewest != 100 {
+		t.Errorf("Expected 3 played 2 won best 5m fewest 100 but was %+v", s)
+	}
+}
@@ -0,0 +1,82 @@
+package engine
+
+import (
+	"sort"
+
+	"github.com/tmasterson/cardgames/generic"
+	"github.com/tmasterson/cardgames/tricks"
+)
+
+// Danger rates how much a computer player wants to get rid of a card when passing or discarding.
+func danger(c generic.Card) int {
+	switch {
+	case c.Rank == "Q" && c.Suit == "S":
+		return 100
+	case c.Suit == "S" && c.Rvalue > 12:
+		return 80 + c.Rvalue
+	case c.Suit == "H":
+		return 20 + c.Rvalue
+	}
+	return c.Rvalue
+}
+
+// ChoosePass picks the three most dangerous cards in a hand to pass.
+func ChoosePass(hand generic.Hand) generic.Hand {
+	cards := append(generic.Hand(nil), hand...)
+	sort.SliceStable(cards, func(i, j int) bool {
+		return danger(cards[i]) > danger(cards[j])
+	})
+	return cards[:3]
+}
+
+// ChoosePlay picks the card for a computer player.
+// It leads low, ducks under the winning card when following suit and
+// dumps the queen of spades or high hearts when it can not follow.
+func ChoosePlay(g *tricks.Game, seat int) generic.Card {
+	legal := g.LegalCards(seat)
+	sort.SliceStable(legal, func(i, j int) bool {
+		return legal[i].Rvalue < legal[j].Rvalue
+	})
+	led := g.Trick.Led()
+	if led == "" {
+		for _, c := range legal {
+			if c.Suit != "H" && !(c.Suit == "S" && c.Rvalue >= 12) {
+				return c
+			}
+		}
+		return legal[0]
+	}
+	if legal[0].Suit != led {
+		best := legal[0]
+		for _, c := range legal {
+			if danger(c) > danger(best) {
+				best = c
+			}
+		}
+		return best
+	}
+	high := 0
+	points := false
+	for _, c := range g.Trick.Cards {
+		if c.Suit == led && c.Rvalue > high {
+			high = c.Rvalue
+		}
+		if IsPoints(c) {
+			points = true
+		}
+	}
+	last := len(g.Trick.Cards) == len(g.Players)-1
+	if last && !points {
+		top := legal[len(legal)-1]
+		if !(top.Rank == "Q" && top.Suit == "S") {
+			return top
+		}
+	}
+	duck := legal[0]
+	for _, c := range legal {
+		if c.Rvalue < high {
+			duck = c
+		}
+	}
+	return duck
+}
@@ -0,0 +1,99 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/tmasterson/cardgames/generic"
+	"github.com/tmasterson/cardgames/tricks"
+)
+
+var names = []string{"South", "West", "North", "East"}
+
+func card(rank string, value int, suit string) generic.Card {
+	return generic.NewCard(rank, suit, "", value, 0, true)
+}
+
+func TestLegal(t *testing.T) {
+	g := tricks.NewGame(names, nil, Rules{})
+	g.Players[0].Hand = []generic.Card{card("2", 2, "C"), card("5", 5, "H"), card("Q", 12, "S")}
+	g.Players[1].Hand = []generic.Card{card("3", 3, "H"), card("Q", 12, "S"), card("4", 4, "D")}
+	if err := g.Legal(0, card("5", 5, "H")); err == nil {
+		t.Errorf("Expected the two of clubs to have to lead")
+	}
+	g.Play(0, card("2", 2, "C"))
+	if err := g.Legal(1, card("Q", 12, "S")); err == nil {
+		t.Errorf("Expected no points on the first trick")
+	}
+	if err := g.Legal(1, card("4", 4, "D")); err != nil {
+		t.Errorf("Expected 4D to be legal but got %v", err)
+	}
+	g.Tricks = 1
+	g.Trick = tricks.Trick{}
+	g.Turn = 0
+	if err := g.Legal(0, card("5", 5, "H")); err == nil {
+		t.Errorf("Expected hearts not to be broken")
+	}
+	g.Played = append(g.Played, card("3", 3, "H"))
+	if err := g.Legal(0, card("5", 5, "H")); err != nil {
+		t.Errorf("Expected hearts to be broken but got %v", err)
+	}
+}
+
+func TestScore(t *testing.T) {
+	g := tricks.NewGame(names, nil, Rules{})
+	g.Players[1].Taken = []generic.Card{card("Q", 12, "S"), card("2", 2, "H"), card("4", 4, "C")}
+	g.Players[2].Taken = []generic.Card{card("3", 3, "H")}
+	pts := g.ScoreHand()
+	if pts[0] != 0 || pts[1] != 14 || pts[2] != 1 || pts[3] != 0 {
+		t.Errorf("Expected 0 14 1 0 but was %v", pts)
+	}
+	deck := generic.NewDeck()
+	g.Players[1].Taken = nil
+	g.Players[2].Taken = deck.Cards
+	pts = g.Rules.Score(g)
+	if pts[0] != 26 || pts[1] != 26 || pts[2] != 0 || pts[3] != 26 {
+		t.Errorf("Expected North to shoot the moon but was %v", pts)
+	}
+}
+
+func TestChoosePass(t *testing.T) {
+	hand := generic.Hand{card("2", 2, "C"), card("Q", 12, "S"), card("A", 14, "H"), card("K", 13, "D"), card("A", 14, "S")}
+	sel := ChoosePass(hand)
+	if len(sel) != 3 || !sel.Contains(card("Q", 12, "S")) || !sel.Contains(card("A", 14, "S")) || !sel.Contains(card("A", 14, "H")) {
+		t.Errorf("Expected QS AS AH but was %v", sel)
+	}
+	if len(hand) != 5 {
+		t.Errorf("The hand should not be changed")
+	}
+}
+
+func TestComputerGame(t *testing.T) {
+	g := tricks.NewGame(names, []bool{true, true, true, true}, Rules{})
+	for n := 0; n < 8; n++ {
+		deck := generic.NewDeck()
+		deck.Shuffle()
+		g.Deal(&deck)
+		if dir := PassDirection(n); dir != tricks.PassNone {
+			sel := make([]generic.Hand, 4)
+			for i := range sel {
+				sel[i] = ChoosePass(g.Players[i].Hand)
+			}
+			if err := g.Pass(sel, dir); err != nil {
+				t.Fatalf("unexpected error passing %v", err)
+			}
+		}
+		g.Turn = FindLead(g)
+		for !g.HandOver() {
+			if _, err := g.Play(g.Turn, ChoosePlay(g, g.Turn)); err != nil {
+				t.Fatalf("computer made an illegal play %v", err)
+			}
+		}
+		total := 0
+		for _, p := range g.ScoreHand() {
+			total += p
+		}
+		if total != 26 && total != 78 {
+			t.Errorf("Expected 26 or 78 points in a hand but was %d", total)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+// Package engine holds the rules of hearts and the computer player so a game of hearts can be
+// played without a screen, such as by the lobby server.  The trick taking itself is done by
+// tricks.Game with Rules as its rules.
+package engine
+
+import (
+	"errors"
+
+	"github.com/tmasterson/cardgames/generic"
+	"github.com/tmasterson/cardgames/tricks"
+)
+
+// Rules implements tricks.Rules for the game of hearts.
+type Rules struct{}
+
+// IsPoints tells if a card scores points in hearts.
+func IsPoints(c generic.Card) bool {
+	return c.Suit == "H" || (c.Rank == "Q" && c.Suit == "S")
+}
+
+// OnlyPoints tells if every card is a point card.
+func onlyPoints(cards generic.Hand) bool {
+	for _, c := range cards {
+		if !IsPoints(c) {
+			return false
+		}
+	}
+	return true
+}
+
+// Broken tells if a heart has been played this hand.
+func broken(g *tricks.Game) bool {
+	for _, c := range g.Played {
+		if c.Suit == "H" {
+			return true
+		}
+	}
+	return false
+}
+
+// Legal enforces the hearts rules on top of following suit.
+// The two of clubs leads the first trick, no points may be played on the first trick
+// unless there is nothing else, and hearts may not be led until they are broken.
+func (Rules) Legal(g *tricks.Game, seat int, c generic.Card) error {
+	hand := g.Players[seat].Hand
+	leading := len(g.Trick.Cards) == 0
+	if g.Tricks == 0 {
+		if leading && (c.Rank != "2" || c.Suit != "C") {
+			return errors.New("the two of clubs must lead the first trick")
+		}
+		if IsPoints(c) && !onlyPoints(hand) {
+			return errors.New("no points may be played on the first trick")
+		}
+		return nil
+	}
+	if leading && c.Suit == "H" && !broken(g) {
+		for _, h := range hand {
+			if h.Suit != "H" {
+				return errors.New("hearts have not been broken")
+			}
+		}
+	}
+	return nil
+}
+
+// Score gives a point for each heart taken and 13 for the queen of spades.
+// A player who takes all 26 points has shot the moon and everyone else gets 26 instead.
+func (Rules) Score(g *tricks.Game) []int {
+	pts := make([]int, len(g.Players))
+	for i, p := range g.Players {
+		for _, c := range p.Taken {
+			switch {
+			case c.Suit == "H":
+				pts[i]++
+			case c.Rank == "Q" && c.Suit == "S":
+				pts[i] += 13
+			}
+		}
+	}
+	for i := range pts {
+		if pts[i] == 26 {
+			for j := range pts {
+				pts[j] = 26
+			}
+			pts[i] = 0
+			break
+		}
+	}
+	return pts
+}
+
+// PassNames are the names of the pass directions.
+var PassNames = []string{"left", "right", "across", "hold"}
+
+// PassDirection returns the direction to pass for the given hand number.
+// Hands go left, right, across and then hold with no passing.
+func PassDirection(hand int) int {
+	return hand % 4
+}
+
+// FindLead returns the seat holding the two of clubs.
+func FindLead(g *tricks.Game) int {
+	for i, p := range g.Players {
+		if p.Hand.Contains(generic.Card{Rank: "2", Suit: "C"}) {
+			return i
+		}
+	}
+	return 0
+}
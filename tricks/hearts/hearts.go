@@ -0,0 +1,322 @@
+// This program allows a user to play hearts against three computer players.
+// It uses the ncurses tcell created by Garrett D'Amore which can be gotten by
+// go get -u github.com/gdamore/tcell
+//
+// Three cards are passed left, right and across in turn with every fourth hand held.
+// The game ends when a player reaches the target score and the lowest score wins.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gdamore/tcell"
+	"github.com/tmasterson/cardgames/display"
+	"github.com/tmasterson/cardgames/generic"
+	"github.com/tmasterson/cardgames/tricks"
+	"github.com/tmasterson/cardgames/tricks/hearts/engine"
+)
+
+// Seat positions around the table.  Play goes clockwise so the seat to your left is west.
+var seatNames = []string{"South", "West", "North", "East"}
+
+// Define the boxes for easier manipulation
+var tableArea, scoreArea display.Box
+
+// Delay is the pause after each computer play so the play can be followed.
+var delay = 400 * time.Millisecond
+
+// Round holds the state of the current hand that is not part of the trick engine.
+// hand:  Number of hands played which sets the pass direction.
+// passing:  The human player is choosing cards to pass.
+// selected:  The cards chosen to pass.
+// msg:  The message for the status line.
+// target:  Score that ends the game.
+type round struct {
+	game     *tricks.Game
+	hand     int
+	passing  bool
+	selected generic.Hand
+	msg      string
+	target   int
+}
+
+// DrawScreen draws the screen putting all the boxes in place
+// s: The screen variable
+// style: The style for the screen
+//
+// Returns: Returns an error if one occurs otherwise nil
+func drawScreen(s tcell.Screen, style tcell.Style) error {
+	w, h := s.Size()
+	if w < 80 || h < 25 {
+		return errors.New("Screen size must be at least 80 by 25")
+	}
+	title := "Hearts"
+	display.PutString(s, w/2-len(title)/2, 0, style, title)
+	var err error
+	tableArea, err = display.MakeBox(s, "Table", 0, 2, 50, 14, style)
+	if err != nil {
+		return err
+	}
+	scoreArea, err = display.MakeBox(s, "Scores", tableArea.RightX+2, 2, tableArea.RightX+24, 9, style)
+	if err != nil {
+		return err
+	}
+	y := scoreArea.BotY + 2
+	help := []string{
+		"Press the letter under a",
+		"card to play it or to",
+		"choose it for passing.",
+		"Enter passes the cards.",
+		"Q quits.",
+	}
+	for _, line := range help {
+		display.PutString(s, scoreArea.LeftX, y, style, line)
+		y++
+	}
+	s.Show()
+	return nil
+}
+
+// SeatPosition returns where on the table a seat's card is shown.
+func seatPosition(seat int) (int, int) {
+	midX := (tableArea.LeftX + tableArea.RightX) / 2
+	midY := (tableArea.TopY + tableArea.BotY) / 2
+	switch seat {
+	case 1:
+		return tableArea.LeftX + 4, midY
+	case 2:
+		return midX - 1, tableArea.TopY + 2
+	case 3:
+		return tableArea.RightX - 10, midY
+	}
+	return midX - 1, tableArea.BotY - 2
+}
+
+// ShowTable prints the trick, the scores, the players hand and the status line.
+// s: Screen variable
+// r: The round being played
+// style: The style for the cards
+func showTable(s tcell.Screen, r *round, style tcell.Style) {
+	w, h := s.Size()
+	g := r.game
+	blank := strings.Repeat(" ", tableArea.RightX-tableArea.LeftX-1)
+	for y := tableArea.TopY + 1; y < tableArea.BotY; y++ {
+		display.PutString(s, tableArea.LeftX+1, y, style, blank)
+	}
+	trick := g.Trick
+	if len(trick.Cards) == 0 {
+		trick = g.LastTrick
+	}
+	for i := range g.Players {
+		x, y := seatPosition(i)
+		display.PutString(s, x, y, style, seatNames[i])
+	}
+	for i, c := range trick.Cards {
+		x, y := seatPosition((trick.Leader + i) % len(g.Players))
+		display.PutString(s, x+1, y+1, style, c.Rank+c.Suit)
+	}
+	for i, p := range g.Players {
+		display.PutString(s, scoreArea.LeftX+2, scoreArea.TopY+1+i, style, fmt.Sprintf("%-7s %3d %3d", seatNames[i], p.Score, len(p.Taken)/len(g.Players)))
+	}
+	display.PutString(s, scoreArea.LeftX+2, scoreArea.TopY+6, style, fmt.Sprintf("Pass: %-8s", engine.PassNames[engine.PassDirection(r.hand)]))
+	hy := tableArea.BotY + 2
+	for y := hy; y < hy+3; y++ {
+		display.PutString(s, 0, y, style, strings.Repeat(" ", w-1))
+	}
+	for i, c := range g.Players[0].Hand {
+		display.PutString(s, i*5+1, hy, style, c.Rank+c.Suit)
+		display.PutString(s, i*5+1, hy+1, style, string(rune('a'+i)))
+		if r.selected.Contains(c) {
+			display.PutString(s, i*5+1, hy+2, style, "^^")
+		}
+	}
+	display.PutString(s, 0, h-1, style, strings.Repeat(" ", w-1))
+	display.PutString(s, 0, h-1, style, r.msg)
+	s.Show()
+}
+
+// NewHand shuffles and deals a hand and starts passing if this hand passes.
+func (r *round) newHand() {
+	deck := generic.NewDeck()
+	deck.Shuffle()
+	r.game.Deal(&deck)
+	r.game.Players[0].Hand.SortBySuit(generic.AceHigh)
+	r.selected = r.selected[:0]
+	if engine.PassDirection(r.hand) == tricks.PassNone {
+		r.startPlay()
+		return
+	}
+	r.passing = true
+	r.msg = fmt.Sprintf("Choose three cards to pass %s.", engine.PassNames[engine.PassDirection(r.hand)])
+}
+
+// StartPlay gives the lead to the holder of the two of clubs.
+func (r *round) startPlay() {
+	r.passing = false
+	r.game.Turn = engine.FindLead(r.game)
+	r.msg = seatNames[r.game.Turn] + " leads."
+}
+
+// Pass passes the human player's selection along with the computer players' choices.
+func (r *round) pass() error {
+	if len(r.selected) != 3 {
+		return errors.New("choose exactly three cards to pass")
+	}
+	g := r.game
+	sel := make([]generic.Hand, len(g.Players))
+	sel[0] = r.selected
+	for i := 1; i < len(g.Players); i++ {
+		sel[i] = engine.ChoosePass(g.Players[i].Hand)
+	}
+	if err := g.Pass(sel, engine.PassDirection(r.hand)); err != nil {
+		return err
+	}
+	g.Players[0].Hand.SortBySuit(generic.AceHigh)
+	r.selected = r.selected[:0]
+	r.startPlay()
+	return nil
+}
+
+// ProcessKey handles the processing of key strokes for the human player.
+//
+// ev:  The event that that contains the key.
+// r:  The round the key acts on.
+//
+// returns: false if the player wants to quit
+func processKey(ev *tcell.EventKey, r *round) bool {
+	var err error
+	hand := r.game.Players[0].Hand
+	switch {
+	case ev.Key() == tcell.KeyEnter:
+		if r.passing {
+			err = r.pass()
+		}
+	case ev.Key() == tcell.KeyRune && unicode.ToUpper(ev.Rune()) == 'Q':
+		return false
+	case ev.Key() == tcell.KeyRune && ev.Rune() >= 'a' && int(ev.Rune()-'a') < len(hand):
+		c := hand[ev.Rune()-'a']
+		if r.passing {
+			if r.selected.Contains(c) {
+				r.selected.Remove(c)
+			} else if len(r.selected) < 3 {
+				r.selected = append(r.selected, c)
+			}
+			break
+		}
+		_, err = r.game.Play(0, c)
+		if err == nil {
+			r.msg = ""
+		}
+	}
+	if err != nil {
+		r.msg = err.Error() + "."
+	}
+	return true
+}
+
+// ComputerTurns plays for the computer players until it is the human's turn or the hand ends.
+func computerTurns(s tcell.Screen, r *round, style tcell.Style) {
+	g := r.game
+	for !r.passing && !g.HandOver() && g.Players[g.Turn].Computer {
+		showTable(s, r, style)
+		time.Sleep(delay)
+		g.Play(g.Turn, engine.ChoosePlay(g, g.Turn))
+	}
+}
+
+// EndHand scores the hand and tells if the game is over.
+func (r *round) endHand() bool {
+	pts := r.game.ScoreHand()
+	var parts []string
+	over := false
+	for i, p := range pts {
+		parts = append(parts, fmt.Sprintf("%s %d", seatNames[i], p))
+		if r.game.Players[i].Score >= r.target {
+			over = true
+		}
+	}
+	r.msg = "Hand scores: " + strings.Join(parts, ", ") + ". Press any key."
+	r.hand++
+	return over
+}
+
+// PlayGame is the main function that handles all aspects of the game.
+//
+// s: Screen variable.
+// style: The style for the screen.
+//
+// returns: The game so the final scores can be reported, and false if the player quit.
+func playGame(s tcell.Screen, target int, style tcell.Style) (*tricks.Game, bool) {
+	g := tricks.NewGame(seatNames, []bool{false, true, true, true}, engine.Rules{})
+	r := &round{game: g, target: target}
+	r.newHand()
+	for {
+		computerTurns(s, r, style)
+		if g.HandOver() {
+			over := r.endHand()
+			showTable(s, r, style)
+			s.PollEvent()
+			if over {
+				return g, true
+			}
+			r.newHand()
+			continue
+		}
+		showTable(s, r, style)
+		ev := s.PollEvent()
+		switch ev := ev.(type) {
+		case *tcell.EventKey:
+			if ev.Key() == tcell.KeyCtrlL {
+				s.Sync()
+			} else if !processKey(ev, r) {
+				return g, false
+			}
+		}
+	}
+}
+
+func main() {
+	target := flag.Int("target", 100, "Score that ends the game")
+	flag.Parse()
+	tcell.SetEncodingFallback(tcell.EncodingFallbackASCII)
+	s, e := tcell.NewScreen()
+	if e != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", e)
+		os.Exit(1)
+	}
+	if e = s.Init(); e != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", e)
+		os.Exit(1)
+	}
+	s.Clear()
+	s.HideCursor()
+	if err := drawScreen(s, tcell.StyleDefault); err != nil {
+		s.Fini()
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	g, finished := playGame(s, *target, tcell.StyleDefault)
+	s.Fini()
+	if !finished {
+		fmt.Println("You quit. Better luck next time.")
+		return
+	}
+	best := 0
+	for i, p := range g.Players {
+		fmt.Printf("%-6s %d\n", seatNames[i], p.Score)
+		if p.Score < g.Players[best].Score {
+			best = i
+		}
+	}
+	if best == 0 {
+		fmt.Println("Congratulations you won!")
+	} else {
+		fmt.Printf("%s won. Better luck next time.\n", seatNames[best])
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell"
+	"github.com/tmasterson/cardgames/tricks"
+	"github.com/tmasterson/cardgames/tricks/hearts/engine"
+)
+
+func TestProcessKey(t *testing.T) {
+	g := tricks.NewGame(seatNames, []bool{false, true, true, true}, engine.Rules{})
+	r := &round{game: g, target: 100}
+	r.newHand()
+	if !r.passing {
+		t.Fatalf("Expected the first hand to pass")
+	}
+	for _, k := range "abb" {
+		processKey(tcell.NewEventKey(tcell.KeyRune, k, tcell.ModNone), r)
+	}
+	if len(r.selected) != 1 {
+		t.Errorf("Expected 1 card selected but was %d", len(r.selected))
+	}
+	processKey(tcell.NewEventKey(tcell.KeyEnter, ' ', tcell.ModNone), r)
+	if !r.passing {
+		t.Errorf("Should not pass with only 1 card selected")
+	}
+	for _, k := range "bc" {
+		processKey(tcell.NewEventKey(tcell.KeyRune, k, tcell.ModNone), r)
+	}
+	processKey(tcell.NewEventKey(tcell.KeyEnter, ' ', tcell.ModNone), r)
+	if r.passing || len(g.Players[0].Hand) != 13 {
+		t.Errorf("Expected the pass to be done")
+	}
+	if processKey(tcell.NewEventKey(tcell.KeyRune, 'q', tcell.ModNone), r) {
+		t.Errorf("Expected q to quit")
+	}
+}
+
+func TestDrawScreen(t *testing.T) {
+	s := tcell.NewSimulationScreen("")
+	if e := s.Init(); e != nil {
+		t.Fatalf("Failed to initialize screen: %v", e)
+	}
+	defer s.Fini()
+	s.SetSize(30, 10)
+	if err := drawScreen(s, tcell.StyleDefault); err == nil {
+		t.Errorf("Expected an error here")
+	}
+	s.SetSize(80, 25)
+	if err := drawScreen(s, tcell.StyleDefault); err != nil {
+		t.Errorf("There should be no errors")
+	}
+	r := &round{game: tricks.NewGame(seatNames, []bool{false, true, true, true}, engine.Rules{}), target: 100}
+	r.newHand()
+	showTable(s, r, tcell.StyleDefault)
+}
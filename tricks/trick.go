@@ -0,0 +1,244 @@
+// Package tricks is a set of types and functions useful for all trick-taking games
+// such as hearts, spades or whist.
+//
+// The Game type handles dealing, passing, follow suit enforcement and working out who
+// wins each trick.  Anything specific to a game such as which cards may be led or how a
+// hand is scored is supplied through the Rules interface.
+package tricks
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/tmasterson/cardgames/generic"
+)
+
+// Directions cards can be passed before play.
+const (
+	PassLeft = iota
+	PassRight
+	PassAcross
+	PassNone
+)
+
+// Rules is the interface a game uses to add its own restrictions and scoring.
+type Rules interface {
+	// Legal is called once the card has been checked to be in the hand and to follow suit.
+	// It returns an error describing why the card can not be played or nil.
+	Legal(g *Game, seat int, c generic.Card) error
+	// Score returns the points each seat earned in the hand that just finished.
+	Score(g *Game) []int
+}
+
+// Player holds a seat at the table.
+// Taken is the cards won in tricks this hand.
+// Score is the running total for the game.
+type Player struct {
+	Name     string
+	Hand     generic.Hand
+	Taken    generic.Hand
+	Score    int
+	Computer bool
+}
+
+// Trick holds the cards played to a trick in the order they were played starting with the leader.
+type Trick struct {
+	Leader int
+	Cards  []generic.Card
+}
+
+// Game holds the state of a trick-taking game.
+// Trump is the trump suit or an empty string for no trump.
+// Turn is the seat to play next.
+// Played is every card played so far this hand.
+// LastTrick is the most recently completed trick so it can be shown.
+type Game struct {
+	Players   []Player
+	Trump     string
+	Trick     Trick
+	LastTrick Trick
+	Tricks    int
+	Turn      int
+	Played    generic.Hand
+	Rules     Rules
+}
+
+// NewGame returns a game with a seat for each name.
+// Seats for which computer is true are played by the computer.
+func NewGame(names []string, computer []bool, rules Rules) *Game {
+	g := &Game{Rules: rules}
+	for i, n := range names {
+		g.Players = append(g.Players, Player{Name: n, Computer: i < len(computer) && computer[i]})
+	}
+	return g
+}
+
+// Led returns the suit led to the trick or an empty string if nothing has been played.
+func (t *Trick) Led() string {
+	if len(t.Cards) == 0 {
+		return ""
+	}
+	return t.Cards[0].Suit
+}
+
+// Winner returns the seat that won the trick.
+// The highest trump wins, otherwise the highest card of the suit led.
+// n is the number of seats at the table.
+func (t *Trick) Winner(trump string, n int) int {
+	best := 0
+	for i, c := range t.Cards {
+		b := t.Cards[best]
+		switch {
+		case c.Suit == b.Suit && c.Rvalue > b.Rvalue:
+			best = i
+		case trump != "" && c.Suit == trump && b.Suit != trump:
+			best = i
+		}
+	}
+	return (t.Leader + best) % n
+}
+
+// Deal deals the deck out evenly to all players face up and clears the last hand.
+// The caller is expected to shuffle the deck first.
+func (g *Game) Deal(deck *generic.Deck) {
+	n := len(deck.Cards) / len(g.Players)
+	for i := range g.Players {
+		g.Players[i].Hand = deck.Deal(n, n)
+		g.Players[i].Taken = g.Players[i].Taken[:0]
+	}
+	g.Played = g.Played[:0]
+	g.Trick = Trick{}
+	g.LastTrick = Trick{}
+	g.Tricks = 0
+}
+
+// PassTarget returns the seat that receives the cards from seat when passing in direction dir.
+func (g *Game) PassTarget(seat, dir int) int {
+	n := len(g.Players)
+	switch dir {
+	case PassLeft:
+		return (seat + 1) % n
+	case PassRight:
+		return (seat + n - 1) % n
+	case PassAcross:
+		return (seat + n/2) % n
+	}
+	return seat
+}
+
+// Pass moves the selected cards from each seat to the seat in direction dir.
+// selections holds the cards chosen by each seat and every seat must pass the same number.
+func (g *Game) Pass(selections []generic.Hand, dir int) error {
+	if dir == PassNone {
+		return nil
+	}
+	if len(selections) != len(g.Players) {
+		return fmt.Errorf("expected a selection for %d seats but got %d", len(g.Players), len(selections))
+	}
+	for i, sel := range selections {
+		if len(sel) != len(selections[0]) {
+			return errors.New("every seat must pass the same number of cards")
+		}
+		for _, c := range sel {
+			if !g.Players[i].Hand.Contains(c) {
+				return fmt.Errorf("%s does not hold %s", g.Players[i].Name, c.Rank+c.Suit)
+			}
+		}
+	}
+	// copy the selections first as they may share storage with the hands
+	passed := make([]generic.Hand, len(selections))
+	for i, sel := range selections {
+		passed[i] = append(generic.Hand(nil), sel...)
+	}
+	for i, sel := range passed {
+		for _, c := range sel {
+			g.Players[i].Hand.Remove(c)
+		}
+	}
+	for i, sel := range passed {
+		t := g.PassTarget(i, dir)
+		g.Players[t].Hand = append(g.Players[t].Hand, sel...)
+	}
+	return nil
+}
+
+// Legal checks that seat may play card c now.
+// Returns nil if the play is legal otherwise an error explaining why not.
+func (g *Game) Legal(seat int, c generic.Card) error {
+	if seat != g.Turn {
+		return fmt.Errorf("it is not %s's turn", g.Players[seat].Name)
+	}
+	hand := g.Players[seat].Hand
+	if !hand.Contains(c) {
+		return fmt.Errorf("%s is not in the hand", c.Rank+c.Suit)
+	}
+	led := g.Trick.Led()
+	if led != "" && c.Suit != led && hand.HasSuit(led) {
+		return fmt.Errorf("you must follow suit")
+	}
+	if g.Rules != nil {
+		return g.Rules.Legal(g, seat, c)
+	}
+	return nil
+}
+
+// LegalCards returns all the cards seat may play now.
+func (g *Game) LegalCards(seat int) generic.Hand {
+	var cards generic.Hand
+	for _, c := range g.Players[seat].Hand {
+		if g.Legal(seat, c) == nil {
+			cards = append(cards, c)
+		}
+	}
+	return cards
+}
+
+// Play plays card c for seat.
+// When the trick is complete the winner takes the cards and leads the next trick.
+//
+// returns: true if the play completed a trick and any error
+func (g *Game) Play(seat int, c generic.Card) (bool, error) {
+	if err := g.Legal(seat, c); err != nil {
+		return false, err
+	}
+	n := len(g.Players)
+	if len(g.Trick.Cards) == 0 {
+		g.Trick.Leader = seat
+	}
+	g.Players[seat].Hand.Remove(c)
+	g.Trick.Cards = append(g.Trick.Cards, c)
+	g.Played = append(g.Played, c)
+	g.Turn = (seat + 1) % n
+	if len(g.Trick.Cards) < n {
+		return false, nil
+	}
+	w := g.Trick.Winner(g.Trump, n)
+	g.Players[w].Taken = append(g.Players[w].Taken, g.Trick.Cards...)
+	g.LastTrick = g.Trick
+	g.Trick = Trick{Leader: w}
+	g.Turn = w
+	g.Tricks++
+	return true, nil
+}
+
+// HandOver tells if every card has been played.
+func (g *Game) HandOver() bool {
+	for _, p := range g.Players {
+		if len(p.Hand) > 0 {
+			return false
+		}
+	}
+	return len(g.Trick.Cards) == 0
+}
+
+// ScoreHand adds the points for the finished hand to each player and returns them.
+func (g *Game) ScoreHand() []int {
+	if g.Rules == nil {
+		return make([]int, len(g.Players))
+	}
+	pts := g.Rules.Score(g)
+	for i := range g.Players {
+		g.Players[i].Score += pts[i]
+	}
+	return pts
+}
@@ -0,0 +1,117 @@
+package tricks
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/tmasterson/cardgames/generic"
+)
+
+func card(rank string, value int, suit string) generic.Card {
+	return generic.NewCard(rank, suit, "", value, 0, true)
+}
+
+// noHearts is a test rule that forbids leading hearts to the first trick and scores a point a trick.
+type noHearts struct{}
+
+func (noHearts) Legal(g *Game, seat int, c generic.Card) error {
+	if g.Tricks == 0 && len(g.Trick.Cards) == 0 && c.Suit == "H" {
+		return errors.New("no hearts")
+	}
+	return nil
+}
+
+func (noHearts) Score(g *Game) []int {
+	pts := make([]int, len(g.Players))
+	for i, p := range g.Players {
+		pts[i] = len(p.Taken) / len(g.Players)
+	}
+	return pts
+}
+
+func TestWinner(t *testing.T) {
+	tr := Trick{Leader: 2, Cards: []generic.Card{card("9", 9, "C"), card("K", 13, "C"), card("A", 14, "D"), card("2", 2, "C")}}
+	if w := tr.Winner("", 4); w != 3 {
+		t.Errorf("Expected 3 but was %d", w)
+	}
+	if w := tr.Winner("D", 4); w != 0 {
+		t.Errorf("Expected 0 with diamonds trump but was %d", w)
+	}
+	tr.Cards[3] = card("3", 3, "D")
+	if w := tr.Winner("D", 4); w != 0 {
+		t.Errorf("Expected the highest trump to win but was %d", w)
+	}
+}
+
+func TestDeal(t *testing.T) {
+	g := NewGame([]string{"a", "b", "c", "d"}, []bool{false, true, true, true}, nil)
+	deck := generic.NewDeck()
+	deck.Shuffle()
+	g.Deal(&deck)
+	for i, p := range g.Players {
+		if len(p.Hand) != 13 {
+			t.Errorf("Expected 13 cards for seat %d but was %d", i, len(p.Hand))
+		}
+	}
+	if g.Players[0].Computer || !g.Players[3].Computer {
+		t.Errorf("Computer seats were not set")
+	}
+}
+
+func TestPass(t *testing.T) {
+	g := NewGame([]string{"a", "b", "c", "d"}, nil, nil)
+	for i := range g.Players {
+		g.Players[i].Hand = []generic.Card{card("2", 2, "CDHS"[i:i+1]), card("3", 3, "CDHS"[i:i+1])}
+	}
+	if g.PassTarget(0, PassLeft) != 1 || g.PassTarget(0, PassRight) != 3 || g.PassTarget(1, PassAcross) != 3 {
+		t.Errorf("Pass targets were wrong")
+	}
+	sel := []generic.Hand{g.Players[0].Hand[:1:1], g.Players[1].Hand[:1:1], g.Players[2].Hand[:1:1], g.Players[3].Hand[:1:1]}
+	if err := g.Pass(sel, PassRight); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !g.Players[0].Hand.Contains(card("2", 2, "D")) || len(g.Players[0].Hand) != 2 {
+		t.Errorf("Expected seat 0 to get the 2D from seat 1 but has %v", g.Players[0].Hand)
+	}
+	sel[0] = generic.Hand{card("A", 14, "S")}
+	if err := g.Pass(sel, PassLeft); err == nil {
+		t.Errorf("Expected an error passing a card not held")
+	}
+}
+
+func TestPlay(t *testing.T) {
+	g := NewGame([]string{"a", "b"}, nil, noHearts{})
+	g.Players[0].Hand = []generic.Card{card("2", 2, "H"), card("5", 5, "C")}
+	g.Players[1].Hand = []generic.Card{card("K", 13, "C"), card("3", 3, "H")}
+	if _, err := g.Play(1, card("K", 13, "C")); err == nil {
+		t.Errorf("Expected an error playing out of turn")
+	}
+	if _, err := g.Play(0, card("2", 2, "H")); err == nil {
+		t.Errorf("Expected the rules hook to refuse a heart lead")
+	}
+	if done, err := g.Play(0, card("5", 5, "C")); done || err != nil {
+		t.Fatalf("Expected the trick to continue %v %v", done, err)
+	}
+	if _, err := g.Play(1, card("3", 3, "H")); err == nil {
+		t.Errorf("Expected an error for not following suit")
+	}
+	if len(g.LegalCards(1)) != 1 {
+		t.Errorf("Expected only 1 legal card but was %v", g.LegalCards(1))
+	}
+	done, err := g.Play(1, card("K", 13, "C"))
+	if !done || err != nil {
+		t.Fatalf("Expected the trick to be done %v %v", done, err)
+	}
+	if g.Turn != 1 || len(g.Players[1].Taken) != 2 || g.Tricks != 1 {
+		t.Errorf("Expected seat 1 to win the trick and lead")
+	}
+	g.Play(1, card("3", 3, "H"))
+	g.Play(0, card("2", 2, "H"))
+	if !g.HandOver() {
+		t.Errorf("Expected the hand to be over")
+	}
+	pts := g.ScoreHand()
+	if pts[1] != 2 || g.Players[1].Score != 2 {
+		t.Errorf("Expected 2 points for seat 1 but was %v", pts)
+	}
+}